@@ -0,0 +1,201 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"pi/control"
+	"pi/dev"
+	"pi/event"
+	"pi/log"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Device is the device of the alert state events.
+const Device = "alert"
+
+// States of a rule.
+const (
+	StateOK      = "ok"
+	StatePending = "pending"
+	StateFiring  = "firing"
+)
+
+// blinkPeriod is the time an LED stays on or off in a blink action.
+const blinkPeriod = 250 * time.Millisecond
+
+// Engine evaluates the rules on the readings of a bus. A rule is pending
+// once its threshold is crossed and fires after For, in its window and
+// out of its cooldown. Firing and resolving publish an event named after
+// the rule on device alert, value 1 or 0, and are logged.
+type Engine struct {
+	ctl   *control.Controller
+	bus   *event.Bus
+	clock func() time.Time
+
+	mu    sync.Mutex
+	rules []*rule
+}
+
+// rule is the state of a Rule.
+type rule struct {
+	Rule
+	state string
+	since time.Time // crossing of the threshold
+	fired time.Time
+}
+
+// New creates a new Engine running the actions with ctl, windows are
+// checked on clock.
+func New(ctl *control.Controller, bus *event.Bus, clock func() time.Time) *Engine {
+	return &Engine{ctl: ctl, bus: bus, clock: clock}
+}
+
+// SetRules replaces the rules, unchanged ones keep their state. Firing
+// rules which are removed or changed resolve.
+func (e *Engine) SetRules(rules []Rule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	old := make(map[string]*rule, len(e.rules))
+	for _, r := range e.rules {
+		old[r.Name] = r
+	}
+	e.rules = make([]*rule, 0, len(rules))
+	for _, r := range rules {
+		if o, ok := old[r.Name]; ok && reflect.DeepEqual(o.Rule, r) {
+			e.rules = append(e.rules, o)
+			delete(old, r.Name)
+			continue
+		}
+		e.rules = append(e.rules, &rule{Rule: r, state: StateOK})
+	}
+	for _, r := range old {
+		if r.state == StateFiring {
+			e.resolve(r, "removed")
+		}
+	}
+}
+
+// States returns the state of the rules by name.
+func (e *Engine) States() map[string]string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	states := make(map[string]string, len(e.rules))
+	for _, r := range e.rules {
+		states[r.Name] = r.state
+	}
+	return states
+}
+
+// Run evaluates the readings published on bus until ctx is done.
+func (e *Engine) Run(ctx context.Context) error {
+	events, cancel := e.bus.Subscribe(64)
+	defer cancel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev := <-events:
+			if ev.Unit != "" {
+				e.evaluate(ev)
+			}
+		}
+	}
+}
+
+// evaluate updates the rules of the reading ev, durations are measured on
+// the event times.
+func (e *Engine) evaluate(ev event.Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range e.rules {
+		if r.Device != ev.Device || r.Reading != ev.Name {
+			continue
+		}
+		crossed, cleared := r.test(ev.Value)
+		switch r.state {
+		case StateOK:
+			if crossed {
+				r.state, r.since = StatePending, ev.Time
+			}
+		case StatePending:
+			if !crossed {
+				r.state = StateOK
+			}
+		case StateFiring:
+			if cleared {
+				e.resolve(r, fmt.Sprintf("%s %g", ev.Name, ev.Value))
+			}
+		}
+		if r.state == StatePending && ev.Time.Sub(r.since) >= r.For &&
+			(r.fired.IsZero() || ev.Time.Sub(r.fired) >= r.Cooldown) &&
+			(r.window == nil || r.window.contains(e.clock())) {
+			r.state, r.fired = StateFiring, ev.Time
+			log.Default().Warnf("alert %s firing: %s/%s %g %s", r.Name, ev.Device, ev.Name, ev.Value, ev.Unit)
+			e.bus.Publish(event.Event{Device: Device, Name: r.Name, Value: 1})
+			go e.run(r.Actions, ev.Value)
+		}
+	}
+}
+
+// test reports whether v crosses the threshold of r, or went back past
+// the hysteresis.
+func (r *rule) test(v float64) (crossed, cleared bool) {
+	if r.Above != nil {
+		return v > *r.Above, v < *r.Above-r.Hysteresis
+	}
+	return v < *r.Below, v > *r.Below+r.Hysteresis
+}
+
+// resolve sets r back to ok.
+func (e *Engine) resolve(r *rule, why string) {
+	r.state = StateOK
+	log.Default().Infof("alert %s resolved: %s", r.Name, why)
+	e.bus.Publish(event.Event{Device: Device, Name: r.Name, Value: 0})
+}
+
+// run runs actions, the errors are logged.
+func (e *Engine) run(actions []Action, value float64) {
+	for _, a := range actions {
+		var err error
+		switch a.Type {
+		case ActionBlink:
+			err = e.blink(a)
+		case ActionOn, ActionOff:
+			_, err = e.ctl.Switch(a.Device, a.Type, control.DefaultHold)
+		case ActionAlarm:
+			_, err = e.ctl.Play(a.Device, Alarm)
+		case ActionText:
+			text := strings.Replace(a.Text, "{value}", fmt.Sprintf("%.1f", value), -1)
+			err = e.ctl.ShowText(a.Device, dev.PosTopLeft, text, control.DefaultHold)
+		}
+		if err != nil {
+			log.Default().Warnf("alert action %s %s error: %v", a.Type, a.Device, err)
+		}
+	}
+}
+
+// blink toggles the LED of a twice per blink in the background, holding it
+// from its worker meanwhile.
+func (e *Engine) blink(a Action) error {
+	count := a.Count
+	if count == 0 {
+		count = DefaultBlinks
+	}
+	hold := time.Duration(2*count)*blinkPeriod + control.DefaultHold
+	if _, err := e.ctl.Switch(a.Device, "toggle", hold); err != nil {
+		return err
+	}
+	go func() {
+		for i := 1; i < 2*count; i++ {
+			time.Sleep(blinkPeriod)
+			if _, err := e.ctl.Switch(a.Device, "toggle", hold); err != nil {
+				log.Default().Warnf("alert blink %s error: %v", a.Device, err)
+				return
+			}
+		}
+	}()
+	return nil
+}
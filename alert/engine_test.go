@@ -0,0 +1,213 @@
+package alert
+
+import (
+	"context"
+	"pi/control"
+	"pi/dev"
+	"pi/event"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// fakeLED counts its switches.
+type fakeLED struct {
+	mu       sync.Mutex
+	status   int
+	switches int
+}
+
+func (l *fakeLED) Name() string                   { return "led2" }
+func (l *fakeLED) Init(ctx context.Context) error { return nil }
+func (l *fakeLED) Close() error                   { return nil }
+func (l *fakeLED) Health() dev.Health             { return dev.Health{State: dev.HealthPresent} }
+
+func (l *fakeLED) set(status int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.status, l.switches = status, l.switches+1
+	return nil
+}
+
+func (l *fakeLED) On() error     { return l.set(1) }
+func (l *fakeLED) Off() error    { return l.set(0) }
+func (l *fakeLED) Toggle() error { return l.set(l.Status() ^ 1) }
+func (l *fakeLED) Status() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.status
+}
+
+// fakeBuzzer sends the notes played.
+type fakeBuzzer struct {
+	notes chan float64
+}
+
+func (b *fakeBuzzer) Name() string                   { return "buzzer" }
+func (b *fakeBuzzer) Init(ctx context.Context) error { return nil }
+func (b *fakeBuzzer) Close() error                   { return nil }
+func (b *fakeBuzzer) Health() dev.Health             { return dev.Health{State: dev.HealthPresent} }
+
+func (b *fakeBuzzer) Play(ctx context.Context, hz float64, d time.Duration) error {
+	b.notes <- hz
+	return nil
+}
+
+func TestEngine(t *testing.T) {
+	led := &fakeLED{}
+	health := dev.NewHealthRegistry()
+	health.Add("led2", led)
+	bus := event.New()
+	alerts, cancel := bus.Subscribe(16)
+	defer cancel()
+	clock := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	e := New(control.New(health, bus, nil), bus, func() time.Time { return clock })
+	above := 30.0
+	rule := Rule{
+		Name: "kitchen-hot", Device: "temp", Reading: "temperature",
+		Above: &above, For: 2 * time.Minute, Hysteresis: 1, Cooldown: 10 * time.Minute,
+		Window: "08:00-22:00", Actions: []Action{{Type: ActionOn, Device: "led2"}},
+	}
+	if err := rule.check(); err != nil {
+		t.Fatal(err)
+	}
+	e.SetRules([]Rule{rule})
+
+	t0 := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	steps := []struct {
+		minute float64
+		value  float64
+		state  string
+	}{
+		{0, 31, StatePending},
+		{1, 29.5, StateOK},
+		{2, 31, StatePending},
+		{4, 31, StateFiring},
+		{5, 29.5, StateFiring}, // within the hysteresis
+		{6, 28.9, StateOK},
+		{7, 31, StatePending},
+		{10, 31, StatePending}, // cooling down since minute 4
+		{14, 31, StateFiring},
+	}
+	for _, s := range steps {
+		at := t0.Add(time.Duration(s.minute * float64(time.Minute)))
+		e.evaluate(event.Event{Device: "temp", Name: "temperature", Value: s.value, Unit: "°C", Time: at})
+		if got := e.States()["kitchen-hot"]; got != s.state {
+			t.Fatalf("minute %v, %v: %s, want %s", s.minute, s.value, got, s.state)
+		}
+	}
+	var changes []float64
+	for len(changes) < 3 {
+		select {
+		case ev := <-alerts:
+			if ev.Device == Device && ev.Name == "kitchen-hot" {
+				changes = append(changes, ev.Value)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("alert events %v", changes)
+		}
+	}
+	if changes[0] != 1 || changes[1] != 0 || changes[2] != 1 {
+		t.Errorf("alert events %v", changes)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for led.Status() != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if led.Status() != 1 {
+		t.Error("led2 not switched on")
+	}
+
+	// Out of the window the rule stays pending, an unchanged rule keeps
+	// its state on reload and a removed one resolves.
+	clock = time.Date(2026, 10, 19, 23, 0, 0, 0, time.Local)
+	e.SetRules([]Rule{rule})
+	if got := e.States()["kitchen-hot"]; got != StateFiring {
+		t.Errorf("reloaded rule %s", got)
+	}
+	e.evaluate(event.Event{Device: "temp", Name: "temperature", Value: 20, Unit: "°C", Time: t0.Add(30 * time.Minute)})
+	e.evaluate(event.Event{Device: "temp", Name: "temperature", Value: 35, Unit: "°C", Time: t0.Add(60 * time.Minute)})
+	if got := e.States()["kitchen-hot"]; got != StatePending {
+		t.Errorf("out of the window %s", got)
+	}
+	e.SetRules(nil)
+	if len(e.States()) != 0 {
+		t.Errorf("states %v", e.States())
+	}
+}
+
+func TestAlarm(t *testing.T) {
+	buzzer := &fakeBuzzer{notes: make(chan float64, len(Alarm))}
+	health := dev.NewHealthRegistry()
+	health.Add("buzzer", buzzer)
+	bus := event.New()
+	e := New(control.New(health, bus, nil), bus, time.Now)
+	above := 30.0
+	rule := Rule{
+		Name: "hot", Device: "temp", Reading: "temperature", Above: &above,
+		Actions: []Action{{Type: ActionAlarm, Device: "buzzer"}},
+	}
+	if err := rule.check(); err != nil {
+		t.Fatal(err)
+	}
+	e.SetRules([]Rule{rule})
+	e.evaluate(event.Event{Device: "temp", Name: "temperature", Value: 31, Unit: "°C", Time: time.Now()})
+	if got := e.States()["hot"]; got != StateFiring {
+		t.Fatalf("state %s", got)
+	}
+	for i, n := range Alarm {
+		select {
+		case hz := <-buzzer.notes:
+			if hz != n.Hz {
+				t.Errorf("note %d: %v Hz, want %v", i, hz, n.Hz)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("alarm played %d notes of %d", i, len(Alarm))
+		}
+	}
+}
+
+func TestNewRules(t *testing.T) {
+	v := viper.New()
+	v.Set("alerts", []map[string]interface{}{{
+		"name": "kitchen-hot", "device": "temp", "reading": "temperature",
+		"above": 30, "for": "2m", "cooldown": "10m", "window": "22:00-06:00",
+		"actions": []map[string]interface{}{{"type": "blink", "device": "led2"}},
+	}})
+	rules, err := NewRules(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rules[0]
+	if *r.Above != 30 || r.For != 2*time.Minute || r.Actions[0].Type != ActionBlink || r.window == nil {
+		t.Errorf("rule %+v", r)
+	}
+	for _, hour := range []int{23, 3} {
+		if !r.window.contains(time.Date(2026, 1, 1, hour, 0, 0, 0, time.Local)) {
+			t.Errorf("%d:00 not in %s", hour, r.Window)
+		}
+	}
+	if r.window.contains(time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)) {
+		t.Errorf("12:00 in %s", r.Window)
+	}
+
+	for _, c := range []struct {
+		key   string
+		value interface{}
+		want  string
+	}{
+		{"below", 10, "one of above and below"},
+		{"window", "8h-22h", "HH:MM-HH:MM"},
+		{"actions", []map[string]interface{}{{"type": "explode", "device": "led2"}}, "unknown type"},
+	} {
+		bad := map[string]interface{}{"name": "n", "device": "temp", "reading": "temperature", "above": 30}
+		bad[c.key] = c.value
+		v.Set("alerts", []map[string]interface{}{bad})
+		if _, err := NewRules(v); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: %v, want %s", c.key, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Package alert fires the actions of threshold rules on the readings of
+// the sensors, eg. blink LED2 when the kitchen is above 30°C for 2 minutes.
+package alert
+
+import (
+	"fmt"
+	"pi/control"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Action types.
+const (
+	// ActionBlink toggles an LED Count times.
+	ActionBlink = "blink"
+	// ActionOn and ActionOff switch an LED.
+	ActionOn  = "on"
+	ActionOff = "off"
+	// ActionAlarm plays Alarm on a buzzer.
+	ActionAlarm = "alarm"
+	// ActionText shows Text on the OLED, {value} is replaced by the reading.
+	ActionText = "text"
+)
+
+// DefaultBlinks is the Count of a blink action without one.
+const DefaultBlinks = 5
+
+// Alarm is the melody of the alarm action.
+var Alarm = []control.Note{
+	{Hz: 880, Duration: 150 * time.Millisecond},
+	{Hz: 0, Duration: 50 * time.Millisecond},
+	{Hz: 880, Duration: 150 * time.Millisecond},
+	{Hz: 0, Duration: 50 * time.Millisecond},
+	{Hz: 440, Duration: 400 * time.Millisecond},
+}
+
+// Action is run on the device of the controller when a rule fires.
+type Action struct {
+	Type   string
+	Device string
+	Text   string
+	Count  int
+}
+
+// Rule is an entry of the alerts section, eg.
+//
+//	name: kitchen-hot
+//	device: temp
+//	reading: temperature
+//	above: 30
+//	for: "2m"
+//	hysteresis: 0.5
+//	cooldown: "10m"
+//	window: "08:00-22:00"
+//	actions:
+//	  - {type: blink, device: led2}
+//	  - {type: alarm, device: buzzer}
+//	  - {type: text, device: oled, text: "Kitchen {value} C"}
+type Rule struct {
+	Name    string
+	Device  string
+	Reading string
+	// Above or Below is the threshold, exactly one of them.
+	Above *float64
+	Below *float64
+	// For is how long the threshold is crossed before the rule fires.
+	For time.Duration
+	// Hysteresis is how far back across the threshold the reading goes
+	// for the alert to resolve.
+	Hysteresis float64
+	// Cooldown is the least time between two firings.
+	Cooldown time.Duration
+	// Window is the time of day the rule fires, on the DS3231 clock, eg.
+	// 22:00-06:00, always when empty.
+	Window  string
+	Actions []Action
+
+	window *window
+}
+
+// window is a time of day range in minutes, to before from wrapping
+// midnight.
+type window struct {
+	from, to int
+}
+
+func parseWindow(s string) (*window, error) {
+	var h1, m1, h2, m2 int
+	if _, err := fmt.Sscanf(s, "%d:%d-%d:%d", &h1, &m1, &h2, &m2); err != nil {
+		return nil, fmt.Errorf("window %q is not HH:MM-HH:MM", s)
+	}
+	for _, v := range []struct{ h, m int }{{h1, m1}, {h2, m2}} {
+		if v.h < 0 || v.h > 24 || v.m < 0 || v.m > 59 || v.h == 24 && v.m != 0 {
+			return nil, fmt.Errorf("window %q is not HH:MM-HH:MM", s)
+		}
+	}
+	return &window{from: h1*60 + m1, to: h2*60 + m2}, nil
+}
+
+// contains reports whether the time of day of t is in w.
+func (w *window) contains(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	if w.from <= w.to {
+		return m >= w.from && m < w.to
+	}
+	return m >= w.from || m < w.to
+}
+
+// NewRules reads and checks the alerts section of the configuration.
+func NewRules(v *viper.Viper) ([]Rule, error) {
+	var rules []Rule
+	if err := v.UnmarshalKey("alerts", &rules); err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for i := range rules {
+		r := &rules[i]
+		if err := r.check(); err != nil {
+			return nil, fmt.Errorf("alerts[%d]: %v", i, err)
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("alerts[%d]: duplicate name %s", i, r.Name)
+		}
+		names[r.Name] = true
+	}
+	return rules, nil
+}
+
+// check validates r and parses its window.
+func (r *Rule) check() error {
+	if r.Name == "" || r.Device == "" || r.Reading == "" {
+		return fmt.Errorf("name, device and reading are required")
+	}
+	if (r.Above == nil) == (r.Below == nil) {
+		return fmt.Errorf("%s: one of above and below is required", r.Name)
+	}
+	if r.For < 0 || r.Cooldown < 0 || r.Hysteresis < 0 {
+		return fmt.Errorf("%s: for, cooldown and hysteresis must not be negative", r.Name)
+	}
+	if r.Window != "" {
+		w, err := parseWindow(r.Window)
+		if err != nil {
+			return fmt.Errorf("%s: %v", r.Name, err)
+		}
+		r.window = w
+	}
+	for j, a := range r.Actions {
+		switch a.Type {
+		case ActionBlink, ActionOn, ActionOff, ActionAlarm, ActionText:
+		default:
+			return fmt.Errorf("%s: actions[%d]: unknown type %q", r.Name, j, a.Type)
+		}
+		if a.Device == "" {
+			return fmt.Errorf("%s: actions[%d]: device is required", r.Name, j)
+		}
+		if a.Count < 0 {
+			return fmt.Errorf("%s: actions[%d]: count %d is negative", r.Name, j, a.Count)
+		}
+	}
+	return nil
+}
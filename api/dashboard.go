@@ -0,0 +1,77 @@
+package api
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"pi/dev"
+	"sync"
+)
+
+// assets are the files of the dashboard.
+//
+//go:embed dashboard
+var assets embed.FS
+
+// Dashboard is the web page of the board: live temperature charts, the
+// RTC time, LED toggles, a buzzer piano and the OLED, fed by /api/events
+// and driving /api/devices.
+type Dashboard struct {
+	mu      sync.Mutex
+	devices []dashboardDevice
+}
+
+// dashboardDevice is an entry of GET /api/dashboard.
+type dashboardDevice struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// NewDashboard creates a new Dashboard without devices.
+func NewDashboard() *Dashboard {
+	return &Dashboard{}
+}
+
+// SetDevices sets the devices of the configuration shown.
+func (d *Dashboard) SetDevices(configs []dev.DeviceConfig) {
+	devices := make([]dashboardDevice, len(configs))
+	for i, c := range configs {
+		devices[i] = dashboardDevice{Name: c.Name, Type: c.Type}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.devices = devices
+}
+
+// HandleDashboard adds the dashboard d:
+//
+//	GET /                redirect to /dashboard/
+//	GET /dashboard/      the page and its scripts
+//	GET /api/dashboard   JSON {devices: [{name, type}]} of the page
+func (s *Server) HandleDashboard(d *Dashboard) {
+	root, err := fs.Sub(assets, "dashboard")
+	if err != nil {
+		panic(err)
+	}
+	s.mux.Handle("/dashboard/", http.StripPrefix("/dashboard/", http.FileServer(http.FS(root))))
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		http.Redirect(w, r, "/dashboard/", http.StatusFound)
+	})
+	s.mux.HandleFunc("/api/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		d.mu.Lock()
+		devices := d.devices
+		d.mu.Unlock()
+		if devices == nil {
+			devices = []dashboardDevice{}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"devices": devices})
+	})
+}
@@ -0,0 +1,250 @@
+// Dashboard of the Pioneer600 daemon, fed by the /api/events WebSocket.
+'use strict';
+
+const WINDOW = 30 * 60 * 1000; // chart time span, ms
+const COLORS = ['#2563eb', '#dc2626', '#16a34a', '#9333ea', '#ea580c', '#0891b2'];
+const NOTES = [
+  ['C4', 261.63], ['C#4', 277.18], ['D4', 293.66], ['D#4', 311.13], ['E4', 329.63], ['F4', 349.23],
+  ['F#4', 369.99], ['G4', 392.00], ['G#4', 415.30], ['A4', 440.00], ['A#4', 466.16], ['B4', 493.88], ['C5', 523.25],
+];
+
+const series = new Map(); // device/name -> [{t, v}]
+const leds = new Map(); // name -> button
+let rtc = null; // {value: unix seconds, at: Date.now()}
+
+function api(method, path, body) {
+  return fetch(path, {
+    method,
+    headers: body ? {'Content-Type': 'application/json'} : {},
+    body: body ? JSON.stringify(body) : undefined,
+  }).then((resp) => {
+    if (!resp.ok) {
+      return resp.text().then((text) => Promise.reject(new Error(resp.status + ' ' + text)));
+    }
+    return resp.status === 204 ? null : resp.json();
+  });
+}
+
+function el(tag, props, children) {
+  const e = Object.assign(document.createElement(tag), props || {});
+  (children || []).forEach((c) => e.append(c));
+  return e;
+}
+
+// Devices
+
+function setupLEDs(devices) {
+  const box = document.querySelector('#leds .buttons');
+  devices.filter((d) => d.type === 'led' || d.type === 'pcf8574-led').forEach((d) => {
+    const b = el('button', {textContent: d.name});
+    b.onclick = () => api('PUT', '/api/devices/' + d.name + '/state', {state: 'toggle'})
+      .then((r) => b.classList.toggle('on', r.state === 'on'))
+      .catch(console.error);
+    leds.set(d.name, b);
+    box.append(b);
+  });
+  document.getElementById('leds').hidden = leds.size === 0;
+}
+
+function setupPiano(devices) {
+  const buzzer = devices.find((d) => d.type === 'buzzer');
+  if (!buzzer) {
+    return;
+  }
+  const keys = document.querySelector('#piano .keys');
+  const whites = NOTES.filter(([name]) => !name.includes('#')).length;
+  let white = 0;
+  NOTES.forEach(([name, hz]) => {
+    const black = name.includes('#');
+    const key = el('button', {className: black ? 'black' : 'white', textContent: black ? '' : name, title: name});
+    if (black) {
+      key.style.left = (white / whites * 100 - 3.5) + '%';
+    } else {
+      white++;
+    }
+    key.onpointerdown = () => {
+      key.classList.add('playing');
+      api('POST', '/api/devices/' + buzzer.name + '/tone', {hz, ms: 250})
+        .catch(() => {}) // busy with the previous note
+        .finally(() => setTimeout(() => key.classList.remove('playing'), 250));
+    };
+    keys.append(key);
+  });
+  document.getElementById('piano').hidden = false;
+}
+
+function setupOLED(devices) {
+  const oled = devices.find((d) => d.type === 'ssd1306');
+  if (!oled) {
+    return;
+  }
+  const section = document.getElementById('oled');
+  const img = section.querySelector('img');
+  img.onerror = () => { img.alt = 'no remote view'; };
+  img.src = '/display/stream.mjpeg?scale=4';
+  section.querySelector('form').onsubmit = (e) => {
+    e.preventDefault();
+    const input = e.target.elements.text;
+    api('POST', '/api/devices/' + oled.name + '/text', {text: input.value, position: 'top-center'})
+      .then(() => { input.value = ''; })
+      .catch(console.error);
+  };
+  section.hidden = false;
+}
+
+// Temperature history from the store, when the daemon records one.
+function backfill(devices) {
+  devices.filter((d) => d.type === 'ds18b20' || d.type === 'bmp180').forEach((d) => {
+    api('GET', '/api/history/' + d.name + '/temperature?from=-30m&step=1m')
+      .then((h) => {
+        const points = h.points.map((p) => ({t: Date.parse(p.time), v: p.mean}));
+        const key = d.name + '/temperature';
+        series.set(key, points.concat(series.get(key) || []));
+        draw();
+      })
+      .catch(() => {});
+  });
+}
+
+// Events
+
+function onEvent(e) {
+  const key = e.device + '/' + e.name;
+  const t = Date.parse(e.time);
+  if (e.name === 'time' && e.unit === 's') {
+    rtc = {value: e.value, at: Date.now()};
+    tick();
+  }
+  if (e.unit === '°C') {
+    const points = series.get(key) || [];
+    points.push({t, v: e.value});
+    series.set(key, points);
+    draw();
+  }
+  if (e.unit === '' || e.unit === undefined) {
+    const b = leds.get(e.device);
+    if (b && e.name === 'state') {
+      b.classList.toggle('on', e.value === 1);
+    }
+    return;
+  }
+  showReading(key, e);
+}
+
+function showReading(key, e) {
+  const body = document.querySelector('#readings tbody');
+  let row = body.querySelector('tr[data-key="' + CSS.escape(key) + '"]');
+  if (!row) {
+    row = el('tr', {}, [el('td', {textContent: key}), el('td')]);
+    row.dataset.key = key;
+    const rows = Array.from(body.children);
+    body.insertBefore(row, rows.find((r) => r.dataset.key > key) || null);
+  }
+  let text;
+  if (e.name === 'time' && e.unit === 's') {
+    text = new Date(e.value * 1000).toLocaleString();
+  } else {
+    text = (Math.round(e.value * 100) / 100) + ' ' + e.unit;
+  }
+  row.lastChild.textContent = text;
+}
+
+function tick() {
+  if (rtc) {
+    const now = new Date((rtc.value * 1000) + Date.now() - rtc.at);
+    document.getElementById('clock').textContent = now.toLocaleTimeString();
+  }
+}
+
+function connect(delay) {
+  const link = document.getElementById('link');
+  const ws = new WebSocket((location.protocol === 'https:' ? 'wss://' : 'ws://') + location.host + '/api/events');
+  ws.onopen = () => {
+    delay = 1000;
+    link.textContent = 'live';
+    link.classList.remove('down');
+  };
+  ws.onmessage = (m) => onEvent(JSON.parse(m.data));
+  ws.onclose = () => {
+    link.textContent = 'offline';
+    link.classList.add('down');
+    setTimeout(() => connect(Math.min(delay * 2, 10000)), delay);
+  };
+}
+
+// Chart
+
+function draw() {
+  const canvas = document.getElementById('chart');
+  const ratio = window.devicePixelRatio || 1;
+  const width = canvas.clientWidth;
+  const height = canvas.clientHeight;
+  canvas.width = width * ratio;
+  canvas.height = height * ratio;
+  const ctx = canvas.getContext('2d');
+  ctx.scale(ratio, ratio);
+  ctx.clearRect(0, 0, width, height);
+
+  const end = Date.now();
+  const start = end - WINDOW;
+  let min = Infinity;
+  let max = -Infinity;
+  series.forEach((points, key) => {
+    const kept = points.filter((p) => p.t >= start);
+    series.set(key, kept);
+    kept.forEach((p) => {
+      min = Math.min(min, p.v);
+      max = Math.max(max, p.v);
+    });
+  });
+  if (min === Infinity) {
+    ctx.fillStyle = '#6b7280';
+    ctx.fillText('waiting for readings', 10, 20);
+    return;
+  }
+  min = Math.floor(min - 0.5);
+  max = Math.ceil(max + 0.5);
+  const left = 40;
+  const x = (t) => left + (t - start) / WINDOW * (width - left - 10);
+  const y = (v) => 10 + (max - v) / (max - min) * (height - 30);
+
+  ctx.strokeStyle = '#e5e7eb';
+  ctx.fillStyle = '#6b7280';
+  ctx.font = '11px sans-serif';
+  const step = Math.max(1, Math.round((max - min) / 5));
+  for (let v = min; v <= max; v += step) {
+    ctx.beginPath();
+    ctx.moveTo(left, y(v));
+    ctx.lineTo(width - 10, y(v));
+    ctx.stroke();
+    ctx.fillText(v + '°', 5, y(v) + 4);
+  }
+  for (let m = 0; m <= 30; m += 10) {
+    const t = end - m * 60000;
+    ctx.fillText(m ? '-' + m + 'm' : 'now', x(t) - 12, height - 5);
+  }
+
+  const legend = document.getElementById('legend');
+  legend.textContent = '';
+  let i = 0;
+  series.forEach((points, key) => {
+    const color = COLORS[i++ % COLORS.length];
+    ctx.strokeStyle = color;
+    ctx.lineWidth = 2;
+    ctx.beginPath();
+    points.forEach((p, j) => (j ? ctx.lineTo(x(p.t), y(p.v)) : ctx.moveTo(x(p.t), y(p.v))));
+    ctx.stroke();
+    legend.append(el('span', {}, [el('i', {style: 'background:' + color}), key]));
+  });
+}
+
+api('GET', '/api/dashboard').then((d) => {
+  setupLEDs(d.devices);
+  setupPiano(d.devices);
+  setupOLED(d.devices);
+  backfill(d.devices);
+}).catch(console.error).finally(() => connect(1000));
+
+setInterval(tick, 1000);
+setInterval(draw, 10000);
+window.addEventListener('resize', draw);
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"pi/dev"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDashboard(t *testing.T) {
+	d := NewDashboard()
+	d.SetDevices([]dev.DeviceConfig{{Name: "led1", Type: dev.TypeLED}, {Name: "bz", Type: dev.TypeBuzzer}})
+	s := NewServer(&Options{})
+	s.HandleDashboard(d)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	for path, want := range map[string]string{
+		"/dashboard/":       "<title>Pioneer600</title>",
+		"/dashboard/app.js": "/api/events",
+		"/":                 "<title>Pioneer600</title>",
+	} {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), want) {
+			t.Errorf("GET %s = %d, no %q", path, resp.StatusCode, want)
+		}
+	}
+	resp, err := http.Get(ts.URL + "/nothing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /nothing = %d, want 404", resp.StatusCode)
+	}
+
+	resp, err = http.Get(ts.URL + "/api/dashboard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var got struct {
+		Devices []dashboardDevice `json:"devices"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := []dashboardDevice{{Name: "led1", Type: "led"}, {Name: "bz", Type: "buzzer"}}
+	if !reflect.DeepEqual(got.Devices, want) {
+		t.Errorf("GET /api/dashboard = %v, want %v", got.Devices, want)
+	}
+}
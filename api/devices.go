@@ -0,0 +1,374 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"pi/control"
+	"pi/dev"
+	"pi/event"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxImage is the size limit of the image bodies.
+const maxImage = 1 << 20
+
+var holdSchema = number(0, 3600, "seconds the daemon leaves the device alone, default 60")
+
+// Schemas are the JSON schemas of the request bodies by name.
+var Schemas = map[string]*Schema{
+	"state": object(map[string]*Schema{
+		"state": {Type: "string", Enum: []string{"on", "off", "toggle"}},
+		"hold":  holdSchema,
+	}, "state"),
+	"tone": object(map[string]*Schema{
+		"hz": number(20, control.MaxHz, "frequency"),
+		"ms": integer(1, 10000, "duration in milliseconds"),
+	}, "hz", "ms"),
+	"melody": object(map[string]*Schema{
+		"bpm": number(20, 400, "tempo, default 96"),
+		"notes": {
+			Type: "array",
+			Items: object(map[string]*Schema{
+				"hz":    number(0, control.MaxHz, "frequency, 0 for a rest"),
+				"beats": number(0.125, 16, "duration in beats"),
+			}, "hz", "beats"),
+			MinItems: intPtr(1),
+			MaxItems: intPtr(512),
+		},
+	}, "notes"),
+	"text": object(map[string]*Schema{
+		"text":     {Type: "string"},
+		"position": {Type: "string", Enum: positionNames()},
+		"hold":     holdSchema,
+	}, "text"),
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+type stateRequest struct {
+	State string   `json:"state"`
+	Hold  *float64 `json:"hold"`
+}
+
+type toneRequest struct {
+	Hz float64 `json:"hz"`
+	Ms int     `json:"ms"`
+}
+
+type note struct {
+	Hz    float64 `json:"hz"`
+	Beats float64 `json:"beats"`
+}
+
+type melodyRequest struct {
+	BPM   float64 `json:"bpm"`
+	Notes []note  `json:"notes"`
+}
+
+type textRequest struct {
+	Text     string   `json:"text"`
+	Position string   `json:"position"`
+	Hold     *float64 `json:"hold"`
+}
+
+// deviceState is the answer of GET /api/devices/{name}.
+type deviceState struct {
+	dev.DeviceHealth
+	// Values are the latest readings by name.
+	Values map[string]event.Event `json:"values"`
+}
+
+var positions = map[string]dev.SSD1306Pos{
+	"top-center":    dev.PosTopCenter,
+	"top-left":      dev.PosTopLeft,
+	"top-right":     dev.PosTopRight,
+	"bottom-left":   dev.PosBottomLeft,
+	"bottom-right":  dev.PosBottomRight,
+	"bottom-center": dev.PosBottomCenter,
+}
+
+var dithers = map[string]dev.DitherMode{
+	"threshold":       dev.DitherThreshold,
+	"floyd-steinberg": dev.DitherFloydSteinberg,
+	"bayer":           dev.DitherBayer,
+}
+
+var scales = map[string]dev.ScaleMode{
+	"fit":     dev.ScaleFit,
+	"fill":    dev.ScaleFill,
+	"crop":    dev.ScaleCrop,
+	"stretch": dev.ScaleStretch,
+}
+
+func positionNames() []string {
+	return []string{"top-center", "top-left", "top-right", "bottom-left", "bottom-right", "bottom-center"}
+}
+
+// devices serves the commands of a control.Controller.
+type devices struct {
+	*control.Controller
+}
+
+// HandleDevices adds the REST API of the peripherals of c:
+//
+//	GET    /api/schemas                JSON schemas of the bodies by name
+//	GET    /api/devices                health of the devices
+//	GET    /api/devices/{name}         health and latest readings
+//	PUT    /api/devices/{name}/state   LED {"state": "on|off|toggle"}
+//	POST   /api/devices/{name}/tone    buzzer {"hz", "ms"}, 202
+//	POST   /api/devices/{name}/melody  buzzer {"bpm", "notes": [{"hz", "beats"}]}, 202
+//	DELETE /api/devices/{name}/sound   stops the tone or melody
+//	POST   /api/devices/{name}/text    OLED {"text", "position"}
+//	POST   /api/devices/{name}/image   OLED PNG, JPEG or GIF body, optional
+//	                                   dither, scale, threshold, invert and
+//	                                   hold query parameters
+//
+// Errors are {"error": message}, or {"errors": [...]} for invalid bodies.
+// Commands to a device which failed to open answer 503.
+func (s *Server) HandleDevices(c *control.Controller) {
+	h := &devices{Controller: c}
+	s.mux.HandleFunc("/api/schemas", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJSON(w, http.StatusOK, Schemas)
+	})
+	s.mux.HandleFunc("/api/devices", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJSON(w, http.StatusOK, c.Health().Report())
+	})
+	s.mux.HandleFunc("/api/devices/", h.serve)
+}
+
+// serve routes /api/devices/{name}[/{action}].
+func (h *devices) serve(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/devices/"), "/")
+	parts := strings.Split(path, "/")
+	if len(parts) > 2 || parts[0] == "" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	name, action := parts[0], ""
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+	type route struct {
+		method string
+		fn     func(http.ResponseWriter, *http.Request, string)
+	}
+	routes := map[string]route{
+		"":       {http.MethodGet, h.get},
+		"state":  {http.MethodPut, h.state},
+		"tone":   {http.MethodPost, h.tone},
+		"melody": {http.MethodPost, h.melody},
+		"sound":  {http.MethodDelete, h.stop},
+		"text":   {http.MethodPost, h.text},
+		"image":  {http.MethodPost, h.image},
+	}
+	rt, ok := routes[action]
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown action "+action)
+		return
+	}
+	if r.Method != rt.method {
+		w.Header().Set("Allow", rt.method)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	rt.fn(w, r, name)
+}
+
+// writeCommandError answers the status of a control error.
+func writeCommandError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	switch {
+	case errors.Is(err, control.ErrUnknown), errors.Is(err, control.ErrUnsupported):
+		status = http.StatusNotFound
+	case errors.Is(err, control.ErrFailed):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, control.ErrBusy):
+		status = http.StatusConflict
+	case errors.Is(err, control.ErrInvalid):
+		status = http.StatusBadRequest
+	}
+	writeError(w, status, err.Error())
+}
+
+// holdOf returns seconds as a duration, control.DefaultHold when nil.
+func holdOf(seconds *float64) time.Duration {
+	if seconds == nil {
+		return control.DefaultHold
+	}
+	return time.Duration(*seconds * float64(time.Second))
+}
+
+func (h *devices) get(w http.ResponseWriter, r *http.Request, name string) {
+	for _, report := range h.Health().Report() {
+		if report.Name == name {
+			writeJSON(w, http.StatusOK, deviceState{DeviceHealth: report, Values: h.Readings(name)})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "unknown device "+name)
+}
+
+func (h *devices) state(w http.ResponseWriter, r *http.Request, name string) {
+	var req stateRequest
+	if !decodeJSON(w, r, Schemas["state"], &req) {
+		return
+	}
+	on, err := h.Switch(name, req.State, holdOf(req.Hold))
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+	state := "off"
+	if on {
+		state = "on"
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"state": state})
+}
+
+func (h *devices) tone(w http.ResponseWriter, r *http.Request, name string) {
+	var req toneRequest
+	if !decodeJSON(w, r, Schemas["tone"], &req) {
+		return
+	}
+	h.play(w, name, []control.Note{{Hz: req.Hz, Duration: time.Duration(req.Ms) * time.Millisecond}})
+}
+
+func (h *devices) melody(w http.ResponseWriter, r *http.Request, name string) {
+	var req melodyRequest
+	if !decodeJSON(w, r, Schemas["melody"], &req) {
+		return
+	}
+	notes := make([]control.Note, len(req.Notes))
+	for i, n := range req.Notes {
+		notes[i] = control.Note{Hz: n.Hz, Duration: control.Beats(req.BPM, n.Beats)}
+	}
+	h.play(w, name, notes)
+}
+
+// play answers 202 with the length of the notes played in the background.
+func (h *devices) play(w http.ResponseWriter, name string, notes []control.Note) {
+	total, err := h.Play(name, notes)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]float64{"seconds": total.Seconds()})
+}
+
+func (h *devices) stop(w http.ResponseWriter, r *http.Request, name string) {
+	if _, err := h.Device(name); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+	h.Stop(name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *devices) text(w http.ResponseWriter, r *http.Request, name string) {
+	var req textRequest
+	if !decodeJSON(w, r, Schemas["text"], &req) {
+		return
+	}
+	pos := dev.PosTopLeft
+	if req.Position != "" {
+		pos = positions[req.Position]
+	}
+	if err := h.ShowText(name, pos, req.Text, holdOf(req.Hold)); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *devices) image(w http.ResponseWriter, r *http.Request, name string) {
+	opts, hold, err := convertQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxImage+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(body) > maxImage {
+		writeError(w, http.StatusRequestEntityTooLarge, "image over 1MiB")
+		return
+	}
+	img, err := dev.DecodeImage(body)
+	if errors.Is(err, dev.ErrImageTooLarge) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusUnsupportedMediaType, "decode image: "+err.Error())
+		return
+	}
+	if err = h.ShowImage(name, img, opts, holdOf(hold)); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// convertQuery reads the dither, scale, threshold, invert and hold query
+// parameters.
+func convertQuery(r *http.Request) (*dev.ConvertOptions, *float64, error) {
+	opts := dev.DefaultConvertOptions
+	q := r.URL.Query()
+	if s := q.Get("dither"); s != "" {
+		mode, ok := dithers[s]
+		if !ok {
+			return nil, nil, fmt.Errorf("dither %q: must be threshold, floyd-steinberg or bayer", s)
+		}
+		opts.Dither = mode
+	}
+	if s := q.Get("scale"); s != "" {
+		mode, ok := scales[s]
+		if !ok {
+			return nil, nil, fmt.Errorf("scale %q: must be fit, fill, crop or stretch", s)
+		}
+		opts.Scale = mode
+	}
+	if s := q.Get("threshold"); s != "" {
+		n, err := strconv.ParseUint(s, 10, 8)
+		if err != nil || n == 0 {
+			return nil, nil, fmt.Errorf("threshold %q: must be 1-255", s)
+		}
+		opts.Threshold = uint8(n)
+	}
+	if s := q.Get("invert"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invert %q: must be a boolean", s)
+		}
+		opts.Invert = b
+	}
+	var hold *float64
+	if s := q.Get("hold"); s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f < 0 || f > 3600 {
+			return nil, nil, fmt.Errorf("hold %q: must be 0-3600 seconds", s)
+		}
+		hold = &f
+	}
+	return &opts, hold, nil
+}
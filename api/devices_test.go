@@ -0,0 +1,344 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"pi/control"
+	"pi/dev"
+	"pi/event"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDevice is a present dev.Device.
+type fakeDevice struct{ name string }
+
+func (d *fakeDevice) Name() string                   { return d.name }
+func (d *fakeDevice) Init(ctx context.Context) error { return nil }
+func (d *fakeDevice) Close() error                   { return nil }
+func (d *fakeDevice) Health() dev.Health             { return dev.Health{State: dev.HealthPresent} }
+
+type fakeLED struct {
+	fakeDevice
+	status int
+}
+
+func (l *fakeLED) On() error     { l.status = 1; return nil }
+func (l *fakeLED) Off() error    { l.status = 0; return nil }
+func (l *fakeLED) Toggle() error { l.status ^= 1; return nil }
+func (l *fakeLED) Status() int   { return l.status }
+
+// fakeBuzzer sends the frequencies it plays to notes.
+type fakeBuzzer struct {
+	fakeDevice
+	notes chan float64
+}
+
+func (b *fakeBuzzer) Play(ctx context.Context, hz float64, d time.Duration) error {
+	b.notes <- hz
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+type devicesFixture struct {
+	ts     *httptest.Server
+	led    *fakeLED
+	buzzer *fakeBuzzer
+	oled   *dev.Mirror
+	bus    *event.Bus
+	holds  map[string]time.Duration
+}
+
+func newDevicesServer(t *testing.T) *devicesFixture {
+	f := &devicesFixture{
+		led:    &fakeLED{fakeDevice: fakeDevice{"led@BCM26"}},
+		buzzer: &fakeBuzzer{fakeDevice: fakeDevice{"pcf8574@/dev/i2c-1:0x20"}, notes: make(chan float64, 16)},
+		oled:   newMirror(t),
+		bus:    event.New(),
+		holds:  make(map[string]time.Duration),
+	}
+	r := dev.NewHealthRegistry()
+	r.Add("led1", f.led)
+	r.Add("buzzer", f.buzzer)
+	r.Add("oled", f.oled)
+	r.Add("temp", &fakeDevice{"ds18b20"})
+	r.Fail("beeper", errors.New("open /dev/i2c-1: no such file or directory"))
+	s := NewServer(&Options{})
+	s.HandleDevices(control.New(r, f.bus, func(name string, d time.Duration) {
+		f.holds[name] = d
+	}))
+	f.ts = httptest.NewServer(s)
+	t.Cleanup(f.ts.Close)
+	return f
+}
+
+// do sends body as JSON, or as is for a []byte or string, and decodes the
+// JSON answer into v when not nil. Only a []byte is not sent as
+// application/json.
+func (f *devicesFixture) do(t *testing.T, method, path string, body interface{}, v interface{}) int {
+	var data []byte
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		data = b
+	case string:
+		data = []byte(b)
+	default:
+		var err error
+		if data, err = json.Marshal(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, f.ts.URL+path, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, image := body.([]byte); !image && body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestDeviceReadings(t *testing.T) {
+	f := newDevicesServer(t)
+	f.bus.Publish(event.Event{Device: "temp", Name: "temperature", Value: 21.5, Unit: "°C"})
+
+	var report []dev.DeviceHealth
+	if status := f.do(t, "GET", "/api/devices", nil, &report); status != http.StatusOK || len(report) != 5 {
+		t.Fatalf("%d %+v", status, report)
+	}
+	var state deviceState
+	if status := f.do(t, "GET", "/api/devices/temp", nil, &state); status != http.StatusOK {
+		t.Fatalf("status %d", status)
+	}
+	if v := state.Values["temperature"]; state.Driver != "ds18b20" || v.Value != 21.5 || v.Unit != "°C" {
+		t.Errorf("temp = %+v", state)
+	}
+	if status := f.do(t, "GET", "/api/devices/humidity", nil, nil); status != http.StatusNotFound {
+		t.Errorf("unknown device status %d", status)
+	}
+}
+
+func TestLEDState(t *testing.T) {
+	f := newDevicesServer(t)
+	var answer map[string]string
+	if status := f.do(t, "PUT", "/api/devices/led1/state", map[string]interface{}{"state": "on"}, &answer); status != http.StatusOK || answer["state"] != "on" {
+		t.Fatalf("%d %v", status, answer)
+	}
+	if f.led.status != 1 || f.holds["led1"] != control.DefaultHold {
+		t.Errorf("led %d, holds %v", f.led.status, f.holds)
+	}
+	if e, ok := f.bus.Last("led1", "state"); !ok || e.Value != 1 {
+		t.Errorf("state event %+v", e)
+	}
+	f.do(t, "PUT", "/api/devices/led1/state", map[string]interface{}{"state": "toggle", "hold": 5}, &answer)
+	if answer["state"] != "off" || f.holds["led1"] != 5*time.Second {
+		t.Errorf("toggle = %v, holds %v", answer, f.holds)
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	f := newDevicesServer(t)
+	for _, c := range []struct {
+		method, path string
+		body         interface{}
+		status       int
+		errors       []string
+	}{
+		{"PUT", "/api/devices/led1/state", map[string]interface{}{"state": "blink", "colour": "red"}, http.StatusBadRequest, []string{
+			"body: unknown property colour",
+			"body.state: must be one of on, off, toggle",
+		}},
+		{"PUT", "/api/devices/led1/state", map[string]interface{}{}, http.StatusBadRequest, []string{"body: state is required"}},
+		{"POST", "/api/devices/buzzer/tone", map[string]interface{}{"hz": "A4", "ms": 2.5}, http.StatusBadRequest, []string{
+			"body.hz: must be a number",
+			"body.ms: must be an integer",
+		}},
+		{"POST", "/api/devices/buzzer/melody", map[string]interface{}{"notes": []interface{}{
+			map[string]interface{}{"hz": 440, "beats": 1},
+			map[string]interface{}{"hz": 30000, "beats": 0},
+		}}, http.StatusBadRequest, []string{
+			"body.notes[1].beats: 0 is below the minimum 0.125",
+			"body.notes[1].hz: 30000 is above the maximum 1000",
+		}},
+		{"POST", "/api/devices/oled/text", map[string]interface{}{"text": strings.Repeat("x", 65)}, http.StatusBadRequest, nil},
+		{"PUT", "/api/devices/led1/state", "{", http.StatusBadRequest, nil},
+		{"POST", "/api/devices/led1/tone", map[string]interface{}{"hz": 440, "ms": 100}, http.StatusNotFound, nil},
+		{"PUT", "/api/devices/rtc/state", map[string]interface{}{"state": "on"}, http.StatusNotFound, nil},
+		{"POST", "/api/devices/beeper/tone", map[string]interface{}{"hz": 440, "ms": 100}, http.StatusServiceUnavailable, nil},
+		{"GET", "/api/devices/led1/state", nil, http.StatusMethodNotAllowed, nil},
+		{"GET", "/api/devices/led1/colour", nil, http.StatusNotFound, nil},
+	} {
+		var answer struct {
+			Error  string
+			Errors []string
+		}
+		status := f.do(t, c.method, c.path, c.body, &answer)
+		if status != c.status {
+			t.Errorf("%s %s: status %d, want %d", c.method, c.path, status, c.status)
+		}
+		if c.errors != nil && strings.Join(answer.Errors, "\n") != strings.Join(c.errors, "\n") {
+			t.Errorf("%s %s: errors %q, want %q", c.method, c.path, answer.Errors, c.errors)
+		}
+		if c.errors == nil && answer.Error == "" {
+			t.Errorf("%s %s: no error message", c.method, c.path)
+		}
+	}
+	if f.led.status != 0 {
+		t.Error("invalid request applied")
+	}
+}
+
+func TestCrossSite(t *testing.T) {
+	f := newDevicesServer(t)
+	for _, c := range []struct {
+		contentType, origin string
+		status              int
+	}{
+		{"text/plain", "", http.StatusUnsupportedMediaType},
+		{"", "", http.StatusUnsupportedMediaType},
+		{"application/json", "http://example.com", http.StatusForbidden},
+		{"application/json", "null", http.StatusForbidden},
+		{"application/json; charset=utf-8", f.ts.URL, http.StatusOK},
+	} {
+		req, err := http.NewRequest("PUT", f.ts.URL+"/api/devices/led1/state", strings.NewReader(`{"state":"toggle"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", c.contentType)
+		if c.origin != "" {
+			req.Header.Set("Origin", c.origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != c.status {
+			t.Errorf("Content-Type %q, Origin %q: status %d, want %d", c.contentType, c.origin, resp.StatusCode, c.status)
+		}
+	}
+	if f.led.status != 1 {
+		t.Errorf("led %d after one accepted toggle", f.led.status)
+	}
+}
+
+func TestMelody(t *testing.T) {
+	f := newDevicesServer(t)
+	melody := map[string]interface{}{"bpm": 120, "notes": []interface{}{
+		map[string]interface{}{"hz": 440, "beats": 0.125},
+		map[string]interface{}{"hz": 0, "beats": 16},
+	}}
+	var answer map[string]float64
+	if status := f.do(t, "POST", "/api/devices/buzzer/melody", melody, &answer); status != http.StatusAccepted || answer["seconds"] != 8.0625 {
+		t.Fatalf("%d %v", status, answer)
+	}
+	if hz := <-f.buzzer.notes; hz != 440 {
+		t.Errorf("first note %v", hz)
+	}
+	<-f.buzzer.notes
+	if status := f.do(t, "POST", "/api/devices/buzzer/tone", map[string]interface{}{"hz": 880, "ms": 10}, nil); status != http.StatusConflict {
+		t.Errorf("tone while playing: status %d", status)
+	}
+	if status := f.do(t, "DELETE", "/api/devices/buzzer/sound", nil, nil); status != http.StatusNoContent {
+		t.Errorf("stop status %d", status)
+	}
+	deadline := time.Now().Add(time.Second)
+	for f.do(t, "POST", "/api/devices/buzzer/tone", map[string]interface{}{"hz": 880, "ms": 10}, nil) == http.StatusConflict {
+		if time.Now().After(deadline) {
+			t.Fatal("melody not stopped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if hz := <-f.buzzer.notes; hz != 880 {
+		t.Errorf("tone %v", hz)
+	}
+
+	long := map[string]interface{}{"bpm": 20, "notes": []interface{}{
+		map[string]interface{}{"hz": 440, "beats": 16},
+		map[string]interface{}{"hz": 440, "beats": 16},
+	}}
+	if status := f.do(t, "POST", "/api/devices/buzzer/melody", long, nil); status != http.StatusBadRequest {
+		t.Errorf("long melody status %d", status)
+	}
+}
+
+func TestDisplayDrawing(t *testing.T) {
+	f := newDevicesServer(t)
+	text := map[string]interface{}{"text": "hello", "position": "bottom-right", "hold": 30}
+	if status := f.do(t, "POST", "/api/devices/oled/text", text, nil); status != http.StatusNoContent {
+		t.Fatalf("text status %d", status)
+	}
+	if lit(dev.FrameImage(f.oled.Snapshot(), 1)) == 0 || f.holds["oled"] != 30*time.Second {
+		t.Errorf("text not drawn, holds %v", f.holds)
+	}
+
+	img := image.NewGray(image.Rect(0, 0, 32, 16))
+	for x := 0; x < 32; x++ {
+		img.SetGray(x, 8, color.Gray{Y: 0xff})
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	if status := f.do(t, "POST", "/api/devices/oled/image?scale=stretch&dither=bayer", buf.Bytes(), nil); status != http.StatusNoContent {
+		t.Fatalf("image status %d", status)
+	}
+	if n := lit(dev.FrameImage(f.oled.Snapshot(), 1)); n == 0 || n > 128*8 {
+		t.Errorf("%d pixels lit", n)
+	}
+	if status := f.do(t, "POST", "/api/devices/oled/image?dither=halftone", buf.Bytes(), nil); status != http.StatusBadRequest {
+		t.Errorf("unknown dither status %d", status)
+	}
+	if status := f.do(t, "POST", "/api/devices/oled/image?threshold=0", buf.Bytes(), nil); status != http.StatusBadRequest {
+		t.Errorf("threshold 0 status %d", status)
+	}
+	// A 1x1 PNG whose header claims 100000x100000.
+	huge := append([]byte(nil), buf.Bytes()...)
+	binary.BigEndian.PutUint32(huge[16:], 100000)
+	binary.BigEndian.PutUint32(huge[20:], 100000)
+	binary.BigEndian.PutUint32(huge[29:], crc32.ChecksumIEEE(huge[12:29]))
+	if status := f.do(t, "POST", "/api/devices/oled/image", huge, nil); status != http.StatusBadRequest {
+		t.Errorf("huge image status %d", status)
+	}
+	if status := f.do(t, "POST", "/api/devices/oled/image", []byte("GIF89a"), nil); status != http.StatusUnsupportedMediaType {
+		t.Errorf("truncated image status %d", status)
+	}
+	if status := f.do(t, "POST", "/api/devices/buzzer/text", text, nil); status != http.StatusNotFound {
+		t.Errorf("text on the buzzer status %d", status)
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	f := newDevicesServer(t)
+	var schemas map[string]*Schema
+	if status := f.do(t, "GET", "/api/schemas", nil, &schemas); status != http.StatusOK {
+		t.Fatalf("status %d", status)
+	}
+	melody := schemas["melody"]
+	if melody == nil || melody.Properties["notes"].Items.Properties["hz"].Maximum == nil || *melody.Additional {
+		t.Errorf("melody schema %+v", melody)
+	}
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image/jpeg"
+	"net/http"
+	"pi/dev"
+	"strconv"
+)
+
+const (
+	// defaultScale enlarges the 128x64 panel to a readable size.
+	defaultScale = 4
+	maxScale     = 16
+	mjpegBound   = "frame"
+)
+
+// HandleDisplay adds the remote view of m:
+//
+//	GET /display/snapshot.png  current frame as PNG
+//	GET /display/stream.mjpeg  frames as motion JPEG, for browsers and VLC
+//	GET /display/events        frames as server-sent events of base64 PNGs
+//
+// All of them take an optional scale query parameter (1-16, default 4).
+func (s *Server) HandleDisplay(m *dev.Mirror) {
+	s.mux.HandleFunc("/display/snapshot.png", func(w http.ResponseWriter, r *http.Request) {
+		scale, ok := queryScale(w, r)
+		if !ok {
+			return
+		}
+		var buf bytes.Buffer
+		if err := dev.EncodePNG(&buf, m.Snapshot(), scale); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(buf.Bytes())
+	})
+	s.mux.HandleFunc("/display/stream.mjpeg", func(w http.ResponseWriter, r *http.Request) {
+		scale, ok := queryScale(w, r)
+		if !ok {
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		frames, cancel := m.Subscribe()
+		defer cancel()
+		w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+mjpegBound)
+		w.Header().Set("Cache-Control", "no-store")
+		var buf bytes.Buffer
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case f := <-frames:
+				buf.Reset()
+				if err := jpeg.Encode(&buf, dev.FrameImage(f, scale), nil); err != nil {
+					return
+				}
+				fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", mjpegBound, buf.Len())
+				w.Write(buf.Bytes())
+				if _, err := fmt.Fprint(w, "\r\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	})
+	s.mux.HandleFunc("/display/events", func(w http.ResponseWriter, r *http.Request) {
+		scale, ok := queryScale(w, r)
+		if !ok {
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		frames, cancel := m.Subscribe()
+		defer cancel()
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-store")
+		var buf bytes.Buffer
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case f := <-frames:
+				buf.Reset()
+				if err := dev.EncodePNG(&buf, f, scale); err != nil {
+					return
+				}
+				_, err := fmt.Fprintf(w, "event: frame\ndata: %s\n\n", base64.StdEncoding.EncodeToString(buf.Bytes()))
+				if err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	})
+}
+
+// queryScale parses the scale parameter, it writes the error response when
+// invalid.
+func queryScale(w http.ResponseWriter, r *http.Request) (int, bool) {
+	q := r.URL.Query().Get("scale")
+	if q == "" {
+		return defaultScale, true
+	}
+	scale, err := strconv.Atoi(q)
+	if err != nil || scale < 1 || scale > maxScale {
+		http.Error(w, fmt.Sprintf("scale must be between 1 and %d", maxScale), http.StatusBadRequest)
+		return 0, false
+	}
+	return scale, true
+}
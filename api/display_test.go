@@ -0,0 +1,182 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"pi/dev"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newMirror(t *testing.T) *dev.Mirror {
+	e, err := dev.NewEmulator(&dev.EmulatorOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.SetOutput(ioutil.Discard)
+	return dev.NewMirror(e)
+}
+
+func newDisplayServer(t *testing.T) (*dev.Mirror, *httptest.Server) {
+	m := newMirror(t)
+	s := NewServer(&Options{})
+	s.HandleDisplay(m)
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return m, ts
+}
+
+func TestSnapshot(t *testing.T) {
+	m, ts := newDisplayServer(t)
+	dev.ShowText(m, dev.PosTopLeft, "snap")
+
+	resp, err := http.Get(ts.URL + "/display/snapshot.png?scale=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "image/png" {
+		t.Fatalf("content type %q", ct)
+	}
+	img, err := png.Decode(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 256, 128) {
+		t.Fatalf("bounds = %v", img.Bounds())
+	}
+	if lit(img) == 0 {
+		t.Fatal("snapshot is blank")
+	}
+
+	m.Off()
+	resp, err = http.Get(ts.URL + "/display/snapshot.png?scale=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err = png.Decode(resp.Body)
+	resp.Body.Close()
+	if err != nil || lit(img) != 0 {
+		t.Fatalf("snapshot of the turned off panel: %v, %d lit", err, lit(img))
+	}
+
+	for _, q := range []string{"0", "17", "x"} {
+		resp, err = http.Get(ts.URL + "/display/snapshot.png?scale=" + q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("scale=%s: status %d", q, resp.StatusCode)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	m, ts := newDisplayServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/display/events?scale=1", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type %q", ct)
+	}
+	events := sseFrames(resp.Body)
+
+	// The current frame comes first, then each drawn one.
+	if first := <-events; lit(first) != 0 {
+		t.Fatal("first frame not blank")
+	}
+	dev.ShowText(m, dev.PosTopLeft, "event")
+	if next := <-events; next == nil || lit(next) == 0 {
+		t.Fatal("drawn frame not streamed")
+	}
+}
+
+func TestMJPEG(t *testing.T) {
+	m, ts := newDisplayServer(t)
+	dev.ShowText(m, dev.PosTopLeft, "mjpeg")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/display/stream.mjpeg", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "multipart/x-mixed-replace") {
+		t.Fatalf("content type %q", ct)
+	}
+	r := bufio.NewReader(resp.Body)
+	if line, _ := r.ReadString('\n'); line != "--frame\r\n" {
+		t.Fatalf("boundary line %q", line)
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line == "\r\n" {
+			break
+		}
+	}
+	img, err := jpeg.Decode(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 512, 256) {
+		t.Fatalf("bounds = %v", img.Bounds())
+	}
+}
+
+// sseFrames decodes the frame events read from body.
+func sseFrames(body interface{ Read([]byte) (int, error) }) <-chan image.Image {
+	ch := make(chan image.Image)
+	go func() {
+		defer close(ch)
+		sc := bufio.NewScanner(body)
+		sc.Buffer(nil, 1<<20)
+		for sc.Scan() {
+			data := strings.TrimPrefix(sc.Text(), "data: ")
+			if data == sc.Text() {
+				continue
+			}
+			b, err := base64.StdEncoding.DecodeString(data)
+			if err != nil {
+				return
+			}
+			img, err := png.Decode(bytes.NewReader(b))
+			if err != nil {
+				return
+			}
+			ch <- img
+		}
+	}()
+	return ch
+}
+
+func lit(img image.Image) int {
+	n := 0
+	r := img.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if v, _, _, _ := img.At(x, y).RGBA(); v > 0x8000 {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+	"pi/event"
+	"pi/log"
+	"sort"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// pingPeriod is how often an idle WebSocket is pinged.
+	pingPeriod = 30 * time.Second
+	// writeWait is how long a WebSocket write may take.
+	writeWait = 10 * time.Second
+)
+
+// upgrader accepts the WebSockets of the same origin only.
+var upgrader = websocket.Upgrader{ReadBufferSize: 1024, WriteBufferSize: 4096}
+
+// HandleEvents adds the live events of bus:
+//
+//	GET /api/events  WebSocket of JSON events {device, name, value, unit, time}
+//
+// The latest event of each device and name is sent first, then each one as
+// published. An optional device query parameter, repeated, selects the
+// devices. Messages from the client are ignored.
+func (s *Server) HandleEvents(bus *event.Bus) {
+	s.mux.HandleFunc("/api/events", func(w http.ResponseWriter, r *http.Request) {
+		var devices map[string]bool
+		if names := r.URL.Query()["device"]; len(names) > 0 {
+			devices = make(map[string]bool, len(names))
+			for _, name := range names {
+				devices[name] = true
+			}
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// The upgrader answered the error.
+			return
+		}
+		defer conn.Close()
+		events, cancel := bus.Subscribe(64)
+		defer cancel()
+
+		// Read until the client leaves, for the control frames.
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+		send := func(e event.Event) bool {
+			if devices != nil && !devices[e.Device] {
+				return true
+			}
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteJSON(e); err != nil {
+				log.Default().Debug("events websocket: ", err)
+				return false
+			}
+			return true
+		}
+		latest := bus.Snapshot()
+		sort.Slice(latest, func(i, j int) bool {
+			if latest[i].Device != latest[j].Device {
+				return latest[i].Device < latest[j].Device
+			}
+			return latest[i].Name < latest[j].Name
+		})
+		for _, e := range latest {
+			if !send(e) {
+				return
+			}
+		}
+		ping := time.NewTicker(pingPeriod)
+		defer ping.Stop()
+		for {
+			select {
+			case <-closed:
+				return
+			case <-r.Context().Done():
+				return
+			case e := <-events:
+				if !send(e) {
+					return
+				}
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
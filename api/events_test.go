@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http/httptest"
+	"pi/event"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestEventStream(t *testing.T) {
+	bus := event.New()
+	bus.Publish(event.Event{Device: "temp", Name: "temperature", Value: 21.5, Unit: "°C"})
+	bus.Publish(event.Event{Device: "led1", Name: "state", Value: 0})
+	s := NewServer(&Options{})
+	s.HandleEvents(bus)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/events"
+
+	read := func(conn *websocket.Conn) event.Event {
+		t.Helper()
+		var e event.Event
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if err := conn.ReadJSON(&e); err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+	all, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer all.Close()
+	leds, _, err := websocket.DefaultDialer.Dial(url+"?device=led1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer leds.Close()
+
+	// The snapshot, sorted by device.
+	if e := read(all); e.Device != "led1" || e.Name != "state" {
+		t.Errorf("first event = %+v, want led1 state", e)
+	}
+	if e := read(all); e.Device != "temp" || e.Value != 21.5 || e.Unit != "°C" {
+		t.Errorf("second event = %+v, want temp 21.5 °C", e)
+	}
+	if e := read(leds); e.Device != "led1" {
+		t.Errorf("first led event = %+v, want led1", e)
+	}
+
+	bus.Publish(event.Event{Device: "temp", Name: "temperature", Value: 22, Unit: "°C"})
+	bus.Publish(event.Event{Device: "led1", Name: "state", Value: 1})
+	if e := read(all); e.Device != "temp" || e.Value != 22 {
+		t.Errorf("published event = %+v, want temp 22", e)
+	}
+	if e := read(all); e.Device != "led1" || e.Value != 1 {
+		t.Errorf("published event = %+v, want led1 1", e)
+	}
+	if e := read(leds); e.Device != "led1" || e.Value != 1 {
+		t.Errorf("filtered event = %+v, want led1 1", e)
+	}
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"pi/dev"
+)
+
+// HandleHealth adds the device report of r:
+//
+//	GET /health  JSON list of {name, driver, state, error, since}
+//
+// The status is 503 when a device failed, for the probes of a supervisor.
+func (s *Server) HandleHealth(r *dev.HealthRegistry) {
+	s.mux.HandleFunc("/health", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		report := r.Report()
+		status := http.StatusOK
+		for _, h := range report {
+			if h.State == dev.HealthFailed {
+				status = http.StatusServiceUnavailable
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(report)
+	})
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"pi/dev"
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+	e, err := dev.NewEmulator(&dev.EmulatorOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.SetOutput(ioutil.Discard)
+	e.Init(context.Background())
+	r := dev.NewHealthRegistry()
+	r.Add("oled", e)
+	s := NewServer(&Options{})
+	s.HandleHealth(r)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	get := func() (int, []dev.DeviceHealth) {
+		resp, err := http.Get(ts.URL + "/health")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var report []dev.DeviceHealth
+		if err = json.NewDecoder(resp.Body).Decode(&report); err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, report
+	}
+	status, report := get()
+	if status != http.StatusOK || len(report) != 1 || report[0].Driver != "emulator:terminal" || report[0].State != dev.HealthPresent {
+		t.Fatalf("%d %+v", status, report)
+	}
+
+	r.Fail("rtc", errors.New("open /dev/i2c-1: no such file or directory"))
+	status, report = get()
+	if status != http.StatusServiceUnavailable || len(report) != 2 || report[1].State != dev.HealthFailed {
+		t.Fatalf("%d %+v", status, report)
+	}
+
+	resp, err := http.Post(ts.URL+"/health", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST status %d", resp.StatusCode)
+	}
+}
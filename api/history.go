@@ -0,0 +1,104 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"pi/history"
+	"strings"
+	"time"
+)
+
+// HandleHistory adds the readings recorded in h:
+//
+//	GET /api/history                          names of the series, device/name
+//	GET /api/history/{device}/{name}          points from from (default -24h)
+//	                                          to to (default now) by step, raw
+//	                                          samples without, format json or
+//	                                          csv
+//	GET /api/history/{device}/{name}/summary  {"min", "max", "mean", "count"}
+//	                                          from from to to
+//
+// Times are RFC 3339, now or durations from now such as -1h.
+func (s *Server) HandleHistory(h *history.Store) {
+	s.mux.HandleFunc("/api/history", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJSON(w, http.StatusOK, h.Series())
+	})
+	s.mux.HandleFunc("/api/history/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/history/"), "/"), "/")
+		if len(parts) < 2 || len(parts) > 3 || len(parts) == 3 && parts[2] != "summary" {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		series := parts[0] + "/" + parts[1]
+		from, to, step, err := historyQuery(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if len(parts) == 3 {
+			p, err := h.Aggregate(series, from, to)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			summary := map[string]interface{}{"count": p.Count}
+			if p.Count > 0 {
+				summary["min"], summary["max"], summary["mean"] = p.Min, p.Max, p.Mean()
+			}
+			writeJSON(w, http.StatusOK, summary)
+			return
+		}
+		points, err := h.Query(series, from, to, step)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		switch r.URL.Query().Get("format") {
+		case "", "json":
+			w.Header().Set("Content-Type", "application/json")
+			history.WriteJSON(w, series, points)
+		case "csv":
+			w.Header().Set("Content-Type", "text/csv")
+			w.Header().Set("Content-Disposition", `attachment; filename="`+parts[0]+"-"+parts[1]+`.csv"`)
+			history.WriteCSV(w, series, points)
+		default:
+			writeError(w, http.StatusBadRequest, "format must be json or csv")
+		}
+	})
+}
+
+// historyQuery parses the from, to and step parameters of r.
+func historyQuery(r *http.Request) (from, to time.Time, step time.Duration, err error) {
+	q := r.URL.Query()
+	now := time.Now()
+	get := func(name, def string) string {
+		if v := q.Get(name); v != "" {
+			return v
+		}
+		return def
+	}
+	if to, err = history.ParseTime(get("to", "now"), now); err != nil {
+		return
+	}
+	if from, err = history.ParseTime(get("from", "-24h"), now); err != nil {
+		return
+	}
+	if !from.Before(to) {
+		err = fmt.Errorf("from %v is not before to %v", from, to)
+		return
+	}
+	if s := q.Get("step"); s != "" {
+		if step, err = time.ParseDuration(s); err == nil && step < 0 {
+			err = fmt.Errorf("step %v is negative", step)
+		}
+	}
+	return
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"pi/history"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	h, err := history.Open(&history.Options{Path: dir, Segment: time.Hour, Retention: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		h.Append("temp/temperature", now.Add(time.Duration(i-4)*time.Minute), 20+float64(i))
+	}
+	s := NewServer(&Options{})
+	s.HandleHistory(h)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+	for _, c := range []struct {
+		path   string
+		status int
+		want   string
+	}{
+		{"/api/history", 200, `["temp/temperature"]`},
+		{"/api/history/temp/temperature?from=-2m", 200, `"max":23,"mean":23,"count":1}]}`},
+		{"/api/history/temp/temperature?from=-1h&step=1m", 200, `"min":20,"max":20,"mean":20,"count":1},{`},
+		{"/api/history/temp/temperature/summary?from=-1h", 200, `{"count":4,"max":23,"mean":21.5,"min":20}`},
+		{"/api/history/temp/temperature?format=csv&from=-2m", 200, "temp/temperature,23,23,23,1\n"},
+		{"/api/history/temp/temperature?from=tomorrow", 400, "cannot parse"},
+		{"/api/history/temp/temperature?from=now", 400, "is not before"},
+		{"/api/history/temp/temperature?format=xml", 400, "json or csv"},
+		{"/api/history/temp", 404, "not found"},
+	} {
+		status, body := get(c.path)
+		if status != c.status || !strings.Contains(body, c.want) {
+			t.Errorf("GET %s = %d %s, want %d %s", c.path, status, body, c.status, c.want)
+		}
+	}
+}
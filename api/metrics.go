@@ -0,0 +1,48 @@
+package api
+
+import (
+	"pi/driver"
+	"pi/event"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var readingDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(driver.Namespace, "", "reading"),
+	"Latest sensor reading by device, name and unit.",
+	[]string{"device", "name", "unit"}, nil,
+)
+
+// readings collects the latest readings of a bus as gauges.
+type readings struct {
+	bus *event.Bus
+}
+
+func (c readings) Describe(ch chan<- *prometheus.Desc) {
+	ch <- readingDesc
+}
+
+func (c readings) Collect(ch chan<- prometheus.Metric) {
+	for _, e := range c.bus.Snapshot() {
+		if e.Unit != "" {
+			ch <- prometheus.MustNewConstMetric(readingDesc, prometheus.GaugeValue, e.Value, e.Device, e.Name, e.Unit)
+		}
+	}
+}
+
+// HandleMetrics adds the Prometheus metrics:
+//
+//	GET /metrics  readings of bus, bus transactions, errors and read
+//	              latencies, GPIO export failures, display frame times,
+//	              Go runtime and process metrics
+func (s *Server) HandleMetrics(bus *event.Bus) {
+	r := prometheus.NewRegistry()
+	r.MustRegister(
+		readings{bus: bus},
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+	r.MustRegister(driver.Collectors()...)
+	s.mux.Handle("/metrics", promhttp.HandlerFor(r, promhttp.HandlerOpts{}))
+}
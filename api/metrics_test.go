@@ -0,0 +1,63 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"pi/driver"
+	"pi/event"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = os.Mkdir(filepath.Join(dir, "28-00000a1b2c3d"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	slave := "13 02 4b 46 7f ff 0d 10 e7 : crc=e7 YES\n13 02 4b 46 7f ff 0d 10 e7 t=33187\n"
+	if err = ioutil.WriteFile(filepath.Join(dir, "28-00000a1b2c3d", "w1_slave"), []byte(slave), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"28-00000a1b2c3d", "28-00000a1b2c3d", "28-0000deadbeef"} {
+		driver.ReadW1Slave(dir, id)
+	}
+
+	bus := event.New()
+	bus.Publish(event.Event{Device: "temp", Name: "temperature", Value: 33.187, Unit: "°C"})
+	bus.Publish(event.Event{Device: "led1", Name: "state", Value: 1})
+	s := NewServer(&Options{})
+	s.HandleMetrics(bus)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	resp, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics := string(body)
+	for _, want := range []string{
+		`pioneer600_reading{device="temp",name="temperature",unit="°C"} 33.187`,
+		`pioneer600_bus_transactions_total{address="28-00000a1b2c3d",bus="` + dir + `",type="1-wire"} 2`,
+		`pioneer600_bus_transaction_errors_total{address="28-0000deadbeef",bus="` + dir + `",type="1-wire"} 1`,
+		`pioneer600_bus_read_duration_seconds_count{address="28-00000a1b2c3d",bus="` + dir + `",type="1-wire"} 2`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("no %s in\n%s", want, metrics)
+		}
+	}
+	if strings.Contains(metrics, `device="led1"`) {
+		t.Error("state exported as a reading")
+	}
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// maxBody is the size limit of the JSON request bodies.
+const maxBody = 64 << 10
+
+// Schema is the subset of JSON Schema describing the request bodies.
+type Schema struct {
+	Type        string             `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	// Additional is false for the objects, unknown properties are errors.
+	Additional *bool    `json:"additionalProperties,omitempty"`
+	Enum       []string `json:"enum,omitempty"`
+	Minimum    *float64 `json:"minimum,omitempty"`
+	Maximum    *float64 `json:"maximum,omitempty"`
+	MaxLength  *int     `json:"maxLength,omitempty"`
+	Items      *Schema  `json:"items,omitempty"`
+	MinItems   *int     `json:"minItems,omitempty"`
+	MaxItems   *int     `json:"maxItems,omitempty"`
+}
+
+// object returns the schema of an object with properties, no other
+// property is accepted.
+func object(properties map[string]*Schema, required ...string) *Schema {
+	closed := false
+	return &Schema{Type: "object", Properties: properties, Required: required, Additional: &closed}
+}
+
+// number returns the schema of a number in [min, max].
+func number(min, max float64, description string) *Schema {
+	return &Schema{Type: "number", Minimum: &min, Maximum: &max, Description: description}
+}
+
+// integer returns the schema of an integer in [min, max].
+func integer(min, max float64, description string) *Schema {
+	return &Schema{Type: "integer", Minimum: &min, Maximum: &max, Description: description}
+}
+
+// Validate returns the errors of v decoded from JSON, prefixed by their
+// path from at.
+func (s *Schema) Validate(v interface{}, at string) []string {
+	var errs []string
+	fail := func(format string, args ...interface{}) []string {
+		return append(errs, at+": "+fmt.Sprintf(format, args...))
+	}
+	switch s.Type {
+	case "object":
+		o, ok := v.(map[string]interface{})
+		if !ok {
+			return fail("must be an object")
+		}
+		for _, name := range s.Required {
+			if _, ok := o[name]; !ok {
+				errs = fail("%s is required", name)
+			}
+		}
+		names := make([]string, 0, len(o))
+		for name := range o {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p, ok := s.Properties[name]
+			if !ok {
+				if s.Additional != nil && !*s.Additional {
+					errs = fail("unknown property %s", name)
+				}
+				continue
+			}
+			errs = append(errs, p.Validate(o[name], at+"."+name)...)
+		}
+	case "array":
+		a, ok := v.([]interface{})
+		if !ok {
+			return fail("must be an array")
+		}
+		if s.MinItems != nil && len(a) < *s.MinItems {
+			errs = fail("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(a) > *s.MaxItems {
+			errs = fail("must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range a {
+				errs = append(errs, s.Items.Validate(item, fmt.Sprintf("%s[%d]", at, i))...)
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fail("must be a string")
+		}
+		if s.MaxLength != nil && len([]rune(str)) > *s.MaxLength {
+			errs = fail("must be at most %d characters", *s.MaxLength)
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, str) {
+			errs = fail("must be one of %s", strings.Join(s.Enum, ", "))
+		}
+	case "number", "integer":
+		n, ok := v.(float64)
+		if !ok {
+			return fail("must be a number")
+		}
+		if s.Type == "integer" && n != math.Trunc(n) {
+			errs = fail("must be an integer")
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			errs = fail("%v is below the minimum %v", n, *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			errs = fail("%v is above the maximum %v", n, *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fail("must be a boolean")
+		}
+	}
+	return errs
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeJSON reads the JSON body of r into dst once valid against s. It
+// answers 400 with the errors and returns false otherwise. The body must be
+// sent as application/json, which a page of another site cannot post
+// without the browser asking first.
+func decodeJSON(w http.ResponseWriter, r *http.Request, s *Schema, dst interface{}) bool {
+	if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mt != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return false
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBody+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	if len(body) > maxBody {
+		writeError(w, http.StatusRequestEntityTooLarge, "body over 64KiB")
+		return false
+	}
+	var v interface{}
+	if err = json.Unmarshal(body, &v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return false
+	}
+	if errs := s.Validate(v, "body"); len(errs) > 0 {
+		writeJSON(w, http.StatusBadRequest, map[string][]string{"errors": errs})
+		return false
+	}
+	if err = json.Unmarshal(body, dst); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+// writeJSON answers status with v as JSON.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers status with {"error": msg}.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"pi/log"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Options is http configuration struct
+type Options struct {
+	// Listen is the server address, eg. "127.0.0.1:8080", empty disables
+	// it. The API has no authentication, ":8080" opens it to the network.
+	Listen string
+}
+
+// NewOptions reads the http section of the configuration.
+func NewOptions(v *viper.Viper) (*Options, error) {
+	o := new(Options)
+	if err := v.UnmarshalKey("http", o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Server is the HTTP server of the board, handlers are added per feature.
+type Server struct {
+	opts Options
+	mux  *http.ServeMux
+}
+
+// NewServer creates a new Server without handlers.
+func NewServer(o *Options) *Server {
+	return &Server{opts: *o, mux: http.NewServeMux()}
+}
+
+// ServeHTTP implements http.Handler. The requests changing the devices
+// are refused when a browser sends them from a page of another site.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		if !sameOrigin(r) {
+			writeError(w, http.StatusForbidden, "cross-origin request refused")
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// sameOrigin reports whether the Origin of r, if any, is the server host.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// Run serves on opts.Listen until ctx is done.
+func (s *Server) Run(ctx context.Context) error {
+	srv := &http.Server{Addr: s.opts.Listen, Handler: s}
+	errc := make(chan error, 1)
+	go func() {
+		log.Default().Info("http server listening on ", s.opts.Listen)
+		errc <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdown)
+}
@@ -0,0 +1,69 @@
+// Package clock tells the time of the DS3231 real time clock from its
+// readings, without sharing the I2C bus with its worker.
+package clock
+
+import (
+	"os"
+	"pi/event"
+	"time"
+)
+
+// Synchronized is the file systemd-timesyncd creates once it set the
+// system clock.
+const Synchronized = "/run/systemd/timesync/synchronized"
+
+// Epoch is a time the system clock is past once set, a Raspberry Pi
+// without network boots in 1970 or at its last shutdown.
+var Epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Clock is the time of the DS3231 of a bus, the system time without one.
+type Clock struct {
+	bus    *event.Bus
+	synced string
+	now    func() time.Time
+}
+
+// New creates a new Clock of the "time" readings of bus.
+func New(bus *event.Bus) *Clock {
+	return &Clock{bus: bus, synced: Synchronized, now: time.Now}
+}
+
+// RTC returns the time of the latest DS3231 reading advanced by the time
+// since, false without a reading.
+func (c *Clock) RTC() (time.Time, bool) {
+	for _, e := range c.bus.Snapshot() {
+		if e.Name == "time" && e.Unit == "s" {
+			return time.Unix(int64(e.Value), 0).Add(time.Since(e.Time)), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Now returns the DS3231 time, the system time without a reading.
+func (c *Clock) Now() time.Time {
+	if t, ok := c.RTC(); ok {
+		return t
+	}
+	return time.Now()
+}
+
+// Trusted reports whether the system clock was synchronized over the
+// network, or is past Epoch without a DS3231 to prefer.
+func (c *Clock) Trusted() bool {
+	if _, err := os.Stat(c.synced); err == nil {
+		return true
+	}
+	if _, ok := c.RTC(); ok {
+		return false
+	}
+	return !c.now().Before(Epoch)
+}
+
+// Wall returns the system time when trusted, the DS3231 time otherwise.
+// It is false while neither is known.
+func (c *Clock) Wall() (time.Time, bool) {
+	if c.Trusted() {
+		return c.now(), true
+	}
+	return c.RTC()
+}
@@ -0,0 +1,35 @@
+package clock
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"pi/event"
+	"testing"
+	"time"
+)
+
+func TestWall(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bus := event.New()
+	boot := time.Date(1970, 1, 1, 0, 5, 0, 0, time.UTC)
+	c := &Clock{bus: bus, synced: filepath.Join(dir, "synchronized"), now: func() time.Time { return boot }}
+	if _, ok := c.Wall(); ok {
+		t.Error("unset clock known")
+	}
+	rtc := time.Date(2026, 10, 19, 7, 30, 0, 0, time.UTC)
+	bus.Publish(event.Event{Device: "rtc", Name: "time", Value: float64(rtc.Unix()), Unit: "s", Time: time.Now()})
+	if now, ok := c.Wall(); !ok || now.Sub(rtc) > time.Minute {
+		t.Errorf("untrusted clock %v, %v", now, ok)
+	}
+	if err := ioutil.WriteFile(c.synced, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if now, ok := c.Wall(); !ok || !now.Equal(boot) {
+		t.Errorf("synchronized clock %v, %v", now, ok)
+	}
+}
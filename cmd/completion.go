@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+// Completion scripts calling the binary with --generate-bash-completion,
+// PROG is replaced by the binary name.
+const (
+	bashCompletion = `_PROG_bash_autocomplete() {
+  local cur opts
+  COMPREPLY=()
+  cur="${COMP_WORDS[COMP_CWORD]}"
+  if [[ "$cur" == "-"* ]]; then
+    opts=$( ${COMP_WORDS[@]:0:$COMP_CWORD} ${cur} --generate-bash-completion )
+  else
+    opts=$( ${COMP_WORDS[@]:0:$COMP_CWORD} --generate-bash-completion )
+  fi
+  COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+  return 0
+}
+complete -o bashdefault -o default -o nospace -F _PROG_bash_autocomplete PROG
+`
+	zshCompletion = `#compdef PROG
+_PROG_zsh_autocomplete() {
+  local -a opts
+  local cur
+  cur=${words[-1]}
+  if [[ "$cur" == "-"* ]]; then
+    opts=("${(@f)$(_CLI_ZSH_AUTOCOMPLETE_HACK=1 ${words[@]:0:#words[@]-1} ${cur} --generate-bash-completion)}")
+  else
+    opts=("${(@f)$(_CLI_ZSH_AUTOCOMPLETE_HACK=1 ${words[@]:0:#words[@]-1} --generate-bash-completion)}")
+  fi
+  if [[ "${opts[1]}" != "" ]]; then
+    _describe 'values' opts
+  else
+    _files
+  fi
+}
+compdef _PROG_zsh_autocomplete PROG
+`
+)
+
+var completionCommand = cli.Command{
+	Name:         "completion",
+	Usage:        "Print the shell completion script, eg. source <(Pioneer600 completion bash)",
+	ArgsUsage:    "bash|zsh",
+	BashComplete: completeArgs("bash", "zsh"),
+	Action: func(c *cli.Context) error {
+		script := bashCompletion
+		switch c.Args().First() {
+		case "", "bash":
+		case "zsh":
+			script = zshCompletion
+		default:
+			return fmt.Errorf("unknown shell %q, want bash or zsh", c.Args().First())
+		}
+		fmt.Print(strings.Replace(script, "PROG", c.App.Name, -1))
+		return nil
+	},
+}
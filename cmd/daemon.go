@@ -0,0 +1,533 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"pi/alert"
+	"pi/api"
+	"pi/clock"
+	"pi/control"
+	"pi/daemon"
+	"pi/dev"
+	"pi/event"
+	"pi/history"
+	"pi/log"
+	"pi/mqtt"
+	"pi/rpc"
+	"pi/schedule"
+	"pi/ui"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/urfave/cli"
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// loadDevices reads the devices section of the configuration.
+func loadDevices() (*dev.Registry, []dev.DeviceConfig, error) {
+	registry := dev.NewRegistry()
+	configs, err := dev.LoadDevices(config, registry)
+	return registry, configs, err
+}
+
+// runDaemon runs a worker per configured device until SIGINT or SIGTERM,
+// then turns the LEDs and the buzzer off, blanks the OLED and unexports the
+// pins.
+func runDaemon(c *cli.Context) error {
+	logger := log.Default()
+	//Out Some Target for project
+	logger.Info("Raspberry Pi 4 and Pioneer600")
+	logger.Info("Learn how to use golang control devices.")
+	logger.Info("Thanks to https://gobot.io")
+	logger.Info("Thanks to http://www.waveshare.net.")
+	logger.Info("Thanks to https://periph.io/project/goals.")
+	o, err := daemon.NewOptions(config)
+	if err != nil {
+		return err
+	}
+	registry, configs, err := loadDevices()
+	if err != nil {
+		return err
+	}
+	server, err := newServer(config)
+	if err != nil {
+		return err
+	}
+	mo, err := mqtt.NewOptions(config)
+	if err != nil {
+		return err
+	}
+	ho, err := history.NewOptions(config)
+	if err != nil {
+		return err
+	}
+	rules, err := alert.NewRules(config)
+	if err != nil {
+		return err
+	}
+	so, err := schedule.NewOptions(config)
+	if err != nil {
+		return err
+	}
+	ro, err := rpc.NewOptions(config)
+	if err != nil {
+		return err
+	}
+	r := &reloader{
+		registry:   registry,
+		bus:        event.New(),
+		health:     dev.NewHealthRegistry(),
+		supervisor: daemon.NewSupervisor(o),
+		devices:    make(map[string]*device),
+		configs:    configs,
+		holds:      newHolds(),
+	}
+	var store *history.Store
+	if ho.Path != "" {
+		if store, err = history.Open(ho); err != nil {
+			return err
+		}
+		r.supervisor.Add(daemon.Worker{Name: "history", Run: func(ctx context.Context) error {
+			return store.Run(ctx, r.bus)
+		}})
+	}
+	rtc := clock.New(r.bus)
+	for i := range configs {
+		cfg := configs[i]
+		if cfg.Type == dev.TypeSSD1306 {
+			status, power := oledWorkers(c, &cfg, server, rtc, r.bus, r.health, r.holds, store)
+			r.supervisor.Add(status)
+			r.supervisor.Add(power)
+			continue
+		}
+		r.start(cfg)
+	}
+	ctl := control.New(r.health, r.bus, r.holds.Hold)
+	r.alerts = alert.New(ctl, r.bus, rtc.Now)
+	r.alerts.SetRules(rules)
+	r.supervisor.Add(daemon.Worker{Name: "alerts", Run: r.alerts.Run})
+	r.scheduler = schedule.New(ctl, r.bus, rtc.Wall, so.State)
+	r.scheduler.SetJobs(so.Jobs)
+	r.supervisor.Add(daemon.Worker{Name: "schedule", Run: r.scheduler.Run})
+	if mo.Broker != "" {
+		r.bridge = mqtt.NewBridge(mo, ctl, r.bus)
+		r.bridge.SetDevices(configs)
+		r.supervisor.Add(daemon.Worker{Name: "mqtt", Run: r.bridge.Run})
+	}
+	if ro.Listen != "" {
+		r.supervisor.Add(daemon.Worker{Name: "grpc", Run: rpc.NewServer(ro, ctl, r.bus).Run})
+	}
+	ctx, cancel := signalContext()
+	defer cancel()
+	if server != nil {
+		server.HandleHealth(r.health)
+		server.HandleDevices(ctl)
+		server.HandleMetrics(r.bus)
+		if store != nil {
+			server.HandleHistory(store)
+		}
+		server.HandleEvents(r.bus)
+		r.dashboard = api.NewDashboard()
+		r.dashboard.SetDevices(configs)
+		server.HandleDashboard(r.dashboard)
+		go serve(ctx, server)
+	}
+	go r.watch(ctx, config.ConfigFileUsed())
+	err = r.supervisor.Run(ctx)
+	logger.Warn("shutting down server")
+	return err
+}
+
+// holds are the devices a command of the REST API or MQTT took over, the workers
+// leave them alone until a time.
+type holds struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+func newHolds() *holds {
+	return &holds{until: make(map[string]time.Time)}
+}
+
+// Hold leaves name alone for d.
+func (h *holds) Hold(name string, d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.until[name] = time.Now().Add(d)
+}
+
+// held reports whether name is held now.
+func (h *holds) held(name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return time.Now().Before(h.until[name])
+}
+
+// device is a device run by the daemon, its interval can be changed while
+// it runs.
+type device struct {
+	cfg      dev.DeviceConfig
+	interval int64 // time.Duration, atomic
+}
+
+func newDevice(cfg dev.DeviceConfig) *device {
+	return &device{cfg: cfg, interval: int64(cfg.Interval)}
+}
+
+// Interval returns the current polling interval.
+func (d *device) Interval() time.Duration {
+	return time.Duration(atomic.LoadInt64(&d.interval))
+}
+
+// SetInterval changes the polling interval from the next poll.
+func (d *device) SetInterval(interval time.Duration) {
+	atomic.StoreInt64(&d.interval, int64(interval))
+}
+
+// deviceWorker opens the device on its first run, so a missing chip is
+// retried with the supervisor backoff, then polls it every interval. The
+// device health is reported to health.
+func deviceWorker(registry *dev.Registry, dv *device, bus *event.Bus, health *dev.HealthRegistry, holds *holds) daemon.Worker {
+	cfg := &dv.cfg
+	var d dev.Device
+	open := func(ctx context.Context) error {
+		if d != nil {
+			return nil
+		}
+		var err error
+		if d, err = registry.Open(ctx, cfg); err != nil {
+			health.Fail(cfg.Name, err)
+			return err
+		}
+		health.Add(cfg.Name, d)
+		return nil
+	}
+	poll := func(ctx context.Context, fn func() error) error {
+		if err := open(ctx); err != nil {
+			return err
+		}
+		for {
+			if err := fn(); err != nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(dv.Interval()):
+			}
+		}
+	}
+	// idle leaves the device in its safe state until shutdown.
+	idle := func(ctx context.Context, off func() error) error {
+		if err := open(ctx); err != nil {
+			return err
+		}
+		if err := off(); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	publish := func(name string, value float64, unit string) {
+		bus.Publish(event.Event{Device: cfg.Name, Name: name, Value: value, Unit: unit})
+	}
+	w := daemon.Worker{
+		Name: cfg.Name,
+		Stop: func() error {
+			if d == nil {
+				return nil
+			}
+			return d.Close()
+		},
+	}
+	switch cfg.Type {
+	case dev.TypeLED:
+		// Blink as a heartbeat, unless set through the REST API.
+		w.Run = func(ctx context.Context) error {
+			return poll(ctx, func() error {
+				if holds.held(cfg.Name) {
+					return nil
+				}
+				led := d.(*dev.LEDOne)
+				if err := led.Toggle(); err != nil {
+					return err
+				}
+				publish("state", float64(led.Status()), "")
+				return nil
+			})
+		}
+	case dev.TypePCF8574LED:
+		w.Run = func(ctx context.Context) error {
+			return idle(ctx, func() error { return d.(*dev.PCF8574LED).LED2Off() })
+		}
+	case dev.TypeBuzzer:
+		w.Run = func(ctx context.Context) error {
+			return idle(ctx, func() error { return d.(*dev.PCF8574Beep).Off() })
+		}
+	case dev.TypeDS18B20:
+		w.Run = func(ctx context.Context) error {
+			return poll(ctx, func() error {
+				s := d.(*dev.DS18B20)
+				if err := s.FetchTemperate(); err != nil {
+					return err
+				}
+				publish("temperature", s.Temperate(), "°C")
+				return nil
+			})
+		}
+	case dev.TypeDS3231:
+		// Publish the RTC time and how far it is from the system clock.
+		w.Run = func(ctx context.Context) error {
+			return poll(ctx, func() error {
+				t, err := d.(*dev.DS3231).Now()
+				if err != nil {
+					return err
+				}
+				publish("time", float64(t.Unix()), "s")
+				publish("drift", t.Sub(time.Now()).Seconds(), "s")
+				return nil
+			})
+		}
+	case dev.TypeBMP180:
+		w.Run = func(ctx context.Context) error {
+			return poll(ctx, func() error {
+				temperature, pressure, err := d.(*dev.BMP180).Read()
+				if err != nil {
+					return err
+				}
+				publish("temperature", temperature, "°C")
+				publish("pressure", pressure, "Pa")
+				return nil
+			})
+		}
+	case dev.TypeJoystick:
+		w.Run = func(ctx context.Context) error {
+			var prev dev.JoystickState
+			first := true
+			return poll(ctx, func() error {
+				s, err := d.(*dev.Joystick).Read()
+				if err != nil {
+					return err
+				}
+				for _, b := range []struct {
+					name     string
+					now, was bool
+				}{
+					{"up", s.Up, prev.Up},
+					{"down", s.Down, prev.Down},
+					{"left", s.Left, prev.Left},
+					{"right", s.Right, prev.Right},
+					{"press", s.Press, prev.Press},
+				} {
+					if first || b.now != b.was {
+						publish(b.name, boolValue(b.now), "")
+					}
+				}
+				prev, first = s, false
+				return nil
+			})
+		}
+	}
+	return w
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// rtcTime is the dev.TimeSource of a clock.Clock.
+type rtcTime struct {
+	clock *clock.Clock
+}
+
+func (t rtcTime) Now() (time.Time, error) {
+	return t.clock.Now(), nil
+}
+
+// commandDisplay is the OLED given to the commands, what they draw wakes
+// the panel up.
+type commandDisplay struct {
+	*dev.PowerManager
+	dev.Device
+}
+
+func (d commandDisplay) DrawFrame(frame *image1bit.VerticalLSB) error {
+	if _, err := d.Wake(); err != nil {
+		return err
+	}
+	return d.PowerManager.DrawFrame(frame)
+}
+
+// pressed reports whether e is a joystick button going down.
+func pressed(e event.Event) bool {
+	switch e.Name {
+	case "up", "down", "left", "right", "press":
+		return e.Value == 1
+	}
+	return false
+}
+
+// oledWorkers open the OLED on the first run of status, so a missing panel
+// is retried with the supervisor backoff, then status shows the time and
+// the latest temperature unless a command holds the display, and power
+// applies the power policy. A joystick press or a command wakes the panel
+// up.
+func oledWorkers(c *cli.Context, cfg *dev.DeviceConfig, server *api.Server, rtc *clock.Clock, bus *event.Bus, health *dev.HealthRegistry, holds *holds, store *history.Store) (status, power daemon.Worker) {
+	var panel dev.Display
+	var popt dev.PowerOpts
+	var display *dev.PowerManager
+	opened := make(chan struct{})
+	// The panel is kept when the power manager fails, its remote view is
+	// registered once.
+	open := func() error {
+		if display != nil {
+			return nil
+		}
+		if panel == nil {
+			d, dopt, err := openDisplay(c, config, cfg, server)
+			if err != nil {
+				health.Fail(cfg.Name, err)
+				return err
+			}
+			panel, popt = d, dopt.Power
+		}
+		pm, err := dev.NewPowerManager(panel, &popt, rtcTime{rtc})
+		if err != nil {
+			health.Fail(cfg.Name, err)
+			return err
+		}
+		display = pm
+		health.Add(cfg.Name, commandDisplay{display, panel.(dev.Device)})
+		close(opened)
+		return nil
+	}
+	status = daemon.Worker{
+		Name: cfg.Name,
+		Run: func(ctx context.Context) error {
+			if err := open(); err != nil {
+				return err
+			}
+			t := time.NewTicker(time.Second)
+			defer t.Stop()
+			events, cancel := bus.Subscribe(16)
+			defer cancel()
+			var spark *ui.Sparkline
+			var drawn time.Time
+			draw := func() error {
+				if store != nil && time.Since(drawn) >= sparkStep {
+					spark, drawn = sparkline(display.Bounds(), bus, store), time.Now()
+				}
+				if holds.held(cfg.Name) {
+					return nil
+				}
+				return display.DrawFrame(statusFrame(display.Bounds(), bus, spark))
+			}
+			if err := draw(); err != nil {
+				return err
+			}
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case e := <-events:
+					if !pressed(e) {
+						continue
+					}
+					if _, err := display.Wake(); err != nil {
+						return err
+					}
+				case <-t.C:
+					if err := draw(); err != nil {
+						return err
+					}
+				}
+			}
+		},
+		Stop: func() error {
+			if panel == nil {
+				return nil
+			}
+			var err error
+			if display != nil {
+				err = display.DrawFrame(image1bit.NewVerticalLSB(display.Bounds()))
+			}
+			// Close halts the panel and unexports its pins.
+			if cerr := panel.(dev.Device).Close(); err == nil {
+				err = cerr
+			}
+			return err
+		},
+	}
+	power = daemon.Worker{
+		Name: cfg.Name + "-power",
+		Run: func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-opened:
+			}
+			return display.Run(ctx)
+		},
+	}
+	return status, power
+}
+
+// sparkStep is the time of a column of the status sparkline.
+const sparkStep = time.Minute
+
+// firstTemperature returns the temperature of the first device by name,
+// for a stable line with several sensors.
+func firstTemperature(bus *event.Bus) (first event.Event, ok bool) {
+	for _, e := range bus.Snapshot() {
+		if e.Name == "temperature" && (!ok || e.Device < first.Device) {
+			first, ok = e, true
+		}
+	}
+	return first, ok
+}
+
+// sparkline returns the recorded history of the first temperature along
+// the bottom of bounds, nil without one.
+func sparkline(bounds image.Rectangle, bus *event.Bus, store *history.Store) *ui.Sparkline {
+	e, ok := firstTemperature(bus)
+	if !ok {
+		return nil
+	}
+	r := image.Rect(bounds.Min.X, bounds.Max.Y-24, bounds.Max.X, bounds.Max.Y)
+	now := time.Now()
+	points, err := store.Query(e.Device+"/"+e.Name, now.Add(-time.Duration(r.Dx())*sparkStep), now, sparkStep)
+	if err != nil {
+		log.Default().Warn("sparkline error: ", err)
+		return nil
+	}
+	spark := ui.NewSparkline(r)
+	for _, p := range points {
+		spark.Push(p.Mean())
+	}
+	return spark
+}
+
+// statusFrame renders the board name, the time and a temperature, or the
+// time, the temperature and its sparkline.
+func statusFrame(bounds image.Rectangle, bus *event.Bus, spark *ui.Sparkline) *image1bit.VerticalLSB {
+	frame := image1bit.NewVerticalLSB(bounds)
+	lines := []string{"Pioneer600", time.Now().Format("15:04:05"), "-- C"}
+	if e, ok := firstTemperature(bus); ok {
+		lines[2] = fmt.Sprintf("%.1f C", e.Value)
+	}
+	top := 4
+	if spark != nil {
+		lines, top = lines[1:], 2
+		spark.Draw(frame)
+	}
+	for i, line := range lines {
+		x := (bounds.Dx() - dev.TextWidth(line)) / 2
+		dev.DrawString(frame, image.Pt(x, top+i*(dev.FontHeight+6)), line)
+	}
+	return frame
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"pi/dev"
+	"pi/log"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli"
+)
+
+// completeArgs completes the first argument of a command with args.
+func completeArgs(args ...string) cli.BashCompleteFunc {
+	return func(c *cli.Context) {
+		if c.NArg() > 0 {
+			return
+		}
+		for _, a := range args {
+			fmt.Println(a)
+		}
+	}
+}
+
+// action returns the first argument of c, def when missing, or an error if
+// not in valid.
+func action(c *cli.Context, def string, valid ...string) (string, error) {
+	a := c.Args().First()
+	if a == "" {
+		return def, nil
+	}
+	for _, v := range valid {
+		if a == v {
+			return a, nil
+		}
+	}
+	return "", fmt.Errorf("unknown action %q, want one of %v", a, valid)
+}
+
+var ledCommand = cli.Command{
+	Name:         "led",
+	Usage:        "Switch or blink the GPIO LED (D1)",
+	ArgsUsage:    "[on|off|blink]",
+	BashComplete: completeArgs("on", "off", "blink"),
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "pin,p",
+			Usage: "BCM pin of the LED",
+			Value: 26,
+		},
+		intervalFlag,
+		countFlag,
+	},
+	Action: func(c *cli.Context) error {
+		a, err := action(c, "blink", "on", "off", "blink")
+		if err != nil {
+			return err
+		}
+		log.Default().Info("GPIO Test LED One.")
+		led := dev.NewLEDOneAt(c.Int("pin"))
+		if err = led.Init(context.Background()); err != nil {
+			return err
+		}
+		switch a {
+		case "on":
+			return led.On()
+		case "off":
+			return led.Off()
+		}
+		return repeat(c, led.Toggle)
+	},
+}
+
+var expanderCommand = cli.Command{
+	Name:         "expander",
+	Usage:        "Drive the PCF8574 expander: LED2 or the raw port",
+	ArgsUsage:    "[on|off|blink|read|write PORT]",
+	BashComplete: completeArgs("on", "off", "blink", "read", "write"),
+	Flags: []cli.Flag{
+		busFlag,
+		addressFlag(dev.I2cAddrPcf8574),
+		intervalFlag,
+		countFlag,
+	},
+	Action: func(c *cli.Context) error {
+		a, err := action(c, "blink", "on", "off", "blink", "read", "write")
+		if err != nil {
+			return err
+		}
+		addr, err := address(c)
+		if err != nil {
+			return err
+		}
+		log.Default().Info("I2C Test PCF8574 LED Two.")
+		led2, err := dev.NewPCF8574LEDAt(c.String("bus"), addr)
+		if err != nil {
+			return err
+		}
+		if err = led2.Init(context.Background()); err != nil {
+			return err
+		}
+		switch a {
+		case "on":
+			return led2.LED2On()
+		case "off":
+			return led2.LED2Off()
+		case "read":
+			return repeat(c, func() error {
+				port, err := led2.Read()
+				if err == nil {
+					fmt.Printf("0x%02x %08b\n", port, port)
+				}
+				return err
+			})
+		case "write":
+			port, err := strconv.ParseUint(c.Args().Get(1), 0, 8)
+			if err != nil {
+				return fmt.Errorf("invalid port value %q", c.Args().Get(1))
+			}
+			return led2.Write(byte(port))
+		}
+		return repeat(c, led2.Toggle)
+	},
+}
+
+var beepCommand = cli.Command{
+	Name:  "beep",
+	Usage: "Play a melody or a tone on the buzzer",
+	Flags: []cli.Flag{
+		busFlag,
+		addressFlag(dev.I2cAddrPcf8574),
+		cli.Float64Flag{
+			Name:  "bpm",
+			Usage: "Tempo in beats per minute",
+			Value: 96,
+		},
+		cli.Float64Flag{
+			Name:  "tone",
+			Usage: "Tone frequency in Hz, 0 plays the melody",
+		},
+		cli.Float64Flag{
+			Name:  "beats",
+			Usage: "Tone length in beats",
+			Value: dev.Quarter,
+		},
+		cli.IntFlag{
+			Name:  "count,n",
+			Usage: "Number of times to play, 0 plays until interrupted",
+			Value: 1,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		addr, err := address(c)
+		if err != nil {
+			return err
+		}
+		log.Default().Info("I2C Test PCF8574 Beep.")
+		beep, err := dev.NewPCF8574BeepAt(c.String("bus"), addr)
+		if err != nil {
+			return err
+		}
+		if err = beep.Init(context.Background()); err != nil {
+			return err
+		}
+		beep.BPM = c.Float64("bpm")
+		defer beep.Off()
+		type note struct {
+			tone     float64
+			duration float64
+		}
+		song := []note{
+			{dev.C4, dev.Quarter},
+			{dev.C4, dev.Quarter},
+			{dev.G4, dev.Quarter},
+			{dev.G4, dev.Quarter},
+			{dev.A4, dev.Quarter},
+			{dev.A4, dev.Quarter},
+			{dev.G4, dev.Half},
+			{dev.F4, dev.Quarter},
+			{dev.F4, dev.Quarter},
+			{dev.E4, dev.Quarter},
+			{dev.E4, dev.Quarter},
+			{dev.D4, dev.Quarter},
+			{dev.D4, dev.Quarter},
+			{dev.C4, dev.Half},
+		}
+		if hz := c.Float64("tone"); hz > 0 {
+			song = []note{{hz, c.Float64("beats")}}
+		}
+		ctx, cancel := signalContext()
+		defer cancel()
+		for i := 0; c.Int("count") == 0 || i < c.Int("count"); i++ {
+			for _, val := range song {
+				if ctx.Err() != nil {
+					return nil
+				}
+				if err = beep.Tone(val.tone, val.duration); err != nil {
+					return err
+				}
+				time.Sleep(50 * time.Millisecond)
+			}
+		}
+		return nil
+	},
+}
+
+var tempCommand = cli.Command{
+	Name:  "temp",
+	Usage: "Read the DS18B20 1-Wire temperature sensor",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "sensor,s",
+			Usage: "1-Wire id of the sensor (28-...), the first one when empty",
+		},
+		cli.DurationFlag{
+			Name:  "interval,i",
+			Usage: "Time between two readings",
+			Value: 2 * time.Second,
+		},
+		countFlag,
+	},
+	Action: func(c *cli.Context) error {
+		log.Default().Info("1-Wire Test DS18b20.")
+		ds18b20 := dev.NewDS18B20Sensor(c.String("sensor"))
+		return repeat(c, func() error {
+			if err := ds18b20.FetchTemperate(); err != nil {
+				return err
+			}
+			fmt.Printf("%s %.3f ℃\n", ds18b20.Name(), ds18b20.Temperate())
+			return nil
+		})
+	},
+}
+
+var rtcCommand = cli.Command{
+	Name:  "rtc",
+	Usage: "Read or set the DS3231 real time clock",
+	Flags: []cli.Flag{
+		busFlag,
+		addressFlag(dev.I2cAddrDS3231),
+		cli.StringFlag{
+			Name:  "set",
+			Usage: `Set the clock to "2006-01-02 15:04:05" (local time) or "now"`,
+		},
+		intervalFlag,
+		cli.IntFlag{
+			Name:  "count,n",
+			Usage: "Number of readings, 0 reads until interrupted",
+			Value: 1,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		addr, err := address(c)
+		if err != nil {
+			return err
+		}
+		log.Default().Info("I2C RTC　Test DS3231.")
+		ds3231, err := dev.NewDS3231At(c.String("bus"), addr)
+		if err != nil {
+			return err
+		}
+		defer ds3231.Close()
+		if err = ds3231.Init(context.Background()); err != nil {
+			return err
+		}
+		if set := c.String("set"); set != "" {
+			t := time.Now()
+			if set != "now" {
+				if t, err = time.ParseInLocation("2006-01-02 15:04:05", set, time.Local); err != nil {
+					return err
+				}
+			}
+			if err = ds3231.SetTimeTo(t); err != nil {
+				return err
+			}
+		}
+		return repeat(c, func() error {
+			t, err := ds3231.Now()
+			if err == nil {
+				fmt.Println(t.Format("2006-01-02 15:04:05 Mon"))
+			}
+			return err
+		})
+	},
+}
+
+var scanCommand = cli.Command{
+	Name:  "scan",
+	Usage: "List the chips answering on the I2C bus",
+	Flags: []cli.Flag{busFlag},
+	Action: func(c *cli.Context) error {
+		found, err := dev.ScanI2C(c.String("bus"))
+		if err != nil {
+			return err
+		}
+		if len(found) == 0 {
+			fmt.Println("no device found on", c.String("bus"))
+		}
+		for _, addr := range found {
+			name := dev.I2CNames[addr]
+			if name == "" {
+				name = "unknown"
+			}
+			fmt.Printf("0x%02x  %s\n", addr, name)
+		}
+		return nil
+	},
+}
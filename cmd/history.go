@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"pi/history"
+	"time"
+
+	"github.com/urfave/cli"
+)
+
+var historyCommand = cli.Command{
+	Name:      "history",
+	Usage:     "List the recorded series or export one as CSV or JSON",
+	ArgsUsage: "[device/name]",
+	Action:    exportHistory,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "from",
+			Usage: "Start, RFC 3339 or a duration from now",
+			Value: "-24h",
+		},
+		cli.StringFlag{
+			Name:  "to",
+			Usage: "End, RFC 3339 or a duration from now",
+			Value: "now",
+		},
+		cli.DurationFlag{
+			Name:  "step",
+			Usage: "Aggregate the samples by step, eg. 1h, raw samples when 0",
+		},
+		cli.StringFlag{
+			Name:  "format,f",
+			Usage: "csv or json",
+			Value: "csv",
+		},
+		cli.StringFlag{
+			Name:  "output,o",
+			Usage: "File, - for stdout",
+			Value: "-",
+		},
+	},
+}
+
+// exportHistory reads the store of the history section, the aggregates
+// still open in a running daemon are not written yet.
+func exportHistory(c *cli.Context) error {
+	o, err := history.NewOptions(config)
+	if err != nil {
+		return err
+	}
+	if o.Path == "" {
+		return errors.New("history.path is not set")
+	}
+	store, err := history.Open(o)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	series := c.Args().First()
+	if series == "" {
+		for _, name := range store.Series() {
+			fmt.Println(name)
+		}
+		return nil
+	}
+	now := time.Now()
+	from, err := history.ParseTime(c.String("from"), now)
+	if err != nil {
+		return err
+	}
+	to, err := history.ParseTime(c.String("to"), now)
+	if err != nil {
+		return err
+	}
+	write := history.WriteCSV
+	switch c.String("format") {
+	case "csv":
+	case "json":
+		write = history.WriteJSON
+	default:
+		return fmt.Errorf("unknown format %q, want csv or json", c.String("format"))
+	}
+	points, err := store.Query(series, from, to, c.Duration("step"))
+	if err != nil {
+		return err
+	}
+	out := os.Stdout
+	if path := c.String("output"); path != "-" {
+		if out, err = os.Create(path); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	return write(out, series, points)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -11,169 +12,115 @@ import (
 
 	"github.com/spf13/viper"
 	"github.com/urfave/cli"
-
 )
 
-const (
-	FunctionGpioLedOne    int = 0
-	FunctionPCF8574LedTwo int = 1
-	FunctionPCF8574Beep   int = 2
-	FunctionDs18B20       int = 3
-	FunctionDs3231        int = 4
-	FunctionSSD1306       int = 5
-)
+// config is the configuration read from --conf before any command runs.
+var config *viper.Viper
 
-func testGpioLEDOne() {
-	log.Default().Info("GPIO Test LED One.")
-	led := dev.NewLEDOne()
-	led.Init()
-	for {
-		led.Toggle()
-		time.Sleep(1 * time.Second)
+// I2C and timing flags shared by the commands.
+var (
+	busFlag = cli.StringFlag{
+		Name:  "bus",
+		Usage: "I2C bus device",
+		Value: dev.I2cDev,
 	}
-}
-
-func testPCF8574LedTwo() {
-	log.Default().Info("I2C Test PCF8574 LED Two.")
-	led2 := dev.NewPCF8574LED()
-	for {
-		led2.Toggle()
-		time.Sleep(1 * time.Second)
+	intervalFlag = cli.DurationFlag{
+		Name:  "interval,i",
+		Usage: "Time between two iterations",
+		Value: time.Second,
 	}
-}
-
-func testPCF8574Beep() {
-	log.Default().Info("I2C Test PCF8574 Beep.")
-	beep := dev.NewPCF8574Beep()
-	type note struct {
-		tone     float64
-		duration float64
+	countFlag = cli.IntFlag{
+		Name:  "count,n",
+		Usage: "Number of iterations, 0 runs until interrupted",
 	}
+)
 
-	song := []note{
-		{dev.C4, dev.Quarter},
-		{dev.C4, dev.Quarter},
-		{dev.G4, dev.Quarter},
-		{dev.G4, dev.Quarter},
-		{dev.A4, dev.Quarter},
-		{dev.A4, dev.Quarter},
-		{dev.G4, dev.Half},
-		{dev.F4, dev.Quarter},
-		{dev.F4, dev.Quarter},
-		{dev.E4, dev.Quarter},
-		{dev.E4, dev.Quarter},
-		{dev.D4, dev.Quarter},
-		{dev.D4, dev.Quarter},
-		{dev.C4, dev.Half},
-	}
-	for {
-		for _, val := range song {
-			beep.Tone(val.tone, val.duration)
-			time.Sleep(500 * time.Millisecond)
-		}
+// addressFlag is the I2C address flag of a chip, parsed with base prefixes
+// (0x20 or 32).
+func addressFlag(value int) cli.StringFlag {
+	return cli.StringFlag{
+		Name:  "address,a",
+		Usage: "I2C address",
+		Value: fmt.Sprintf("0x%02x", value),
 	}
 }
 
-func testDS18b20() {
-	log.Default().Info("1-Wire Test DS18b20.")
-	ds18b20 := dev.NewDS18B20()
-	for {
-		err := ds18b20.FetchTemperate()
-		if err != nil {
-			fmt.Println(err)
-		}
-		log.Default().Infof("Current temperate : %v ℃", ds18b20.Temperate())
-		time.Sleep(2 * time.Second)
+// address returns the --address flag of c.
+func address(c *cli.Context) (int, error) {
+	var a int
+	if _, err := fmt.Sscan(c.String("address"), &a); err != nil || a < 0x03 || a > 0x77 {
+		return 0, fmt.Errorf("invalid I2C address %q", c.String("address"))
 	}
+	return a, nil
 }
 
-func testDS3231() {
-	log.Default().Info("I2C RTC　Test DS3231.")
-	ds3231 := dev.NewDS3231()
-	ds3231.SetTime()
-	for {
-		t, err := ds3231.Time()
-		if err != nil {
-			fmt.Println(err)
+// signalContext returns a context cancelled on SIGINT or SIGTERM.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-sigChan:
+			log.Default().Infof("signal received signal %v", sig)
+			cancel()
+		case <-ctx.Done():
 		}
-		fmt.Println(t)
-		time.Sleep(2 * time.Second)
-	}
+		signal.Stop(sigChan)
+	}()
+	return ctx, cancel
 }
 
-func testSSD1306() {
-	log.Default().Info("SPI Test SSD1306.")
-	ssd1306 := dev.NewSSD1306H()
-	for {
-		log.Default().Info("ssd1306 ...... ", ssd1306)
-		ssd1306.DrawText(dev.PosTopLeft, "Super Google.")
-		time.Sleep(1 * time.Second)
-		ssd1306.DrawText(dev.PosTopCenter, "Super Google.")
-		time.Sleep(1 * time.Second)
-		ssd1306.DrawText(dev.PosTopRight, "Super Google.")
-		time.Sleep(1 * time.Second)
-		ssd1306.DrawText(dev.PosBottomLeft, "Super Google.")
-		time.Sleep(1 * time.Second)
-		ssd1306.DrawText(dev.PosBottomCenter, "Super Google.")
-		time.Sleep(1 * time.Second)
-		ssd1306.DrawText(dev.PosBottomRight, "Super Google.")
-		time.Sleep(1 * time.Second)
+// repeat calls fn every --interval, --count times or until interrupted.
+func repeat(c *cli.Context, fn func() error) error {
+	ctx, cancel := signalContext()
+	defer cancel()
+	t := time.NewTicker(c.Duration("interval"))
+	defer t.Stop()
+	for i := 0; c.Int("count") == 0 || i < c.Int("count"); i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-t.C:
+			}
+		}
+		if err := fn(); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func run(c *cli.Context) error {
-
-	fmt.Println("conf = ", c.String("conf"))
-	config := viper.New()
+// setup reads the configuration and creates the logger.
+func setup(c *cli.Context) error {
+	config = viper.New()
 	config.SetConfigFile(c.String("conf"))
 	config.SetConfigType("yaml")
-	config.ReadInConfig()
+	if err := config.ReadInConfig(); err != nil && c.IsSet("conf") {
+		return err
+	}
 	opt, err := log.NewOptions(config)
 	if err != nil {
-		fmt.Println("err = ", err)
+		return err
 	}
-	logger, err := log.NewLogger(opt)
-	if err != nil {
-		fmt.Println("err = ", err)
+	if opt.Level == "" {
+		opt.Level = "info"
 	}
-	//Out Some Target for project
-	logger.Info("Raspberry Pi 4 and Pioneer600")
-	logger.Info("Learn how to use golang control devices.")
-	logger.Info("Thanks to https://gobot.io")
-	logger.Info("Thanks to http://www.waveshare.net.")
-	logger.Info("Thanks to https://periph.io/project/goals.")
-	function := c.Int("function")
-	switch function {
-	case FunctionGpioLedOne:
-		testGpioLEDOne()
-	case FunctionPCF8574LedTwo:
-		testPCF8574LedTwo()
-	case FunctionPCF8574Beep:
-		testPCF8574Beep()
-	case FunctionDs18B20:
-		testDS18b20()
-	case FunctionDs3231:
-		testDS3231()
-	case FunctionSSD1306:
-		testSSD1306()
-	default:
-		logger.Info("%v is not define yet.\n", function)
+	if _, err = log.NewLogger(opt); err != nil {
+		fmt.Println("err = ", err)
 	}
-
-	//quit when receive end signal
-	sigChan := make(chan os.Signal)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	logger.Info("signal received signal %v", <-sigChan)
-	logger.Warn("shutting down server")
 	return nil
 }
 
 func main() {
 	app := cli.NewApp()
-	app.Name = "pi"
-	app.Usage = "/usr/bin/pi -c /etc/pi/prod.yml"
+	app.Name = "Pioneer600"
+	app.Usage = "Control the Waveshare Pioneer600 board"
+	app.UsageText = "Pioneer600 [-c /etc/Pioneer600/prod.yml] command [command options]"
 	app.Version = "0.0.1"
-	app.Action = run
+	app.EnableBashCompletion = true
+	app.Before = setup
 	app.Flags = []cli.Flag{
 		cli.StringFlag{
 			Name:   "conf,c",
@@ -181,12 +128,29 @@ func main() {
 			Value:  "prod.yml",
 			EnvVar: "APP_CONF",
 		},
-		cli.IntFlag{
-			Name:   "function,f",
-			Usage:  "Test different device function",
-			Value:  FunctionGpioLedOne,
-			EnvVar: "APP_CONF",
+	}
+	app.Commands = []cli.Command{
+		ledCommand,
+		expanderCommand,
+		beepCommand,
+		tempCommand,
+		rtcCommand,
+		oledCommand,
+		snapshotCommand,
+		historyCommand,
+		scheduleCommand,
+		runScriptCommand,
+		{
+			Name:   "daemon",
+			Usage:  "Run the configured devices as a service until SIGTERM",
+			Action: runDaemon,
+			Flags:  displayFlags,
 		},
+		scanCommand,
+		completionCommand,
+	}
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-	app.Run(os.Args)
 }
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"pi/api"
+	"pi/dev"
+	"pi/log"
+	"pi/ui"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/urfave/cli"
+)
+
+// displayFlags override the display section of the configuration.
+var displayFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:   "display",
+		Usage:  "Set display driver here (ssd1306h, ssd1306 or emulator)",
+		EnvVar: "APP_DISPLAY",
+	},
+	cli.StringFlag{
+		Name:  "controller",
+		Usage: "OLED controller (ssd1306, sh1106 or ssd1309)",
+	},
+	cli.StringFlag{
+		Name:  "bus",
+		Usage: "OLED bus (spi or i2c)",
+	},
+	cli.StringFlag{
+		Name:  "address,a",
+		Usage: "OLED I2C address",
+	},
+}
+
+var oledCommand = cli.Command{
+	Name:         "oled",
+	Usage:        "Show text or the joystick menu on the OLED",
+	ArgsUsage:    "[text|menu]",
+	BashComplete: completeArgs("text", "menu"),
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name:  "text,t",
+			Usage: "Text moved around the panel",
+			Value: "Super Google.",
+		},
+		intervalFlag,
+		countFlag,
+	}, displayFlags...),
+	Action: func(c *cli.Context) error {
+		a, err := action(c, "text", "text", "menu")
+		if err != nil {
+			return err
+		}
+		server, err := newServer(config)
+		if err != nil {
+			return err
+		}
+		display, dopt, err := openDisplay(c, config, nil, server)
+		if err != nil {
+			log.Default().Error("open display error: ", err)
+			return err
+		}
+		if server != nil {
+			go serve(context.Background(), server)
+		}
+		if a == "menu" {
+			return testMenu(display, dopt)
+		}
+		return testSSD1306(c, display)
+	},
+}
+
+var snapshotCommand = cli.Command{
+	Name:   "snapshot",
+	Usage:  "Save the OLED frame of a running instance as PNG",
+	Action: snapshot,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "url",
+			Usage: "Remote view address of the instance",
+			Value: "http://localhost:8080",
+		},
+		cli.StringFlag{
+			Name:  "output,o",
+			Usage: "PNG file, - for stdout",
+			Value: "oled.png",
+		},
+		cli.IntFlag{
+			Name:  "scale",
+			Usage: "Enlarge the 128x64 frame (1-16)",
+			Value: 1,
+		},
+	},
+}
+
+func testSSD1306(c *cli.Context, display dev.Display) error {
+	log.Default().Info("SPI Test SSD1306.")
+	positions := []dev.SSD1306Pos{
+		dev.PosTopLeft,
+		dev.PosTopCenter,
+		dev.PosTopRight,
+		dev.PosBottomLeft,
+		dev.PosBottomCenter,
+		dev.PosBottomRight,
+	}
+	i := 0
+	return repeat(c, func() error {
+		pos := positions[i%len(positions)]
+		i++
+		return dev.ShowText(display, pos, c.String("text"))
+	})
+}
+
+func testMenu(panel dev.Display, dopt *dev.DisplayOpts) error {
+	log.Default().Info("Menu Test SSD1306.")
+	ctx, cancel := signalContext()
+	defer cancel()
+	var events <-chan ui.Event
+	var clock dev.TimeSource = dev.SystemClock{}
+	if dopt.Driver == dev.DisplayEmulator {
+		restore := cbreak()
+		defer restore()
+		events = ui.KeyboardEvents(os.Stdin)
+	} else {
+		joystick, err := dev.NewJoystick()
+		if err != nil {
+			return fmt.Errorf("unable to open the joystick: %v", err)
+		}
+		defer joystick.Close()
+		if err = joystick.Init(ctx); err != nil {
+			return err
+		}
+		events = ui.JoystickEvents(ctx, joystick, 50*time.Millisecond)
+		if rtc, err := dev.NewDS3231(); err == nil && rtc.Init(ctx) == nil {
+			clock = rtc
+		}
+	}
+	display, err := dev.NewPowerManager(panel, &dopt.Power, clock)
+	if err != nil {
+		return err
+	}
+	go display.Run(ctx)
+	events = ui.WakeOn(events, display)
+	contrast := 0xCF
+	root := ui.NewListMenu("Pioneer600",
+		ui.MenuItem{Label: "Contrast", Action: func(n *ui.Navigator) {
+			n.Push(&ui.Spinner{Title: "Contrast", Value: float64(contrast), Max: 255, Step: 16, OnDone: func(v float64) {
+				contrast = int(v)
+				display.SetContrast(byte(contrast))
+			}})
+		}},
+		ui.MenuItem{Label: "Clock", Action: func(n *ui.Navigator) {
+			n.Push(&ui.TimeSetter{Title: "Clock", Value: time.Now(), OnDone: func(t time.Time) {
+				log.Default().Info("clock set to ", t.Format("15:04:05"))
+			}})
+		}},
+		ui.MenuItem{Label: "Reset", Action: func(n *ui.Navigator) {
+			n.Push(&ui.Confirm{Message: "Restore default contrast?", OnDone: func(yes bool) {
+				if yes {
+					contrast = 0xCF
+					display.SetContrast(byte(contrast))
+				}
+			}})
+		}},
+	)
+	if err = ui.NewNavigator(display, root).Run(ctx, events); err != nil && err != context.Canceled {
+		return err
+	}
+	return nil
+}
+
+// cbreak makes the terminal deliver keys without enter for the emulator and
+// returns a function restoring it.
+func cbreak() func() {
+	stty := func(args ...string) error {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = os.Stdin
+		return cmd.Run()
+	}
+	if err := stty("cbreak", "-echo"); err != nil {
+		return func() {}
+	}
+	return func() { stty("-cbreak", "echo") }
+}
+
+// openDisplay creates the display configured in conf, updated with cfg,
+// or the ssd1306 of the devices section when nil, and the displayFlags.
+// The display is mirrored to the remote view of server when it is not nil.
+func openDisplay(c *cli.Context, config *viper.Viper, cfg *dev.DeviceConfig, server *api.Server) (dev.Display, *dev.DisplayOpts, error) {
+	dopt, err := dev.NewDisplayOpts(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg == nil {
+		_, devices, err := loadDevices()
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range devices {
+			if devices[i].Type == dev.TypeSSD1306 {
+				cfg = &devices[i]
+				break
+			}
+		}
+	}
+	if cfg != nil {
+		dopt = dev.DisplayOptsOf(cfg, dopt)
+	}
+	if c.IsSet("display") {
+		dopt.Driver = c.String("display")
+	}
+	if c.IsSet("controller") {
+		dopt.Controller = c.String("controller")
+	}
+	if c.IsSet("bus") {
+		dopt.Bus = c.String("bus")
+	}
+	if c.IsSet("address") {
+		if dopt.Address, err = address(c); err != nil {
+			return nil, nil, err
+		}
+	}
+	display, err := dev.OpenDisplay(dopt)
+	if err != nil || server == nil {
+		return display, dopt, err
+	}
+	mirror := dev.NewMirror(display)
+	server.HandleDisplay(mirror)
+	return mirror, dopt, nil
+}
+
+// newServer creates the server of the http section, nil when http.listen
+// is empty.
+func newServer(config *viper.Viper) (*api.Server, error) {
+	hopt, err := api.NewOptions(config)
+	if err != nil || hopt.Listen == "" {
+		return nil, err
+	}
+	return api.NewServer(hopt), nil
+}
+
+// serve runs server until ctx is done.
+func serve(ctx context.Context, server *api.Server) {
+	if err := server.Run(ctx); err != nil {
+		log.Default().Error("http server error: ", err)
+	}
+}
+
+// snapshot saves the frame shown by a running instance as a PNG.
+func snapshot(c *cli.Context) error {
+	url := strings.TrimSuffix(c.String("url"), "/") + "/display/snapshot.png?scale=" + strconv.Itoa(c.Int("scale"))
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("snapshot: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	out := os.Stdout
+	if path := c.String("output"); path != "-" {
+		if out, err = os.Create(path); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	_, err = io.Copy(out, resp.Body)
+	return err
+}
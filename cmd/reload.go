@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"pi/alert"
+	"pi/api"
+	"pi/daemon"
+	"pi/dev"
+	"pi/event"
+	"pi/log"
+	"pi/mqtt"
+	"pi/schedule"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay groups the events of an editor saving the configuration.
+const reloadDelay = 200 * time.Millisecond
+
+// reloader applies a changed configuration to the running daemon.
+type reloader struct {
+	registry   *dev.Registry
+	bus        *event.Bus
+	health     *dev.HealthRegistry
+	supervisor *daemon.Supervisor
+	devices    map[string]*device
+	configs    []dev.DeviceConfig
+	holds      *holds
+	// bridge announces the devices to Home Assistant, nil without MQTT.
+	bridge    *mqtt.Bridge
+	alerts    *alert.Engine
+	scheduler *schedule.Scheduler
+	// dashboard lists the devices to the web page, nil without HTTP.
+	dashboard *api.Dashboard
+}
+
+// start runs the worker of cfg, replacing a running one of the same name.
+func (r *reloader) start(cfg dev.DeviceConfig) error {
+	d := newDevice(cfg)
+	r.devices[cfg.Name] = d
+	return r.supervisor.Replace(deviceWorker(r.registry, d, r.bus, r.health, r.holds))
+}
+
+// watch reloads the configuration on SIGHUP and when the file path is
+// written. The directory is watched as editors replace the file.
+func (r *reloader) watch(ctx context.Context, path string) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	var events <-chan fsnotify.Event
+	if path != "" {
+		watcher, err := fsnotify.NewWatcher()
+		if err == nil {
+			defer watcher.Close()
+			err = watcher.Add(filepath.Dir(path))
+		}
+		if err != nil {
+			log.Default().Error("watch configuration error: ", err)
+		} else {
+			events = watcher.Events
+		}
+	}
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Default().Info("SIGHUP received, reloading ", path)
+			r.reload()
+		case e := <-events:
+			if filepath.Clean(e.Name) == filepath.Clean(path) && e.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				pending = time.After(reloadDelay)
+			}
+		case <-pending:
+			pending = nil
+			log.Default().Info("configuration changed, reloading ", path)
+			r.reload()
+		}
+	}
+}
+
+// reload reads the configuration again and applies the log level, the
+// alerts, the schedule and the devices section. Only the changed devices
+// are restarted, an invalid configuration is ignored.
+func (r *reloader) reload() {
+	logger := log.Default()
+	if err := config.ReadInConfig(); err != nil {
+		logger.Error("reload error: ", err)
+		return
+	}
+	lopt, err := log.NewOptions(config)
+	if err != nil {
+		logger.Error("reload error: ", err)
+		return
+	}
+	if lopt.Level == "" {
+		lopt.Level = "info"
+	}
+	if old := log.Level(); lopt.Level != old {
+		if err := log.SetLevel(lopt.Level); err != nil {
+			logger.Error("reload error: ", err)
+		} else {
+			logger.Infof("reload: log level %s -> %s", old, lopt.Level)
+		}
+	}
+	if rules, err := alert.NewRules(config); err != nil {
+		logger.Error("reload error: ", err)
+	} else {
+		r.alerts.SetRules(rules)
+		logger.Infof("reload: %d alert rules", len(rules))
+	}
+	if so, err := schedule.NewOptions(config); err != nil {
+		logger.Error("reload error: ", err)
+	} else {
+		r.scheduler.SetJobs(so.Jobs)
+		logger.Infof("reload: %d scheduled jobs", len(so.Jobs))
+	}
+	configs, err := dev.LoadDevices(config, r.registry)
+	if err != nil {
+		logger.Error("reload error: ", err)
+		return
+	}
+	changes := dev.DiffDevices(r.configs, configs)
+	for _, c := range changes {
+		logger.Info("reload: ", c)
+		// The display and its remote view are opened once.
+		if c.Kind != dev.ChangeRemoved && (c.New.Type == dev.TypeSSD1306 || c.Old != nil && c.Old.Type == dev.TypeSSD1306) {
+			logger.Warnf("reload: %s is applied on restart", c.New.Name)
+			continue
+		}
+		var err error
+		switch c.Kind {
+		case dev.ChangeInterval:
+			r.devices[c.New.Name].SetInterval(c.New.Interval)
+		case dev.ChangeRemoved:
+			delete(r.devices, c.Old.Name)
+			r.health.Remove(c.Old.Name)
+			err = r.supervisor.Remove(c.Old.Name)
+		default:
+			err = r.start(*c.New)
+		}
+		if err != nil {
+			logger.Errorf("reload %s error: %v", c, err)
+		}
+	}
+	if len(changes) == 0 {
+		logger.Info("reload: devices unchanged")
+	} else {
+		if r.bridge != nil {
+			r.bridge.SetDevices(configs)
+		}
+		if r.dashboard != nil {
+			r.dashboard.SetDevices(configs)
+		}
+	}
+	r.configs = configs
+}
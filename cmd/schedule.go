@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"pi/schedule"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli"
+)
+
+var scheduleCommand = cli.Command{
+	Name:   "schedule",
+	Usage:  "List the upcoming runs of the scheduled jobs",
+	Action: listSchedule,
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "count,n",
+			Usage: "Number of runs",
+			Value: 10,
+		},
+	},
+}
+
+// listSchedule prints the next runs of the schedule section on the system
+// clock, with the last run of each job in the state file.
+func listSchedule(c *cli.Context) error {
+	o, err := schedule.NewOptions(config)
+	if err != nil {
+		return err
+	}
+	last := make(map[string]time.Time)
+	if o.State != "" {
+		if last, err = schedule.LoadState(o.State); err != nil {
+			return err
+		}
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tJOB\tCRON\tACTIONS\tLAST RUN")
+	for _, r := range schedule.Upcoming(o.Jobs, time.Now(), c.Int("count")) {
+		actions := make([]string, len(r.Job.Actions))
+		for i, a := range r.Job.Actions {
+			actions[i] = a.Type
+			if a.Device != "" {
+				actions[i] += " " + a.Device
+			}
+		}
+		lastRun := "-"
+		if t, ok := last[r.Job.Name]; ok {
+			lastRun = t.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Time.Format("Mon 2006-01-02 15:04"), r.Job.Name, r.Job.Cron, strings.Join(actions, ", "), lastRun)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"pi/control"
+	"pi/dev"
+	"pi/log"
+	"pi/script"
+
+	"github.com/urfave/cli"
+)
+
+var runScriptCommand = cli.Command{
+	Name:      "run-script",
+	Usage:     "Run a Lua script on the configured devices, list the scripts without one",
+	ArgsUsage: "[name|file]",
+	Action:    runScript,
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "Stop the script after timeout, script.timeout when 0",
+		},
+	},
+}
+
+// runScript opens the configured devices and runs a script of the script
+// directory, or a file, on them. It does not share the devices with a
+// running daemon.
+func runScript(c *cli.Context) error {
+	o, err := script.NewOptions(config)
+	if err != nil {
+		return err
+	}
+	name := c.Args().First()
+	if name == "" {
+		names, err := o.Scripts()
+		if err != nil {
+			return err
+		}
+		for _, n := range names {
+			fmt.Println(n)
+		}
+		return nil
+	}
+	if t := c.Duration("timeout"); t > 0 {
+		o.Timeout = t
+	}
+	registry, configs, err := loadDevices()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := signalContext()
+	defer cancel()
+	health := dev.NewHealthRegistry()
+	for i := range configs {
+		cfg := &configs[i]
+		d, err := registry.Open(ctx, cfg)
+		if err != nil {
+			log.Default().Debug(err)
+			health.Fail(cfg.Name, err)
+			continue
+		}
+		health.Add(cfg.Name, d)
+		defer d.Close()
+	}
+	r := script.New(control.New(health, nil, nil), o.Timeout)
+	return r.RunFile(ctx, o.Path(name))
+}
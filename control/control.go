@@ -0,0 +1,304 @@
+// Package control drives the actuators of the daemon by device name, for
+// the REST API and the integrations.
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"pi/dev"
+	"pi/event"
+	"pi/log"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultBPM is the tempo of the melodies without one.
+	DefaultBPM = 96
+	// MaxMelody is the longest melody accepted, the buzzer is shared.
+	MaxMelody = time.Minute
+	// DefaultHold is how long the daemon leaves a device alone after a
+	// command changed it.
+	DefaultHold = time.Minute
+	// MaxHz is the highest note. The buzzer is toggled by an I2C write
+	// each half period, which the bus cannot do much faster.
+	MaxHz = 1000
+	// MaxText is the longest text shown, in characters.
+	MaxText = 64
+)
+
+// Errors of the commands, wrapped with the device name.
+var (
+	// ErrUnknown is a device missing from the configuration.
+	ErrUnknown = errors.New("unknown device")
+	// ErrFailed is a device which could not be opened.
+	ErrFailed = errors.New("device failed")
+	// ErrUnsupported is a command the device does not have.
+	ErrUnsupported = errors.New("unsupported command")
+	// ErrBusy is a buzzer already playing.
+	ErrBusy = errors.New("device busy")
+	// ErrInvalid is a command with invalid arguments.
+	ErrInvalid = errors.New("invalid command")
+)
+
+// Switch is a LED.
+type Switch interface {
+	On() error
+	Off() error
+	Toggle() error
+	Status() int
+}
+
+// Player is a buzzer.
+type Player interface {
+	Play(ctx context.Context, hz float64, d time.Duration) error
+}
+
+// Note is a frequency played for a duration, Hz 0 is a rest.
+type Note struct {
+	Hz       float64
+	Duration time.Duration
+}
+
+// Beats returns the duration of beats at bpm, DefaultBPM when 0.
+func Beats(bpm, beats float64) time.Duration {
+	if bpm == 0 {
+		bpm = DefaultBPM
+	}
+	return time.Duration(beats * float64(time.Minute) / bpm)
+}
+
+// Controller serializes the commands to each device of a HealthRegistry.
+type Controller struct {
+	health *dev.HealthRegistry
+	bus    *event.Bus
+	hold   func(name string, d time.Duration)
+
+	mu      sync.Mutex
+	locks   map[string]*sync.Mutex
+	playing map[string]*playback
+}
+
+// playback is a melody being played.
+type playback struct {
+	cancel context.CancelFunc
+	// done is closed once the buzzer is free.
+	done chan struct{}
+}
+
+// New creates a new Controller of the devices of health. The LED states
+// are published on bus, hold is called with the name of a device a
+// command changed so the daemon leaves it alone; both may be nil.
+func New(health *dev.HealthRegistry, bus *event.Bus, hold func(name string, d time.Duration)) *Controller {
+	return &Controller{
+		health:  health,
+		bus:     bus,
+		hold:    hold,
+		locks:   make(map[string]*sync.Mutex),
+		playing: make(map[string]*playback),
+	}
+}
+
+// Health returns the HealthRegistry of the devices.
+func (c *Controller) Health() *dev.HealthRegistry {
+	return c.health
+}
+
+// Device returns the device name, the error wraps ErrUnknown or ErrFailed.
+func (c *Controller) Device(name string) (dev.Device, error) {
+	if d := c.health.Device(name); d != nil {
+		return d, nil
+	}
+	for _, report := range c.health.Report() {
+		if report.Name == name {
+			return nil, fmt.Errorf("%s: %w: %s", name, ErrFailed, report.Error)
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", name, ErrUnknown)
+}
+
+// Readings returns the latest events of name by event name.
+func (c *Controller) Readings(name string) map[string]event.Event {
+	values := make(map[string]event.Event)
+	if c.bus == nil {
+		return values
+	}
+	for _, e := range c.bus.Snapshot() {
+		if e.Device == name {
+			values[e.Name] = e
+		}
+	}
+	return values
+}
+
+// lock serializes the commands to name.
+func (c *Controller) lock(name string) func() {
+	c.mu.Lock()
+	l, ok := c.locks[name]
+	if !ok {
+		l = new(sync.Mutex)
+		c.locks[name] = l
+	}
+	c.mu.Unlock()
+	l.Lock()
+	return l.Unlock
+}
+
+func (c *Controller) held(name string, d time.Duration) {
+	if c.hold != nil {
+		c.hold(name, d)
+	}
+}
+
+// Switch sets the LED name "on", "off" or "toggle" and returns whether it
+// is on. The daemon leaves it alone for hold.
+func (c *Controller) Switch(name, state string, hold time.Duration) (bool, error) {
+	d, err := c.Device(name)
+	if err != nil {
+		return false, err
+	}
+	led, ok := d.(Switch)
+	if !ok {
+		return false, fmt.Errorf("%s is not a LED: %w", name, ErrUnsupported)
+	}
+	defer c.lock(name)()
+	c.held(name, hold)
+	switch state {
+	case "on":
+		err = led.On()
+	case "off":
+		err = led.Off()
+	case "toggle":
+		err = led.Toggle()
+	default:
+		return false, fmt.Errorf("state %q: %w", state, ErrInvalid)
+	}
+	if err != nil {
+		return false, err
+	}
+	if c.bus != nil {
+		c.bus.Publish(event.Event{Device: name, Name: "state", Value: float64(led.Status())})
+	}
+	return led.Status() == 1, nil
+}
+
+// Play plays notes on the buzzer name in the background and returns how
+// long it lasts. The error wraps ErrBusy while it plays.
+func (c *Controller) Play(name string, notes []Note) (time.Duration, error) {
+	d, err := c.Device(name)
+	if err != nil {
+		return 0, err
+	}
+	p, ok := d.(Player)
+	if !ok {
+		return 0, fmt.Errorf("%s is not a buzzer: %w", name, ErrUnsupported)
+	}
+	var total time.Duration
+	for _, n := range notes {
+		if n.Hz < 0 || n.Hz > MaxHz || n.Duration <= 0 {
+			return 0, fmt.Errorf("note %v Hz for %v: %w", n.Hz, n.Duration, ErrInvalid)
+		}
+		total += n.Duration
+	}
+	if len(notes) == 0 || total > MaxMelody {
+		return 0, fmt.Errorf("melody of %v, not in (0, %v]: %w", total.Round(time.Second), MaxMelody, ErrInvalid)
+	}
+	c.mu.Lock()
+	if _, busy := c.playing[name]; busy {
+		c.mu.Unlock()
+		return 0, fmt.Errorf("%s is playing: %w", name, ErrBusy)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	pb := &playback{cancel: cancel, done: make(chan struct{})}
+	c.playing[name] = pb
+	c.mu.Unlock()
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.playing, name)
+			c.mu.Unlock()
+			cancel()
+			close(pb.done)
+		}()
+		defer c.lock(name)()
+		for _, n := range notes {
+			if err := p.Play(ctx, n.Hz, n.Duration); err != nil {
+				if err != context.Canceled {
+					log.Default().Errorf("play on %s error: %v", name, err)
+				}
+				return
+			}
+		}
+	}()
+	return total, nil
+}
+
+// Stop stops the melody of name, if any.
+func (c *Controller) Stop(name string) {
+	c.mu.Lock()
+	pb, ok := c.playing[name]
+	c.mu.Unlock()
+	if ok {
+		pb.cancel()
+	}
+}
+
+// Wait waits until the melody of name, if any, has ended and the buzzer
+// takes a new one, or ctx is done.
+func (c *Controller) Wait(ctx context.Context, name string) error {
+	c.mu.Lock()
+	pb, ok := c.playing[name]
+	c.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	select {
+	case <-pb.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// display returns the OLED name.
+func (c *Controller) display(name string) (dev.Display, error) {
+	d, err := c.Device(name)
+	if err != nil {
+		return nil, err
+	}
+	display, ok := d.(dev.Display)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a display: %w", name, ErrUnsupported)
+	}
+	return display, nil
+}
+
+// ShowText draws text at pos on the OLED name, the daemon leaves it alone
+// for hold. The text has at most MaxText characters.
+func (c *Controller) ShowText(name string, pos dev.SSD1306Pos, text string, hold time.Duration) error {
+	if n := utf8.RuneCountInString(text); n > MaxText {
+		return fmt.Errorf("text of %d characters, over %d: %w", n, MaxText, ErrInvalid)
+	}
+	display, err := c.display(name)
+	if err != nil {
+		return err
+	}
+	defer c.lock(name)()
+	c.held(name, hold)
+	return dev.ShowText(display, pos, text)
+}
+
+// ShowImage draws img converted with opts on the OLED name, the daemon
+// leaves it alone for hold.
+func (c *Controller) ShowImage(name string, img image.Image, opts *dev.ConvertOptions, hold time.Duration) error {
+	display, err := c.display(name)
+	if err != nil {
+		return err
+	}
+	defer c.lock(name)()
+	c.held(name, hold)
+	return dev.ShowImage(display, img, opts)
+}
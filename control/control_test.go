@@ -0,0 +1,105 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"math"
+	"pi/dev"
+	"pi/event"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeBuzzer plays until its context is done.
+type fakeBuzzer struct {
+	playing chan float64
+}
+
+func (b *fakeBuzzer) Name() string                   { return "buzzer" }
+func (b *fakeBuzzer) Init(ctx context.Context) error { return nil }
+func (b *fakeBuzzer) Close() error                   { return nil }
+func (b *fakeBuzzer) Health() dev.Health             { return dev.Health{State: dev.HealthPresent} }
+
+func (b *fakeBuzzer) Play(ctx context.Context, hz float64, d time.Duration) error {
+	b.playing <- hz
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestController(t *testing.T) {
+	health := dev.NewHealthRegistry()
+	buzzer := &fakeBuzzer{playing: make(chan float64, 1)}
+	health.Add("buzzer", buzzer)
+	health.Fail("rtc", errors.New("no such file or directory"))
+	c := New(health, event.New(), nil)
+
+	for name, want := range map[string]error{"rtc": ErrFailed, "humidity": ErrUnknown} {
+		if _, err := c.Device(name); !errors.Is(err, want) {
+			t.Errorf("Device(%s) = %v, want %v", name, err, want)
+		}
+	}
+	if _, err := c.Switch("buzzer", "on", 0); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Switch(buzzer) = %v", err)
+	}
+	if err := c.ShowText("oled", dev.PosTopLeft, strings.Repeat("é", MaxText+1), 0); !errors.Is(err, ErrInvalid) {
+		t.Errorf("ShowText(%d characters) = %v", MaxText+1, err)
+	}
+	for _, notes := range [][]Note{
+		nil,
+		{{Hz: 440, Duration: 0}},
+		{{Hz: -1, Duration: time.Second}},
+		{{Hz: MaxHz + 1, Duration: time.Second}},
+		{{Hz: 440, Duration: 2 * MaxMelody}},
+	} {
+		if _, err := c.Play("buzzer", notes); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Play(%v) = %v", notes, err)
+		}
+	}
+
+	notes := []Note{{Hz: 440, Duration: Beats(120, 1)}, {Hz: 0, Duration: Beats(0, 2)}}
+	if total, err := c.Play("buzzer", notes); err != nil || total != 500*time.Millisecond+1250*time.Millisecond {
+		t.Fatalf("Play() = %v, %v", total, err)
+	}
+	<-buzzer.playing
+	if _, err := c.Play("buzzer", notes); !errors.Is(err, ErrBusy) {
+		t.Errorf("Play() while playing = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := c.Wait(ctx, "buzzer"); err != context.DeadlineExceeded {
+		t.Errorf("Wait() while playing = %v", err)
+	}
+	c.Stop("buzzer")
+	if err := c.Wait(context.Background(), "buzzer"); err != nil {
+		t.Fatalf("Wait() = %v", err)
+	}
+	if _, err := c.Play("buzzer", notes); err != nil {
+		t.Errorf("Play() after Wait = %v", err)
+	}
+	c.Stop("buzzer")
+}
+
+func TestParseMelody(t *testing.T) {
+	notes, err := ParseMelody("A4 C4:2 R:0.5 Bb3 B5", 120)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Note{
+		{Hz: 440, Duration: 500 * time.Millisecond},
+		{Hz: 261.63, Duration: time.Second},
+		{Hz: 0, Duration: 250 * time.Millisecond},
+		{Hz: 233.08, Duration: 500 * time.Millisecond},
+		{Hz: 987.77, Duration: 500 * time.Millisecond},
+	}
+	for i, n := range notes {
+		if math.Abs(n.Hz-want[i].Hz) > 0.01 || n.Duration != want[i].Duration {
+			t.Errorf("note %d %+v, want %+v", i, n, want[i])
+		}
+	}
+	for _, s := range []string{"", "H4", "C9", "C6", "C4:0", "C4:x"} {
+		if _, err := ParseMelody(s, 0); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%q: %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package control
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// semitones are the notes of an octave from C.
+var semitones = map[byte]int{'C': 0, 'D': 2, 'E': 4, 'F': 5, 'G': 7, 'A': 9, 'B': 11}
+
+// ParseMelody parses notes separated by spaces at bpm, eg. "C4 E4 G4:2 R
+// A#4:0.5". A note is a name, an optional # or b and an octave, or R for a
+// rest, followed by its beats, 1 when omitted. Notes above MaxHz, from C6,
+// are refused.
+func ParseMelody(s string, bpm float64) ([]Note, error) {
+	var notes []Note
+	for _, field := range strings.Fields(s) {
+		name, beats := field, 1.0
+		if i := strings.IndexByte(field, ':'); i >= 0 {
+			var err error
+			name = field[:i]
+			if beats, err = strconv.ParseFloat(field[i+1:], 64); err != nil || beats <= 0 {
+				return nil, fmt.Errorf("note %q: beats must be a positive number: %w", field, ErrInvalid)
+			}
+		}
+		hz, err := frequency(name)
+		if err != nil {
+			return nil, fmt.Errorf("note %q: %v: %w", field, err, ErrInvalid)
+		}
+		if hz > MaxHz {
+			return nil, fmt.Errorf("note %q: %.0f Hz over %d Hz: %w", field, hz, MaxHz, ErrInvalid)
+		}
+		notes = append(notes, Note{Hz: hz, Duration: Beats(bpm, beats)})
+	}
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("empty melody: %w", ErrInvalid)
+	}
+	return notes, nil
+}
+
+// frequency returns the equal tempered frequency of a note name, A4 is
+// 440 Hz.
+func frequency(name string) (float64, error) {
+	if name == "R" || name == "r" {
+		return 0, nil
+	}
+	if len(name) < 2 {
+		return 0, fmt.Errorf("want a name and an octave")
+	}
+	n, ok := semitones[name[0]&^0x20]
+	if !ok {
+		return 0, fmt.Errorf("unknown name %c", name[0])
+	}
+	rest := name[1:]
+	switch rest[0] {
+	case '#':
+		n, rest = n+1, rest[1:]
+	case 'b':
+		n, rest = n-1, rest[1:]
+	}
+	octave, err := strconv.Atoi(rest)
+	if err != nil || octave < 0 || octave > 8 {
+		return 0, fmt.Errorf("octave %q not in [0, 8]", rest)
+	}
+	return 440 * math.Pow(2, float64((octave+1)*12+n-69)/12), nil
+}
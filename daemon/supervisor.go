@@ -0,0 +1,238 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"pi/log"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Backoff is the delay before restarting a failed worker, doubled after
+// each failure up to Max.
+type Backoff struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Options is daemon configuration struct
+type Options struct {
+	Backoff Backoff
+	// ShutdownTimeout bounds the wait for the workers to return before
+	// the devices are turned off anyway.
+	ShutdownTimeout time.Duration
+}
+
+// NewOptions reads the daemon section of the configuration.
+func NewOptions(v *viper.Viper) (*Options, error) {
+	o := &Options{
+		Backoff:         Backoff{Min: time.Second, Max: time.Minute},
+		ShutdownTimeout: 5 * time.Second,
+	}
+	if err := v.UnmarshalKey("daemon", o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Worker is a device loop run by the Supervisor.
+type Worker struct {
+	Name string
+	// Run works until ctx is done, it is restarted when it returns an
+	// error. It may be nil for devices which only need Stop.
+	Run func(ctx context.Context) error
+	// Stop leaves the device in a safe state (LED off, OLED blank, GPIO
+	// unexported), it may be nil.
+	Stop func() error
+}
+
+// Supervisor runs workers concurrently and stops them on shutdown.
+// Workers can be added, removed or replaced while it runs.
+type Supervisor struct {
+	opts    Options
+	mu      sync.Mutex
+	ctx     context.Context // set while running
+	workers []*worker
+}
+
+// worker is a Worker started by the Supervisor.
+type worker struct {
+	Worker
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSupervisor creates a new Supervisor without workers.
+func NewSupervisor(o *Options) *Supervisor {
+	s := &Supervisor{opts: *o}
+	if s.opts.Backoff.Min <= 0 {
+		s.opts.Backoff.Min = time.Second
+	}
+	if s.opts.Backoff.Max < s.opts.Backoff.Min {
+		s.opts.Backoff.Max = s.opts.Backoff.Min
+	}
+	if s.opts.ShutdownTimeout <= 0 {
+		s.opts.ShutdownTimeout = 5 * time.Second
+	}
+	return s
+}
+
+// Add registers w, workers are stopped in the reverse order. w starts at
+// once when the Supervisor is running.
+func (s *Supervisor) Add(w Worker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sw := &worker{Worker: w}
+	s.workers = append(s.workers, sw)
+	if s.ctx != nil {
+		s.start(sw)
+	}
+}
+
+// Remove stops the worker name and leaves its device in a safe state.
+func (s *Supervisor) Remove(name string) error {
+	found, err := s.remove(name)
+	if !found {
+		return fmt.Errorf("unknown worker %s", name)
+	}
+	return err
+}
+
+// Replace stops the worker named w.Name, if any, and adds w.
+func (s *Supervisor) Replace(w Worker) error {
+	_, err := s.remove(w.Name)
+	s.Add(w)
+	return err
+}
+
+// Names returns the names of the workers, in the order they were added.
+func (s *Supervisor) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, len(s.workers))
+	for i, w := range s.workers {
+		names[i] = w.Name
+	}
+	return names
+}
+
+func (s *Supervisor) remove(name string) (bool, error) {
+	s.mu.Lock()
+	var w *worker
+	for i := range s.workers {
+		if s.workers[i].Name == name {
+			w = s.workers[i]
+			s.workers = append(s.workers[:i], s.workers[i+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+	if w == nil {
+		return false, nil
+	}
+	if w.cancel != nil {
+		w.cancel()
+		s.wait([]*worker{w})
+	}
+	if w.Stop == nil {
+		return true, nil
+	}
+	return true, w.Stop()
+}
+
+// start runs w in the background, s.mu must be held.
+func (s *Supervisor) start(w *worker) {
+	w.done = make(chan struct{})
+	if w.Run == nil {
+		close(w.done)
+		return
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	w.cancel = cancel
+	go func() {
+		defer close(w.done)
+		s.supervise(ctx, w.Worker)
+	}()
+}
+
+// wait waits for workers to return, up to ShutdownTimeout.
+func (s *Supervisor) wait(workers []*worker) {
+	timeout := time.After(s.opts.ShutdownTimeout)
+	for _, w := range workers {
+		select {
+		case <-w.done:
+		case <-timeout:
+			log.Default().Warn("workers did not return in ", s.opts.ShutdownTimeout)
+			return
+		}
+	}
+}
+
+// Run starts the workers and blocks until ctx is done, then it waits for
+// them up to ShutdownTimeout and stops the devices.
+func (s *Supervisor) Run(ctx context.Context) error {
+	s.mu.Lock()
+	s.ctx = ctx
+	for _, w := range s.workers {
+		s.start(w)
+	}
+	s.mu.Unlock()
+	<-ctx.Done()
+
+	s.mu.Lock()
+	workers := append([]*worker(nil), s.workers...)
+	s.mu.Unlock()
+	log.Default().Info("stopping ", len(workers), " workers")
+	s.wait(workers)
+	for i := len(workers) - 1; i >= 0; i-- {
+		w := workers[i]
+		if w.Stop == nil {
+			continue
+		}
+		if err := w.Stop(); err != nil {
+			log.Default().Errorf("stop %s error: %v", w.Name, err)
+		}
+	}
+	return nil
+}
+
+// supervise runs w until ctx is done, restarting it with backoff. The
+// delay is reset once the worker ran longer than Backoff.Max.
+func (s *Supervisor) supervise(ctx context.Context, w Worker) {
+	delay := s.opts.Backoff.Min
+	for {
+		start := time.Now()
+		err := run(ctx, w)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			log.Default().Infof("worker %s finished", w.Name)
+			return
+		}
+		if time.Since(start) > s.opts.Backoff.Max {
+			delay = s.opts.Backoff.Min
+		}
+		log.Default().Errorf("worker %s failed: %v, restart in %v", w.Name, err, delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > s.opts.Backoff.Max {
+			delay = s.opts.Backoff.Max
+		}
+	}
+}
+
+// run calls w.Run, turning a panic into an error so the worker restarts.
+func run(ctx context.Context, w Worker) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return w.Run(ctx)
+}
@@ -0,0 +1,158 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestSupervisorRestart(t *testing.T) {
+	s := NewSupervisor(&Options{Backoff: Backoff{Min: time.Millisecond, Max: 4 * time.Millisecond}})
+	var mu sync.Mutex
+	var starts []time.Time
+	var stopped []string
+	s.Add(Worker{
+		Name: "flaky",
+		Run: func(ctx context.Context) error {
+			mu.Lock()
+			starts = append(starts, time.Now())
+			n := len(starts)
+			mu.Unlock()
+			switch n {
+			case 1:
+				return errors.New("bus error")
+			case 2:
+				panic("nil frame")
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		Stop: func() error { stopped = append(stopped, "flaky"); return nil },
+	})
+	s.Add(Worker{Name: "once", Run: func(context.Context) error { return nil }})
+	s.Add(Worker{Name: "cleanup", Stop: func() error { stopped = append(stopped, "cleanup"); return nil }})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if len(starts) != 3 {
+		t.Fatalf("flaky started %d times", len(starts))
+	}
+	if d := starts[2].Sub(starts[1]); d < 2*time.Millisecond {
+		t.Errorf("second restart after %v, want the doubled backoff", d)
+	}
+	if len(stopped) != 2 || stopped[0] != "cleanup" || stopped[1] != "flaky" {
+		t.Fatalf("stopped = %v, want reverse order", stopped)
+	}
+}
+
+func TestSupervisorShutdownTimeout(t *testing.T) {
+	s := NewSupervisor(&Options{ShutdownTimeout: 10 * time.Millisecond})
+	stopped := false
+	s.Add(Worker{
+		Name: "stuck",
+		Run:  func(context.Context) error { select {} },
+		Stop: func() error { stopped = true; return nil },
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	s.Run(ctx)
+	if !stopped || time.Since(start) > time.Second {
+		t.Fatalf("stuck worker not stopped in time: %v", time.Since(start))
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	err := v.ReadConfig(bytes.NewBufferString(`
+daemon:
+  backoff:
+    max: "30s"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := NewOptions(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Backoff.Min != time.Second || o.Backoff.Max != 30*time.Second || o.ShutdownTimeout != 5*time.Second {
+		t.Fatalf("opts = %+v", o)
+	}
+}
+
+func TestSupervisorReplace(t *testing.T) {
+	s := NewSupervisor(&Options{ShutdownTimeout: time.Second})
+	var mu sync.Mutex
+	var log []string
+	record := func(msg string) {
+		mu.Lock()
+		log = append(log, msg)
+		mu.Unlock()
+	}
+	worker := func(name, version string) Worker {
+		return Worker{
+			Name: name,
+			Run: func(ctx context.Context) error {
+				record("run " + name + version)
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			Stop: func() error { record("stop " + name + version); return nil },
+		}
+	}
+	s.Add(worker("led", "1"))
+	s.Add(worker("temp", "1"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+	time.Sleep(10 * time.Millisecond)
+	if err := s.Replace(worker("temp", "2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Remove("led"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Remove("led"); err == nil {
+		t.Error("removed an unknown worker")
+	}
+	s.Add(worker("rtc", "1"))
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-done
+
+	if names := s.Names(); len(names) != 2 || names[0] != "temp" || names[1] != "rtc" {
+		t.Errorf("names = %v", names)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	stops := []string{}
+	for _, l := range log {
+		if l[:4] == "stop" {
+			stops = append(stops, l)
+		}
+	}
+	want := []string{"stop temp1", "stop led1", "stop rtc1", "stop temp2"}
+	if len(stops) != len(want) {
+		t.Fatalf("stops = %v, want %v", stops, want)
+	}
+	for i := range want {
+		if stops[i] != want[i] {
+			t.Fatalf("stops = %v, want %v", stops, want)
+		}
+	}
+}
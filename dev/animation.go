@@ -0,0 +1,189 @@
+package dev
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// defaultFrameDelay is used for frames without a delay and no target FPS.
+const defaultFrameDelay = 100 * time.Millisecond
+
+// Animation is a sequence of frames pre-converted to the panel format.
+type Animation struct {
+	Frames []*image1bit.VerticalLSB
+	// Delays holds how long each frame stays on screen, 0 for the default.
+	Delays []time.Duration
+}
+
+// NewAnimation converts frames for a bounds sized panel, every frame is
+// shown for delay.
+func NewAnimation(frames []image.Image, delay time.Duration, bounds image.Rectangle, opts *ConvertOptions) *Animation {
+	a := &Animation{}
+	for _, f := range frames {
+		a.Frames = append(a.Frames, ConvertImage(f, bounds, opts))
+		a.Delays = append(a.Delays, delay)
+	}
+	return a
+}
+
+// LoadGIF decodes an animated GIF and converts its frames, partial frames
+// are composed according to their disposal method.
+func LoadGIF(r io.Reader, bounds image.Rectangle, opts *ConvertOptions) (*Animation, error) {
+	g, err := gif.DecodeAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.Image) == 0 {
+		return nil, errors.New("gif has no frames")
+	}
+	canvas := image.NewRGBA(image.Rect(0, 0, g.Config.Width, g.Config.Height))
+	if canvas.Rect.Empty() {
+		canvas = image.NewRGBA(g.Image[0].Bounds())
+	}
+	a := &Animation{}
+	for i, frame := range g.Image {
+		var previous *image.RGBA
+		disposal := byte(gif.DisposalNone)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(canvas.Rect)
+			copy(previous.Pix, canvas.Pix)
+		}
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		a.Frames = append(a.Frames, ConvertImage(canvas, bounds, opts))
+		a.Delays = append(a.Delays, time.Duration(g.Delay[i])*10*time.Millisecond)
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+	return a, nil
+}
+
+// LoadSpriteSheet cuts count frames of size frame out of sheet, left to right
+// then top to bottom, and converts them.
+func LoadSpriteSheet(sheet image.Image, frame image.Point, count int, delay time.Duration, bounds image.Rectangle, opts *ConvertOptions) (*Animation, error) {
+	sb := sheet.Bounds()
+	if frame.X <= 0 || frame.Y <= 0 || frame.X > sb.Dx() || frame.Y > sb.Dy() {
+		return nil, fmt.Errorf("invalid sprite size %v for a %v sheet", frame, sb.Size())
+	}
+	perRow := sb.Dx() / frame.X
+	if max := perRow * (sb.Dy() / frame.Y); count <= 0 || count > max {
+		count = max
+	}
+	frames := make([]image.Image, 0, count)
+	for i := 0; i < count; i++ {
+		min := sb.Min.Add(image.Point{X: i % perRow * frame.X, Y: i / perRow * frame.Y})
+		sprite := image.NewRGBA(image.Rectangle{Max: frame})
+		draw.Draw(sprite, sprite.Rect, sheet, min, draw.Src)
+		frames = append(frames, sprite)
+	}
+	return NewAnimation(frames, delay, bounds, opts), nil
+}
+
+var (
+	animationMu    sync.Mutex
+	animationCache = map[string]*Animation{}
+)
+
+// LoadGIFFile loads and converts a GIF file once, later calls with the same
+// path, bounds and options return the cached animation.
+func LoadGIFFile(path string, bounds image.Rectangle, opts *ConvertOptions) (*Animation, error) {
+	if opts == nil {
+		opts = &DefaultConvertOptions
+	}
+	key := fmt.Sprintf("%s|%v|%+v", path, bounds, *opts)
+	animationMu.Lock()
+	defer animationMu.Unlock()
+	if a, ok := animationCache[key]; ok {
+		return a, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	a, err := LoadGIF(f, bounds, opts)
+	if err != nil {
+		return nil, err
+	}
+	animationCache[key] = a
+	return a, nil
+}
+
+// PlayOptions controls Play.
+type PlayOptions struct {
+	// FPS overrides the frame delays when positive.
+	FPS float64
+	// Loops is the number of times the animation plays, 0 loops forever.
+	Loops int
+}
+
+// PlayStats reports how a playback went.
+type PlayStats struct {
+	Frames  int
+	Dropped int
+	Elapsed time.Duration
+}
+
+// Play shows a on d until all loops are done or ctx is done. When the display
+// cannot keep up, late frames are dropped to stay on schedule.
+func Play(ctx context.Context, d Display, a *Animation, o *PlayOptions) (stats PlayStats, err error) {
+	if len(a.Frames) == 0 {
+		return stats, errors.New("animation has no frames")
+	}
+	if o == nil {
+		o = &PlayOptions{Loops: 1}
+	}
+	start := time.Now()
+	defer func() { stats.Elapsed = time.Since(start) }()
+	timer := time.NewTimer(0)
+	<-timer.C
+	next := start
+	for loop := 0; o.Loops == 0 || loop < o.Loops; loop++ {
+		for i, frame := range a.Frames {
+			next = next.Add(frameDelay(a, i, o))
+			last := loop == o.Loops-1 && i == len(a.Frames)-1
+			if !last && time.Now().After(next) {
+				stats.Dropped++
+				continue
+			}
+			if err = d.DrawFrame(frame); err != nil {
+				return
+			}
+			stats.Frames++
+			timer.Reset(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return stats, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+	return
+}
+
+func frameDelay(a *Animation, i int, o *PlayOptions) time.Duration {
+	if o.FPS > 0 {
+		return time.Duration(float64(time.Second) / o.FPS)
+	}
+	if i < len(a.Delays) && a.Delays[i] > 0 {
+		return a.Delays[i]
+	}
+	return defaultFrameDelay
+}
@@ -0,0 +1,140 @@
+package dev
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+	"time"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// slowDisplay records frames and takes delay to draw each one.
+type slowDisplay struct {
+	delay  time.Duration
+	frames []*image1bit.VerticalLSB
+}
+
+func (d *slowDisplay) Bounds() image.Rectangle { return panel }
+func (d *slowDisplay) DrawFrame(f *image1bit.VerticalLSB) error {
+	time.Sleep(d.delay)
+	d.frames = append(d.frames, f)
+	return nil
+}
+func (d *slowDisplay) SetContrast(byte) error { return nil }
+func (d *slowDisplay) On() error              { return nil }
+func (d *slowDisplay) Off() error             { return nil }
+func (d *slowDisplay) Halt() error            { return nil }
+
+// testGIF returns a 16x16 GIF of 3 frames: a full white background, then a
+// black 8x8 patch kept, then one restored to the previous frame.
+func testGIF(t *testing.T) []byte {
+	pal := color.Palette{color.Black, color.White}
+	full := image.NewPaletted(image.Rect(0, 0, 16, 16), pal)
+	for i := range full.Pix {
+		full.Pix[i] = 1
+	}
+	patch := image.NewPaletted(image.Rect(0, 0, 8, 8), pal)
+	other := image.NewPaletted(image.Rect(8, 8, 16, 16), pal)
+	g := &gif.GIF{
+		Image:    []*image.Paletted{full, patch, other},
+		Delay:    []int{1, 2, 3},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalNone, gif.DisposalPrevious},
+		Config:   image.Config{Width: 16, Height: 16, ColorModel: pal},
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestLoadGIF(t *testing.T) {
+	bounds := image.Rect(0, 0, 16, 16)
+	opts := ConvertOptions{Scale: ScaleCrop}
+	a, err := LoadGIF(bytes.NewReader(testGIF(t)), bounds, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Frames) != 3 {
+		t.Fatalf("got %d frames", len(a.Frames))
+	}
+	want := []int{256, 256 - 64, 256 - 64 - 64}
+	for i, f := range a.Frames {
+		if n := countOn(f); n != want[i] {
+			t.Errorf("frame %d lit %d pixels, want %d", i, n, want[i])
+		}
+	}
+	if a.Delays[2] != 30*time.Millisecond {
+		t.Errorf("delay = %v", a.Delays[2])
+	}
+}
+
+func TestLoadSpriteSheet(t *testing.T) {
+	// 4 sprites of 8x8 on a 2x2 sheet, sprite i has i+1 lit rows.
+	sheet := image.NewGray(image.Rect(0, 0, 16, 16))
+	for i := 0; i < 4; i++ {
+		ox, oy := i%2*8, i/2*8
+		for y := 0; y <= i; y++ {
+			for x := 0; x < 8; x++ {
+				sheet.SetGray(ox+x, oy+y, color.Gray{Y: 0xff})
+			}
+		}
+	}
+	opts := ConvertOptions{Scale: ScaleCrop}
+	a, err := LoadSpriteSheet(sheet, image.Pt(8, 8), 0, time.Millisecond, image.Rect(0, 0, 8, 8), &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Frames) != 4 {
+		t.Fatalf("got %d frames", len(a.Frames))
+	}
+	for i, f := range a.Frames {
+		if n := countOn(f); n != (i+1)*8 {
+			t.Errorf("sprite %d lit %d pixels", i, n)
+		}
+	}
+	if _, err := LoadSpriteSheet(sheet, image.Pt(32, 8), 0, 0, panel, nil); err == nil {
+		t.Error("oversized sprite accepted")
+	}
+}
+
+func TestPlay(t *testing.T) {
+	frames := make([]image.Image, 10)
+	for i := range frames {
+		frames[i] = image.NewGray(image.Rect(0, 0, 8, 8))
+	}
+	a := NewAnimation(frames, 0, panel, nil)
+
+	fast := &slowDisplay{}
+	stats, err := Play(context.Background(), fast, a, &PlayOptions{FPS: 500, Loops: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Frames+stats.Dropped != 20 || stats.Frames != len(fast.frames) {
+		t.Fatalf("stats = %+v, drawn %d", stats, len(fast.frames))
+	}
+
+	// A display three times slower than the frame period drops frames but
+	// still shows the last one.
+	slow := &slowDisplay{delay: 6 * time.Millisecond}
+	stats, err = Play(context.Background(), slow, a, &PlayOptions{FPS: 500, Loops: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Dropped == 0 || stats.Frames+stats.Dropped != 10 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if slow.frames[len(slow.frames)-1] != a.Frames[9] {
+		t.Fatal("last frame not shown")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err = Play(ctx, fast, a, &PlayOptions{FPS: 100}); err != context.DeadlineExceeded {
+		t.Fatalf("endless loop returned %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// https://cdn-shop.adafruit.com/datasheets/BST-BMP180-DS000-09.pdf
+const (
+	bmp180ChipID       = 0x55
+	bmp180RegChipID    = 0xD0
+	bmp180RegCalib     = 0xAA
+	bmp180RegControl   = 0xF4
+	bmp180RegData      = 0xF6
+	bmp180CmdTemp      = 0x2E
+	bmp180CmdPressure  = 0x34
+	bmp180TempDelay    = 5 * time.Millisecond
+	bmp180MaxOversampl = 3
+)
+
+// bmp180PressureDelay is the conversion time by oversampling setting.
+var bmp180PressureDelay = [...]time.Duration{
+	5 * time.Millisecond,
+	8 * time.Millisecond,
+	14 * time.Millisecond,
+	26 * time.Millisecond,
+}
+
+// registers is the register access of driver.I2CDevice.
+type registers interface {
+	ReadByteData(reg uint8) (uint8, error)
+	WriteByteData(reg uint8, val uint8) error
+	Close() error
+}
+
+// bmp180Calibration is the factory calibration of the EEPROM.
+type bmp180Calibration struct {
+	ac1, ac2, ac3 int64
+	ac4, ac5, ac6 int64
+	b1, b2        int64
+	mb, mc, md    int64
+}
+
+// BMP180 reads the Pioneer600 barometric pressure and temperature sensor.
+type BMP180 struct {
+	health
+	name string
+	i2c  registers
+	cal  bmp180Calibration
+	// Oversampling (0-3) averages 2^n pressure samples, trading conversion
+	// time for noise.
+	Oversampling int
+	sleep        func(time.Duration)
+}
+
+func NewBMP180() (*BMP180, error) {
+	return NewBMP180At(I2cDev, I2cAddrBMP180)
+}
+
+// NewBMP180At creates the sensor at address on bus.
+func NewBMP180At(bus string, address int) (*BMP180, error) {
+	dev, err := openI2C(bus, address)
+	if err != nil {
+		return nil, err
+	}
+	return newBMP180(dev, i2cName("bmp180", bus, address)), nil
+}
+
+func newBMP180(r registers, name string) *BMP180 {
+	return &BMP180{name: name, i2c: r, sleep: time.Sleep}
+}
+
+// Name returns bmp180@ and the bus and address.
+func (b *BMP180) Name() string {
+	return b.name
+}
+
+// Init checks the chip id and reads the calibration.
+func (b *BMP180) Init(ctx context.Context) error {
+	if b.Oversampling < 0 || b.Oversampling > bmp180MaxOversampl {
+		return b.fail(fmt.Errorf("bmp180 oversampling %d out of 0-3", b.Oversampling))
+	}
+	id, err := b.i2c.ReadByteData(bmp180RegChipID)
+	if err != nil {
+		return b.fail(err)
+	}
+	if id != bmp180ChipID {
+		return b.fail(fmt.Errorf("bmp180 chip id 0x%02x, want 0x%02x", id, bmp180ChipID))
+	}
+	var words [11]int64
+	for i := range words {
+		w, err := b.word(bmp180RegCalib + uint8(2*i))
+		if err != nil {
+			return b.fail(err)
+		}
+		words[i] = w
+	}
+	signed := func(w int64) int64 { return int64(int16(w)) }
+	b.cal = bmp180Calibration{
+		ac1: signed(words[0]), ac2: signed(words[1]), ac3: signed(words[2]),
+		ac4: words[3], ac5: words[4], ac6: words[5],
+		b1: signed(words[6]), b2: signed(words[7]),
+		mb: signed(words[8]), mc: signed(words[9]), md: signed(words[10]),
+	}
+	return b.fail(nil)
+}
+
+// Close closes the bus.
+func (b *BMP180) Close() error {
+	return b.i2c.Close()
+}
+
+// Read returns the temperature in °C and the pressure in Pa.
+func (b *BMP180) Read() (temperature, pressure float64, err error) {
+	defer func() { err = b.observe(err) }()
+	if err = b.i2c.WriteByteData(bmp180RegControl, bmp180CmdTemp); err != nil {
+		return
+	}
+	b.sleep(bmp180TempDelay)
+	ut, err := b.word(bmp180RegData)
+	if err != nil {
+		return
+	}
+	oss := uint(b.Oversampling)
+	if err = b.i2c.WriteByteData(bmp180RegControl, bmp180CmdPressure|uint8(oss<<6)); err != nil {
+		return
+	}
+	b.sleep(bmp180PressureDelay[oss])
+	msb, err := b.word(bmp180RegData)
+	if err != nil {
+		return
+	}
+	xlsb, err := b.i2c.ReadByteData(bmp180RegData + 2)
+	if err != nil {
+		return
+	}
+	up := (msb<<8 | int64(xlsb)) >> (8 - oss)
+	t, p := b.cal.compensate(ut, up, oss)
+	return float64(t) / 10, float64(p), nil
+}
+
+// word reads the big endian 16 bits register at reg.
+func (b *BMP180) word(reg uint8) (int64, error) {
+	msb, err := b.i2c.ReadByteData(reg)
+	if err != nil {
+		return 0, err
+	}
+	lsb, err := b.i2c.ReadByteData(reg + 1)
+	if err != nil {
+		return 0, err
+	}
+	return int64(msb)<<8 | int64(lsb), nil
+}
+
+// compensate returns the temperature in 0.1 °C and the pressure in Pa of
+// the raw values ut and up, following the datasheet integer algorithm.
+func (c *bmp180Calibration) compensate(ut, up int64, oss uint) (t, p int64) {
+	x1 := (ut - c.ac6) * c.ac5 >> 15
+	x2 := c.mc << 11 / (x1 + c.md)
+	b5 := x1 + x2
+	t = (b5 + 8) >> 4
+
+	b6 := b5 - 4000
+	x1 = c.b2 * (b6 * b6 >> 12) >> 11
+	x2 = c.ac2 * b6 >> 11
+	x3 := x1 + x2
+	b3 := ((c.ac1*4+x3)<<oss + 2) / 4
+	x1 = c.ac3 * b6 >> 13
+	x2 = c.b1 * (b6 * b6 >> 12) >> 16
+	x3 = (x1 + x2 + 2) >> 2
+	b4 := uint64(c.ac4) * uint64(x3+32768) >> 15
+	b7 := uint64(up-b3) * uint64(50000>>oss)
+	if b7 < 0x80000000 {
+		p = int64(b7 * 2 / b4)
+	} else {
+		p = int64(b7 / b4 * 2)
+	}
+	x1 = (p >> 8) * (p >> 8)
+	x1 = x1 * 3038 >> 16
+	x2 = -7357 * p >> 16
+	p += (x1 + x2 + 3791) >> 4
+	return t, p
+}
@@ -0,0 +1,76 @@
+package dev
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeBMP180 converts to the raw values ut and up on a control write.
+type fakeBMP180 struct {
+	regs   [256]byte
+	ut, up int64
+	err    error
+}
+
+func newFakeBMP180(cal []int64, ut, up int64) *fakeBMP180 {
+	f := &fakeBMP180{ut: ut, up: up}
+	f.regs[bmp180RegChipID] = bmp180ChipID
+	for i, w := range cal {
+		f.regs[bmp180RegCalib+2*i] = byte(uint16(w) >> 8)
+		f.regs[bmp180RegCalib+2*i+1] = byte(w)
+	}
+	return f
+}
+
+func (f *fakeBMP180) ReadByteData(reg uint8) (uint8, error) {
+	return f.regs[reg], f.err
+}
+
+func (f *fakeBMP180) WriteByteData(reg uint8, val uint8) error {
+	if f.err != nil {
+		return f.err
+	}
+	if reg == bmp180RegControl {
+		if val == bmp180CmdTemp {
+			f.regs[bmp180RegData], f.regs[bmp180RegData+1] = byte(f.ut>>8), byte(f.ut)
+		} else {
+			raw := f.up << (8 - val>>6)
+			f.regs[bmp180RegData], f.regs[bmp180RegData+1], f.regs[bmp180RegData+2] = byte(raw>>16), byte(raw>>8), byte(raw)
+		}
+	}
+	return nil
+}
+
+func (f *fakeBMP180) Close() error {
+	return nil
+}
+
+func TestBMP180(t *testing.T) {
+	// The calculation example of the datasheet.
+	cal := []int64{408, -72, -14383, 32741, 32757, 23153, 6190, 4, -32768, -8711, 2868}
+	f := newFakeBMP180(cal, 27898, 23843)
+	b := newBMP180(f, "bmp180")
+	b.sleep = func(time.Duration) {}
+	if err := b.Init(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	temperature, pressure, err := b.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if temperature != 15 || pressure != 69964 {
+		t.Errorf("Read() = %v °C, %v Pa, want 15 °C, 69964 Pa", temperature, pressure)
+	}
+
+	f.err = errors.New("remote I/O error")
+	if _, _, err = b.Read(); err == nil || b.Health().State != HealthDegraded {
+		t.Errorf("Read() error %v, health %+v", err, b.Health())
+	}
+
+	f.err, f.regs[bmp180RegChipID] = nil, 0x58
+	if err = b.Init(context.Background()); err == nil || b.Health().State != HealthFailed {
+		t.Errorf("Init() of a BMP280 = %v", err)
+	}
+}
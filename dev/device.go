@@ -0,0 +1,192 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"pi/driver"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Health states of a Device.
+const (
+	// HealthUnknown is the state before Init.
+	HealthUnknown = "unknown"
+	// HealthPresent is a device answering on its bus.
+	HealthPresent = "present"
+	// HealthDegraded is a device whose last transactions failed.
+	HealthDegraded = "degraded"
+	// HealthFailed is a device which could not be opened or initialized,
+	// or failed healthFailures transactions in a row.
+	HealthFailed = "failed"
+)
+
+// healthFailures is the number of failed transactions in a row turning a
+// degraded device into a failed one.
+const healthFailures = 3
+
+// Health is the state of a Device.
+type Health struct {
+	State string `json:"state"`
+	// Error is the last error, empty once the device answers again.
+	Error string `json:"error,omitempty"`
+	// Since is the time of the last state change.
+	Since time.Time `json:"since"`
+}
+
+// Device is the lifecycle shared by the drivers. Constructors only open
+// the bus, Init talks to the chip and may be called again to recover it.
+type Device interface {
+	// Name describes the driver and where it is, eg. ds3231@/dev/i2c-1:0x68.
+	Name() string
+	Init(ctx context.Context) error
+	// Close leaves the chip in a safe state and releases the bus.
+	Close() error
+	Health() Health
+}
+
+// health tracks the Health of a driver from the result of its
+// transactions, it is embedded by the drivers.
+type health struct {
+	mu       sync.Mutex
+	state    Health
+	failures int
+}
+
+// Health returns the state of the device.
+func (h *health) Health() Health {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.state.State == "" {
+		return Health{State: HealthUnknown}
+	}
+	return h.state
+}
+
+// observe records the result of a transaction and returns err.
+func (h *health) observe(err error) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err == nil {
+		h.failures = 0
+		h.set(HealthPresent, "")
+		return nil
+	}
+	h.failures++
+	if h.failures >= healthFailures {
+		h.set(HealthFailed, err.Error())
+	} else {
+		h.set(HealthDegraded, err.Error())
+	}
+	return err
+}
+
+// fail records an Init error, the device is failed at once.
+func (h *health) fail(err error) error {
+	if err == nil {
+		return h.observe(nil)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures = healthFailures
+	h.set(HealthFailed, err.Error())
+	return err
+}
+
+func (h *health) set(state, msg string) {
+	if h.state.State != state {
+		h.state.Since = time.Now()
+	}
+	h.state.State = state
+	h.state.Error = msg
+}
+
+// DeviceHealth is an entry of the HealthRegistry report.
+type DeviceHealth struct {
+	// Name is the name of the devices section.
+	Name string `json:"name"`
+	// Driver is the Device Name, empty when it could not be created.
+	Driver string `json:"driver,omitempty"`
+	Health
+}
+
+// HealthRegistry reports the health of the devices by name.
+type HealthRegistry struct {
+	mu      sync.Mutex
+	devices map[string]Device
+	failed  map[string]Health
+}
+
+// NewHealthRegistry creates a new empty HealthRegistry.
+func NewHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{
+		devices: make(map[string]Device),
+		failed:  make(map[string]Health),
+	}
+}
+
+// Add reports the health of d as name.
+func (r *HealthRegistry) Add(name string, d Device) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.failed, name)
+	r.devices[name] = d
+}
+
+// Fail reports name as failed with err, for a device which could not be
+// created.
+func (r *HealthRegistry) Fail(name string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.devices, name)
+	r.failed[name] = Health{State: HealthFailed, Error: err.Error(), Since: time.Now()}
+}
+
+// Device returns the device added as name, nil when there is none.
+func (r *HealthRegistry) Device(name string) Device {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.devices[name]
+}
+
+// Remove stops reporting name.
+func (r *HealthRegistry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.devices, name)
+	delete(r.failed, name)
+}
+
+// Report returns the health of every device, sorted by name.
+func (r *HealthRegistry) Report() []DeviceHealth {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report := make([]DeviceHealth, 0, len(r.devices)+len(r.failed))
+	for name, d := range r.devices {
+		report = append(report, DeviceHealth{Name: name, Driver: d.Name(), Health: d.Health()})
+	}
+	for name, h := range r.failed {
+		report = append(report, DeviceHealth{Name: name, Health: h})
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Name < report[j].Name })
+	return report
+}
+
+// openI2C opens the chip at address on bus.
+func openI2C(bus string, address int) (*driver.I2CDevice, error) {
+	dev, err := driver.NewI2cDevice(bus)
+	if err != nil {
+		return nil, err
+	}
+	if err = dev.SetAddress(address); err != nil {
+		dev.Close()
+		return nil, err
+	}
+	return dev, nil
+}
+
+// i2cName is the Device Name of the chip at address on bus.
+func i2cName(chip, bus string, address int) string {
+	return fmt.Sprintf("%s@%s:0x%02x", chip, bus, address)
+}
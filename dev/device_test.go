@@ -0,0 +1,76 @@
+package dev
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// flakyBus is a fake I2C device failing while err is set.
+type flakyBus struct {
+	err error
+}
+
+func (b *flakyBus) WriteBlockData(reg uint8, data []byte) error {
+	return b.err
+}
+
+func TestDeviceHealth(t *testing.T) {
+	bus := &flakyBus{}
+	s, err := newSSD1306(&i2cBus{dev: bus}, fakePin{}, ControllerSSD1306)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Device = s
+	if h := d.Health(); h.State != HealthUnknown {
+		t.Fatalf("before Init: %+v", h)
+	}
+	if err = d.Init(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if h := d.Health(); h.State != HealthPresent || h.Since.IsZero() {
+		t.Fatalf("after Init: %+v", h)
+	}
+
+	bus.err = errors.New("remote I/O error")
+	for i, want := range []string{HealthDegraded, HealthDegraded, HealthFailed} {
+		if err = s.Display(); err == nil {
+			t.Fatal("display error lost")
+		}
+		if h := d.Health(); h.State != want || h.Error != "remote I/O error" {
+			t.Fatalf("after %d errors: %+v, want %s", i+1, h, want)
+		}
+	}
+	bus.err = nil
+	if err = s.Display(); err != nil {
+		t.Fatal(err)
+	}
+	if h := d.Health(); h.State != HealthPresent || h.Error != "" {
+		t.Fatalf("after recovery: %+v", h)
+	}
+
+	bus.err = errors.New("no ack")
+	if err = d.Init(context.Background()); err == nil || d.Health().State != HealthFailed {
+		t.Fatalf("Init error %v: %+v", err, d.Health())
+	}
+}
+
+func TestHealthRegistry(t *testing.T) {
+	r := NewHealthRegistry()
+	led := NewLEDOneAt(26)
+	r.Add("led1", led)
+	r.Fail("rtc", errors.New("open /dev/i2c-1: no such file or directory"))
+	r.Add("buzzer", led)
+	r.Remove("buzzer")
+
+	report := r.Report()
+	if len(report) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	if h := report[0]; h.Name != "led1" || h.Driver != "led@BCM26" || h.State != HealthUnknown {
+		t.Errorf("led1 = %+v", h)
+	}
+	if h := report[1]; h.Name != "rtc" || h.Driver != "" || h.State != HealthFailed || h.Error == "" {
+		t.Errorf("rtc = %+v", h)
+	}
+}
@@ -0,0 +1,178 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+const (
+	// DisplaySSD1306H drives the OLED through periph.io (default).
+	DisplaySSD1306H = "ssd1306h"
+	// DisplaySSD1306 drives the OLED with the built-in SPI driver.
+	DisplaySSD1306 = "ssd1306"
+	// DisplayEmulator renders frames to PNG, GIF or the terminal.
+	DisplayEmulator = "emulator"
+)
+
+const (
+	// BusSPI uses the SPI bus with the D/C pin (default).
+	BusSPI = "spi"
+	// BusI2C uses the I2C bus, set by the jumpers on the Pioneer600.
+	BusI2C = "i2c"
+)
+
+const (
+	// FontWidth is the advance of the built-in 7x13 font.
+	FontWidth = 7
+	// FontHeight is the line height of the built-in 7x13 font.
+	FontHeight = 13
+)
+
+// Display is implemented by the SSD1306 drivers and the emulator.
+type Display interface {
+	// Bounds returns the panel size, Min is always {0, 0}.
+	Bounds() image.Rectangle
+	// DrawFrame sends a whole 1-bit frame to the panel.
+	DrawFrame(frame *image1bit.VerticalLSB) error
+	// SetContrast sets the display contrast (0-255).
+	SetContrast(contrast byte) error
+	// On turns on the display.
+	On() error
+	// Off turns off the display.
+	Off() error
+	// Halt turns off the display and releases it.
+	Halt() error
+}
+
+// DisplayOpts is display configuration struct
+type DisplayOpts struct {
+	Driver string
+	// Controller selects the init sequence of the built-in driver.
+	Controller string
+	// Bus is BusSPI or BusI2C.
+	Bus string
+	// Address is the I2C address, I2cAddrSSD1306 when 0.
+	Address int
+	// Device is the bus device, /dev/spidev0.0 or /dev/i2c-1 when empty.
+	Device string
+	// DCPin and RSTPin are the BCM pins of the D/C and reset lines, 16 and
+	// 19 when 0.
+	DCPin  int
+	RSTPin int
+	// Speed is the SPI clock of the built-in driver in Hz, 500kHz when 0.
+	Speed    int64
+	Emulator EmulatorOpts
+	Power    PowerOpts
+}
+
+// NewDisplayOpts reads the display section of the configuration.
+func NewDisplayOpts(v *viper.Viper) (*DisplayOpts, error) {
+	o := &DisplayOpts{Driver: DisplaySSD1306H}
+	if err := v.UnmarshalKey("display", o); err != nil {
+		return nil, err
+	}
+	if o.Driver == "" {
+		o.Driver = DisplaySSD1306H
+	}
+	return o, nil
+}
+
+// OpenDisplay creates and initializes the display selected by o.Driver on
+// o.Bus.
+func OpenDisplay(o *DisplayOpts) (Display, error) {
+	d, err := newDisplay(o)
+	if err != nil {
+		return nil, err
+	}
+	if err = d.(Device).Init(context.Background()); err != nil {
+		d.(Device).Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// newDisplay creates the display of o without initializing it. periph.io
+// only knows the SSD1306 at 0x3C, other setups use the built-in driver.
+func newDisplay(o *DisplayOpts) (Display, error) {
+	driver := o.Driver
+	if driver == DisplaySSD1306H && o.Controller != "" && o.Controller != ControllerSSD1306 {
+		driver = DisplaySSD1306
+	}
+	if driver == DisplaySSD1306H && o.Bus == BusI2C && o.Address != 0 && o.Address != I2cAddrSSD1306 {
+		driver = DisplaySSD1306
+	}
+	switch o.Bus {
+	case "", BusSPI, BusI2C:
+	default:
+		return nil, fmt.Errorf("unknown display bus %q", o.Bus)
+	}
+	var d Display
+	var err error
+	switch driver {
+	case DisplaySSD1306H:
+		d, err = openSSD1306H(o)
+	case DisplaySSD1306:
+		d, err = openSSD1306(o)
+	case DisplayEmulator:
+		d, err = NewEmulator(&o.Emulator)
+	default:
+		return nil, fmt.Errorf("unknown display driver %q", o.Driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// ShowImage converts img with opts (nil for defaults) and draws it on d.
+func ShowImage(d Display, img image.Image, opts *ConvertOptions) error {
+	return d.DrawFrame(convert(d, img, opts))
+}
+
+// ShowText clears d and draws text at pos.
+func ShowText(d Display, pos SSD1306Pos, text string) error {
+	return d.DrawFrame(renderText(d.Bounds(), pos, text))
+}
+
+// DrawString draws text on dst with the built-in font, p is the top left
+// corner of the first glyph.
+func DrawString(dst draw.Image, p image.Point, text string) {
+	drawText(dst, p, text)
+}
+
+// TextWidth returns the width in pixels of text in the built-in font.
+func TextWidth(text string) int {
+	return utf8.RuneCountInString(text) * FontWidth
+}
+
+func convert(d Display, src image.Image, opts *ConvertOptions) *image1bit.VerticalLSB {
+	return ConvertImage(src, d.Bounds(), opts)
+}
+
+// renderText returns a blank frame with text drawn at pos.
+func renderText(bounds image.Rectangle, pos SSD1306Pos, text string) *image1bit.VerticalLSB {
+	img := image1bit.NewVerticalLSB(bounds)
+	switch pos {
+	case PosTopCenter:
+		drawTextTopCenter(img, text)
+	case PosTopLeft:
+		drawTextTopLeft(img, text)
+	case PosTopRight:
+		drawTextTopRight(img, text)
+	case PosBottomLeft:
+		drawTextBottomLeft(img, text)
+	case PosBottomRight:
+		drawTextBottomRight(img, text)
+	case PosBottomCenter:
+		drawTextBottomCenter(img, text)
+	default:
+
+	}
+	return img
+}
@@ -1,10 +1,10 @@
 package dev
 
 import (
+	"context"
 	"errors"
-	"io/ioutil"
 	"os"
-	"path/filepath"
+	"pi/driver"
 	"pi/log"
 	"regexp"
 	"sort"
@@ -26,17 +26,23 @@ import (
 const (
 	rootPath          = "/sys/bus/w1/devices/"
 	ds18b20PrefixName = "28-00"
-	ds18b20Slave      = "/w1_slave"
 )
 
 type DS18B20 struct {
+	health
 	name      string
 	temperate float64
 }
 
 func NewDS18B20() *DS18B20 {
+	return NewDS18B20Sensor("")
+}
+
+// NewDS18B20Sensor creates the sensor with the 1-Wire id name (eg.
+// 28-00000a1b2c3d), the first one found when empty.
+func NewDS18B20Sensor(name string) *DS18B20 {
 	return &DS18B20{
-		name:      "",
+		name:      name,
 		temperate: 0.0,
 	}
 }
@@ -57,24 +63,45 @@ func readDirNames(dirname string) ([]string, error) {
 	return names, nil
 }
 
-func (d *DS18B20) FetchTemperate() (err error) {
-	// find ds18b20
-	names, _ := readDirNames(rootPath)
-	for _, name := range names {
-		if strings.HasPrefix(name, ds18b20PrefixName) {
-			d.name = name
-			log.Default().Info("ds18b20's name:  ", name)
-			break
+// Init finds the sensor when no name was given and checks it is present.
+func (d *DS18B20) Init(ctx context.Context) error {
+	if err := d.find(); err != nil {
+		return d.fail(err)
+	}
+	_, err := os.Stat(rootPath + d.name)
+	return d.fail(err)
+}
+
+// Close does nothing, the 1-Wire bus belongs to the kernel.
+func (d *DS18B20) Close() error {
+	return nil
+}
+
+// find sets the name to the first sensor found when empty.
+func (d *DS18B20) find() error {
+	if d.name == "" {
+		names, _ := readDirNames(rootPath)
+		for _, name := range names {
+			if strings.HasPrefix(name, ds18b20PrefixName) {
+				d.name = name
+				log.Default().Info("ds18b20's name:  ", name)
+				break
+			}
 		}
 	}
 	if d.name == "" {
-		err = errors.New("Can not find ds18b20.")
+		return errors.New("Can not find ds18b20.")
+	}
+	return nil
+}
+
+func (d *DS18B20) FetchTemperate() (err error) {
+	defer func() { err = d.observe(err) }()
+	if err = d.find(); err != nil {
 		return
 	}
 	//calculate temperate
-	devPath := rootPath + d.name + (string)(filepath.Separator) + ds18b20Slave
-	log.Default().Infof("devPath = ", devPath)
-	data, err := ioutil.ReadFile(devPath)
+	data, err := driver.ReadW1Slave(rootPath, d.name)
 	if err != nil {
 		return
 	}
@@ -87,7 +114,11 @@ func (d *DS18B20) FetchTemperate() (err error) {
 	return
 }
 
+// Name returns the 1-Wire id of the sensor, ds18b20 until it is found.
 func (d *DS18B20) Name() string {
+	if d.name == "" {
+		return "ds18b20"
+	}
 	return d.name
 }
 
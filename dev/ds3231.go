@@ -1,9 +1,9 @@
 package dev
 
 import (
+	"context"
 	"fmt"
-	"pi/driver"
-	"pi/log"
+	"time"
 )
 
 //http://www.waveshare.net/study/article-623-1.html
@@ -19,43 +19,118 @@ var (
 	weekArr = [7]string{"SUN", "Mon", "Tues", "Wed", "Thur", "Fri", "Sat"}
 )
 
+// rtcRegisters is the register access of driver.I2CDevice used by the
+// DS3231.
+type rtcRegisters interface {
+	ReadByteData(reg uint8) (uint8, error)
+	ReadBlockData(reg uint8, data []byte) error
+	WriteBlockData(reg uint8, data []byte) error
+	Close() error
+}
+
 type DS3231 struct {
-	i2c  *driver.I2CDevice
+	health
+	name string
+	i2c  rtcRegisters
 	time string
 }
 
-func NewDS3231() *DS3231 {
-	dev, err := driver.NewI2cDevice(I2cDev)
+func NewDS3231() (*DS3231, error) {
+	return NewDS3231At(I2cDev, I2cAddrDS3231)
+}
+
+// NewDS3231At creates the RTC at address on bus.
+func NewDS3231At(bus string, address int) (*DS3231, error) {
+	dev, err := openI2C(bus, address)
 	if err != nil {
-		log.Default().Error("err: ", err)
-		return nil
+		return nil, err
 	}
-	dev.SetAddress(I2cAddrDS3231)
 	return &DS3231{
+		name: i2cName("ds3231", bus, address),
 		i2c:  dev,
 		time: "",
-	}
+	}, nil
+}
+
+// Name returns ds3231@ and the bus and address.
+func (d *DS3231) Name() string {
+	return d.name
+}
+
+// Init reads the seconds register to check the chip answers.
+func (d *DS3231) Init(ctx context.Context) error {
+	_, err := d.i2c.ReadByteData(0x00)
+	return d.fail(err)
+}
+
+// Close closes the bus, the RTC keeps running on its battery.
+func (d *DS3231) Close() error {
+	return d.i2c.Close()
 }
 
 func (d *DS3231) SetTime() error {
-	return d.i2c.WriteBlockData(0x00, timeNow)
+	return d.observe(d.i2c.WriteBlockData(0x00, timeNow))
 }
 
 func (d *DS3231) Time() (time string, err error) {
-	sec, err := d.i2c.ReadByteData(0x00)
-	sec = sec & 0x7f
-	min, err := d.i2c.ReadByteData(0x01)
-	min = min & 0x7f
-	hour, err := d.i2c.ReadByteData(0x02)
-	hour = hour & 0x3F
-	week, err := d.i2c.ReadByteData(0x03)
-	week = week & 0x07
-	day, err := d.i2c.ReadByteData(0x04)
-	day = day & 0x3F
-	month, err := d.i2c.ReadByteData(0x05)
-	month = month & 0x1F
-	year, err := d.i2c.ReadByteData(0x06)
+	regs, err := d.registers()
+	if err != nil {
+		return "", err
+	}
+	sec := regs[0] & 0x7f
+	min := regs[1] & 0x7f
+	hour := regs[2] & 0x3F
+	week := regs[3] & 0x07
+	day := regs[4] & 0x3F
+	month := regs[5] & 0x1F
+	year := regs[6]
 	d.time = fmt.Sprintf("20%x年 %x 月 %x日 %v  %x:%x:%x\n", year, month, day, weekArr[week-1], hour, min, sec)
 	time = d.time
 	return
 }
+
+// registers reads the seven time registers in one burst, which the DS3231
+// latches so they do not roll over between bytes.
+func (d *DS3231) registers() (regs [7]byte, err error) {
+	err = d.observe(d.i2c.ReadBlockData(0x00, regs[:]))
+	return
+}
+
+// Now reads the RTC as a time.Time in the local time zone, the DS3231 is
+// expected to run in 24 hour mode.
+func (d *DS3231) Now() (t time.Time, err error) {
+	regs, err := d.registers()
+	if err != nil {
+		return t, err
+	}
+	t = time.Date(
+		2000+bcdToInt(regs[6]),
+		time.Month(bcdToInt(regs[5]&0x1F)),
+		bcdToInt(regs[4]&0x3F),
+		bcdToInt(regs[2]&0x3F),
+		bcdToInt(regs[1]&0x7F),
+		bcdToInt(regs[0]&0x7F),
+		0, time.Local)
+	return
+}
+
+// SetTimeTo writes t to the RTC in 24 hour mode.
+func (d *DS3231) SetTimeTo(t time.Time) error {
+	return d.observe(d.i2c.WriteBlockData(0x00, []byte{
+		intToBCD(t.Second()),
+		intToBCD(t.Minute()),
+		intToBCD(t.Hour()),
+		byte(t.Weekday()) + 1,
+		intToBCD(t.Day()),
+		intToBCD(int(t.Month())),
+		intToBCD(t.Year() % 100),
+	}))
+}
+
+func intToBCD(n int) byte {
+	return byte(n/10)<<4 | byte(n%10)
+}
+
+func bcdToInt(b byte) int {
+	return int(b>>4)*10 + int(b&0x0F)
+}
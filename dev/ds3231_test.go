@@ -0,0 +1,49 @@
+package dev
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeDS3231 ticks one second on each register access, as a running clock
+// seen through separate reads.
+type fakeDS3231 struct {
+	now   time.Time
+	reads int
+}
+
+func (f *fakeDS3231) regs() []byte {
+	t := f.now
+	f.now = f.now.Add(time.Second)
+	return []byte{
+		intToBCD(t.Second()), intToBCD(t.Minute()), intToBCD(t.Hour()), byte(t.Weekday()) + 1,
+		intToBCD(t.Day()), intToBCD(int(t.Month())), intToBCD(t.Year() % 100),
+	}
+}
+
+func (f *fakeDS3231) ReadByteData(reg uint8) (uint8, error) {
+	f.reads++
+	return f.regs()[reg], nil
+}
+
+func (f *fakeDS3231) ReadBlockData(reg uint8, data []byte) error {
+	f.reads++
+	copy(data, f.regs()[reg:])
+	return nil
+}
+
+func (f *fakeDS3231) WriteBlockData(reg uint8, data []byte) error { return nil }
+func (f *fakeDS3231) Close() error                                { return nil }
+
+func TestDS3231Rollover(t *testing.T) {
+	want := time.Date(2024, time.December, 31, 23, 59, 59, 0, time.Local)
+	f := &fakeDS3231{now: want}
+	d := &DS3231{name: "ds3231", i2c: f}
+	got, err := d.Now()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) || f.reads != 1 {
+		t.Errorf("Now() = %v in %d reads, want %v in 1", got, f.reads, want)
+	}
+}
@@ -0,0 +1,278 @@
+package dev
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+const (
+	// EmulatorTerminal renders frames with Unicode half blocks on stdout.
+	EmulatorTerminal = "terminal"
+	// EmulatorPNG writes every frame as a numbered PNG file.
+	EmulatorPNG = "png"
+	// EmulatorGIF records every frame into an animated GIF written on Halt.
+	EmulatorGIF = "gif"
+)
+
+// EmulatorOpts is emulator configuration struct
+type EmulatorOpts struct {
+	Width  int
+	Height int
+	// Output is one of terminal, png or gif.
+	Output string
+	// Path is the frame directory for png and the file name for gif.
+	Path string
+}
+
+// Emulator is a software Display for developing without the board.
+type Emulator struct {
+	health
+	mu       sync.Mutex
+	opts     EmulatorOpts
+	out      io.Writer
+	frame    *image1bit.VerticalLSB
+	on       bool
+	contrast byte
+	count    int
+	anim     *gif.GIF
+	last     time.Time
+}
+
+// emulatorPalette mimics the white on black OLED.
+var emulatorPalette = color.Palette{color.Black, color.White}
+
+// NewEmulator creates a new Emulator.
+func NewEmulator(o *EmulatorOpts) (*Emulator, error) {
+	opts := *o
+	if opts.Width == 0 {
+		opts.Width = ssd1306Width
+	}
+	if opts.Height == 0 {
+		opts.Height = ssd1306Height
+	}
+	if opts.Output == "" {
+		opts.Output = EmulatorTerminal
+	}
+	e := &Emulator{
+		opts:     opts,
+		out:      os.Stdout,
+		frame:    image1bit.NewVerticalLSB(image.Rect(0, 0, opts.Width, opts.Height)),
+		on:       true,
+		contrast: 0xCF,
+	}
+	switch opts.Output {
+	case EmulatorTerminal:
+	case EmulatorPNG:
+		if opts.Path == "" {
+			e.opts.Path = "frames"
+		}
+		if err := os.MkdirAll(e.opts.Path, 0755); err != nil {
+			return nil, err
+		}
+	case EmulatorGIF:
+		if opts.Path == "" {
+			e.opts.Path = "display.gif"
+		}
+		e.anim = &gif.GIF{}
+	default:
+		return nil, fmt.Errorf("unknown emulator output %q", opts.Output)
+	}
+	return e, nil
+}
+
+// SetOutput redirects the terminal rendering to w.
+func (e *Emulator) SetOutput(w io.Writer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.out = w
+}
+
+// Bounds returns the panel size.
+func (e *Emulator) Bounds() image.Rectangle {
+	return e.frame.Rect
+}
+
+// Frame returns a copy of the last frame drawn.
+func (e *Emulator) Frame() *image1bit.VerticalLSB {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return copyFrame(e.frame)
+}
+
+// DrawFrame stores frame and renders it to the configured output.
+func (e *Emulator) DrawFrame(frame *image1bit.VerticalLSB) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.frame = image1bit.NewVerticalLSB(e.frame.Rect)
+	for y := e.frame.Rect.Min.Y; y < e.frame.Rect.Max.Y; y++ {
+		for x := e.frame.Rect.Min.X; x < e.frame.Rect.Max.X; x++ {
+			e.frame.SetBit(x, y, frame.BitAt(x, y))
+		}
+	}
+	return e.render()
+}
+
+// SetContrast sets the display contrast (0-255), low values render dimmed.
+func (e *Emulator) SetContrast(contrast byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.contrast = contrast
+	return e.render()
+}
+
+// On turns on the display.
+func (e *Emulator) On() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.on = true
+	return e.render()
+}
+
+// Off turns off the display.
+func (e *Emulator) Off() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.on = false
+	return e.render()
+}
+
+// Name returns emulator: and the output.
+func (e *Emulator) Name() string {
+	return "emulator:" + e.opts.Output
+}
+
+// Init does nothing, the emulator is always present.
+func (e *Emulator) Init(ctx context.Context) error {
+	return e.fail(nil)
+}
+
+// Close halts the display.
+func (e *Emulator) Close() error {
+	return e.Halt()
+}
+
+// Halt turns off the display and writes the GIF recording if any.
+func (e *Emulator) Halt() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.on = false
+	if err := e.render(); err != nil {
+		return err
+	}
+	if e.anim == nil || len(e.anim.Image) == 0 {
+		return nil
+	}
+	f, err := os.Create(e.opts.Path)
+	if err != nil {
+		return err
+	}
+	if err = gif.EncodeAll(f, e.anim); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// visible returns what the panel shows, blank while off.
+func (e *Emulator) visible() *image.Paletted {
+	img := image.NewPaletted(e.frame.Rect, emulatorPalette)
+	if !e.on {
+		return img
+	}
+	for y := e.frame.Rect.Min.Y; y < e.frame.Rect.Max.Y; y++ {
+		for x := e.frame.Rect.Min.X; x < e.frame.Rect.Max.X; x++ {
+			if e.frame.BitAt(x, y) {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	return img
+}
+
+func (e *Emulator) render() error {
+	img := e.visible()
+	switch e.opts.Output {
+	case EmulatorPNG:
+		e.count++
+		f, err := os.Create(filepath.Join(e.opts.Path, fmt.Sprintf("frame-%05d.png", e.count)))
+		if err != nil {
+			return err
+		}
+		if err = png.Encode(f, img); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	case EmulatorGIF:
+		now := time.Now()
+		if n := len(e.anim.Delay); n > 0 {
+			// GIF delays are in 100ths of a second.
+			e.anim.Delay[n-1] = int(now.Sub(e.last) / (10 * time.Millisecond))
+		}
+		e.last = now
+		e.anim.Image = append(e.anim.Image, img)
+		e.anim.Delay = append(e.anim.Delay, 0)
+		return nil
+	default:
+		return e.renderTerminal(img)
+	}
+}
+
+// renderTerminal draws two pixel rows per text line with half blocks.
+func (e *Emulator) renderTerminal(img *image.Paletted) error {
+	w := bufio.NewWriter(e.out)
+	r := img.Rect
+	// Move the cursor home so frames redraw in place.
+	fmt.Fprint(w, "\x1b[H")
+	if e.contrast < 0x80 {
+		fmt.Fprint(w, "\x1b[2m")
+	}
+	fmt.Fprint(w, "┌")
+	for x := r.Min.X; x < r.Max.X; x++ {
+		fmt.Fprint(w, "─")
+	}
+	fmt.Fprint(w, "┐\n")
+	for y := r.Min.Y; y < r.Max.Y; y += 2 {
+		fmt.Fprint(w, "│")
+		for x := r.Min.X; x < r.Max.X; x++ {
+			top := img.ColorIndexAt(x, y) == 1
+			bottom := y+1 < r.Max.Y && img.ColorIndexAt(x, y+1) == 1
+			switch {
+			case top && bottom:
+				fmt.Fprint(w, "█")
+			case top:
+				fmt.Fprint(w, "▀")
+			case bottom:
+				fmt.Fprint(w, "▄")
+			default:
+				fmt.Fprint(w, " ")
+			}
+		}
+		fmt.Fprint(w, "│\n")
+	}
+	fmt.Fprint(w, "└")
+	for x := r.Min.X; x < r.Max.X; x++ {
+		fmt.Fprint(w, "─")
+	}
+	fmt.Fprint(w, "┘\x1b[0m\n")
+	return w.Flush()
+}
+
+// copyFrame returns a deep copy of f.
+func copyFrame(f *image1bit.VerticalLSB) *image1bit.VerticalLSB {
+	c := *f
+	c.Pix = append([]byte(nil), f.Pix...)
+	return &c
+}
@@ -0,0 +1,119 @@
+package dev
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// Run "go test ./dev -update" to rewrite the golden images after an intended
+// rendering change, and review the PNGs before committing them.
+var update = flag.Bool("update", false, "update golden images in testdata/golden")
+
+const (
+	goldenDir = "testdata/golden"
+	failedDir = "testdata/failed"
+)
+
+var (
+	diffMissing = color.NRGBA{R: 0xff, A: 0xff} // on in golden, off in got
+	diffExtra   = color.NRGBA{G: 0xff, A: 0xff} // off in golden, on in got
+	diffSame    = color.NRGBA{R: 0x60, G: 0x60, B: 0x60, A: 0xff}
+)
+
+// checkGolden compares img with testdata/golden/<name>.png. On mismatch it
+// writes the rendering and a diff image to testdata/failed.
+func checkGolden(t *testing.T, name string, img *image1bit.VerticalLSB) {
+	t.Helper()
+	path := filepath.Join(goldenDir, name+".png")
+	if *update {
+		if err := writePNG(path, toGray(img)); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := readPNG(path)
+	if err != nil {
+		t.Fatalf("%v (run go test with -update to create it)", err)
+	}
+	if want.Bounds() != img.Bounds() {
+		t.Fatalf("%s: golden is %v, got %v", name, want.Bounds(), img.Bounds())
+	}
+	diff := image.NewNRGBA(img.Bounds())
+	bad := 0
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			w := isOn(want.At(x, y))
+			g := bool(img.BitAt(x, y))
+			switch {
+			case w && !g:
+				diff.Set(x, y, diffMissing)
+				bad++
+			case !w && g:
+				diff.Set(x, y, diffExtra)
+				bad++
+			case w:
+				diff.Set(x, y, diffSame)
+			}
+		}
+	}
+	if bad == 0 {
+		return
+	}
+	gotPath := filepath.Join(failedDir, name+".got.png")
+	diffPath := filepath.Join(failedDir, name+".diff.png")
+	if err := writePNG(gotPath, toGray(img)); err != nil {
+		t.Error(err)
+	}
+	if err := writePNG(diffPath, diff); err != nil {
+		t.Error(err)
+	}
+	t.Errorf("%s: %d pixels differ from %s, see %s", name, bad, path, diffPath)
+}
+
+func isOn(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return (r | g | b) >= 0x8000
+}
+
+func toGray(img *image1bit.VerticalLSB) *image.Gray {
+	g := image.NewGray(img.Bounds())
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			if img.BitAt(x, y) {
+				g.SetGray(x, y, color.Gray{Y: 0xff})
+			}
+		}
+	}
+	return g
+}
+
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
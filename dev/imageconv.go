@@ -0,0 +1,270 @@
+package dev
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// DitherMode selects how gray levels are reduced to the 1-bit panel.
+type DitherMode int
+
+const (
+	// DitherThreshold lights every pixel brighter than ConvertOptions.Threshold.
+	DitherThreshold DitherMode = iota
+	// DitherFloydSteinberg diffuses the quantization error to the neighbours,
+	// best for photos.
+	DitherFloydSteinberg
+	// DitherBayer uses an 8x8 ordered matrix, stable between animation frames.
+	DitherBayer
+)
+
+// ScaleMode selects how an image is fitted to the panel.
+type ScaleMode int
+
+const (
+	// ScaleFit keeps the aspect ratio and letterboxes the image.
+	ScaleFit ScaleMode = iota
+	// ScaleFill keeps the aspect ratio and covers the panel, cropping overflow.
+	ScaleFill
+	// ScaleCrop keeps the original size and crops around the center.
+	ScaleCrop
+	// ScaleStretch ignores the aspect ratio.
+	ScaleStretch
+)
+
+// ConvertOptions controls ConvertImage.
+type ConvertOptions struct {
+	Dither DitherMode
+	Scale  ScaleMode
+	// Threshold is the cutoff (1-255) for DitherThreshold. 0 means 128 so
+	// the zero ConvertOptions is usable, callers taking a cutoff from users
+	// reject 0.
+	Threshold uint8
+	// Gamma is applied as lum^(1/Gamma), values above 1 brighten midtones.
+	// 0 means 1.
+	Gamma float64
+	// Invert swaps on and off pixels inside the image area.
+	Invert bool
+}
+
+// DefaultConvertOptions is used when nil options are given.
+var DefaultConvertOptions = ConvertOptions{
+	Dither:    DitherThreshold,
+	Scale:     ScaleFit,
+	Threshold: 128,
+	Gamma:     1,
+}
+
+// MaxImageSide is the largest width and height DecodeImage accepts.
+const MaxImageSide = 4096
+
+// ErrImageTooLarge is an image over MaxImageSide.
+var ErrImageTooLarge = errors.New("image too large")
+
+// DecodeImage decodes data with the registered formats. The header is read
+// first so an image declaring more than MaxImageSide pixels on a side is
+// refused before its pixels are allocated.
+func DecodeImage(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width > MaxImageSide || cfg.Height > MaxImageSide {
+		return nil, fmt.Errorf("%dx%d over %dx%d: %w", cfg.Width, cfg.Height, MaxImageSide, MaxImageSide, ErrImageTooLarge)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// bayer8 is the classic 8x8 ordered dither matrix.
+var bayer8 = [8][8]uint8{
+	{0, 32, 8, 40, 2, 34, 10, 42},
+	{48, 16, 56, 24, 50, 18, 58, 26},
+	{12, 44, 4, 36, 14, 46, 6, 38},
+	{60, 28, 52, 20, 62, 30, 54, 22},
+	{3, 35, 11, 43, 1, 33, 9, 41},
+	{51, 19, 59, 27, 49, 17, 57, 25},
+	{15, 47, 7, 39, 13, 45, 5, 37},
+	{63, 31, 55, 23, 61, 29, 53, 21},
+}
+
+// ConvertImage scales src to bounds and reduces it to a 1-bit image.
+func ConvertImage(src image.Image, bounds image.Rectangle, opts *ConvertOptions) *image1bit.VerticalLSB {
+	if opts == nil {
+		opts = &DefaultConvertOptions
+	}
+	w, h := bounds.Dx(), bounds.Dy()
+	dst := image1bit.NewVerticalLSB(bounds)
+	if w <= 0 || h <= 0 || src.Bounds().Empty() {
+		return dst
+	}
+
+	size := scaledSize(src.Bounds().Size(), bounds.Size(), opts.Scale)
+	scaled := resize(src, size)
+	off := image.Point{X: (w - size.X) / 2, Y: (h - size.Y) / 2}
+
+	gamma := opts.Gamma
+	if gamma <= 0 {
+		gamma = 1
+	}
+	// gray holds luminance in [0,1], -1 marks pixels outside the image.
+	gray := make([]float64, w*h)
+	for i := range gray {
+		gray[i] = -1
+	}
+	for y := 0; y < size.Y; y++ {
+		dy := y + off.Y
+		if dy < 0 || dy >= h {
+			continue
+		}
+		for x := 0; x < size.X; x++ {
+			dx := x + off.X
+			if dx < 0 || dx >= w {
+				continue
+			}
+			l := luminance(scaled.NRGBAAt(x, y))
+			if gamma != 1 {
+				l = math.Pow(l, 1/gamma)
+			}
+			if opts.Invert {
+				l = 1 - l
+			}
+			gray[dy*w+dx] = l
+		}
+	}
+
+	threshold := float64(opts.Threshold)
+	if threshold == 0 {
+		threshold = 128
+	}
+	threshold /= 255
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			l := gray[y*w+x]
+			if l < 0 {
+				continue
+			}
+			on := false
+			switch opts.Dither {
+			case DitherFloydSteinberg:
+				on = l >= 0.5
+				q := 0.0
+				if on {
+					q = 1
+				}
+				diffuse(gray, w, h, x, y, l-q)
+			case DitherBayer:
+				on = l > (float64(bayer8[y&7][x&7])+0.5)/64
+			default:
+				on = l >= threshold
+			}
+			if on {
+				dst.SetBit(bounds.Min.X+x, bounds.Min.Y+y, image1bit.On)
+			}
+		}
+	}
+	return dst
+}
+
+// diffuse spreads the Floyd–Steinberg error of (x, y) to unvisited pixels.
+func diffuse(gray []float64, w, h, x, y int, e float64) {
+	add := func(x, y int, f float64) {
+		if x < 0 || x >= w || y >= h || gray[y*w+x] < 0 {
+			return
+		}
+		gray[y*w+x] += e * f
+	}
+	add(x+1, y, 7.0/16)
+	add(x-1, y+1, 3.0/16)
+	add(x, y+1, 5.0/16)
+	add(x+1, y+1, 1.0/16)
+}
+
+// luminance returns the Rec. 601 luma of a non premultiplied pixel composed
+// over black, in [0,1].
+func luminance(c color.NRGBA) float64 {
+	l := (0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)) / 255
+	return l * float64(c.A) / 255
+}
+
+// scaledSize returns the size src takes on a dst sized panel.
+func scaledSize(src, dst image.Point, mode ScaleMode) image.Point {
+	if src.X == 0 || src.Y == 0 {
+		return image.Point{}
+	}
+	switch mode {
+	case ScaleCrop:
+		return src
+	case ScaleStretch:
+		return dst
+	}
+	sx := float64(dst.X) / float64(src.X)
+	sy := float64(dst.Y) / float64(src.Y)
+	s := math.Min(sx, sy)
+	if mode == ScaleFill {
+		s = math.Max(sx, sy)
+	}
+	size := image.Point{
+		X: int(math.Round(float64(src.X) * s)),
+		Y: int(math.Round(float64(src.Y) * s)),
+	}
+	if size.X < 1 {
+		size.X = 1
+	}
+	if size.Y < 1 {
+		size.Y = 1
+	}
+	return size
+}
+
+// resize scales src to size, averaging the covered source pixels when
+// shrinking and picking the nearest one when enlarging.
+func resize(src image.Image, size image.Point) *image.NRGBA {
+	sb := src.Bounds()
+	dst := image.NewNRGBA(image.Rectangle{Max: size})
+	if sb.Empty() {
+		return dst
+	}
+	for y := 0; y < size.Y; y++ {
+		y0 := sb.Min.Y + y*sb.Dy()/size.Y
+		y1 := sb.Min.Y + (y+1)*sb.Dy()/size.Y
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < size.X; x++ {
+			x0 := sb.Min.X + x*sb.Dx()/size.X
+			x1 := sb.Min.X + (x+1)*sb.Dx()/size.X
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			if a == 0 {
+				continue
+			}
+			// Un-premultiply the averaged colour.
+			dst.Pix[i+0] = uint8(r * 0xff / a)
+			dst.Pix[i+1] = uint8(g * 0xff / a)
+			dst.Pix[i+2] = uint8(b * 0xff / a)
+			dst.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,130 @@
+package dev
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+func countOn(img *image1bit.VerticalLSB) int {
+	n := 0
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			if img.BitAt(x, y) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func uniform(w, h int, c color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestConvertImageThreshold(t *testing.T) {
+	bounds := image.Rect(0, 0, 128, 64)
+	dark := uniform(128, 64, color.Gray{Y: 100})
+	if n := countOn(ConvertImage(dark, bounds, nil)); n != 0 {
+		t.Fatalf("dark gray lit %d pixels, want 0", n)
+	}
+	opts := DefaultConvertOptions
+	opts.Threshold = 90
+	if n := countOn(ConvertImage(dark, bounds, &opts)); n != 128*64 {
+		t.Fatalf("threshold 90 lit %d pixels, want %d", n, 128*64)
+	}
+	opts = DefaultConvertOptions
+	opts.Invert = true
+	if n := countOn(ConvertImage(dark, bounds, &opts)); n != 128*64 {
+		t.Fatalf("inverted lit %d pixels, want %d", n, 128*64)
+	}
+}
+
+func TestConvertImageDither(t *testing.T) {
+	bounds := image.Rect(0, 0, 128, 64)
+	gray := uniform(128, 64, color.Gray{Y: 128})
+	for _, mode := range []DitherMode{DitherFloydSteinberg, DitherBayer} {
+		opts := DefaultConvertOptions
+		opts.Dither = mode
+		n := countOn(ConvertImage(gray, bounds, &opts))
+		if n < 128*64*45/100 || n > 128*64*55/100 {
+			t.Errorf("dither %d lit %d pixels, want about half", mode, n)
+		}
+	}
+}
+
+func TestConvertImageScale(t *testing.T) {
+	bounds := image.Rect(0, 0, 128, 64)
+	white := uniform(32, 32, color.White)
+	tests := []struct {
+		mode ScaleMode
+		want int
+	}{
+		{ScaleFit, 64 * 64},
+		{ScaleFill, 128 * 64},
+		{ScaleCrop, 32 * 32},
+		{ScaleStretch, 128 * 64},
+	}
+	for _, tt := range tests {
+		opts := DefaultConvertOptions
+		opts.Scale = tt.mode
+		img := ConvertImage(white, bounds, &opts)
+		if n := countOn(img); n != tt.want {
+			t.Errorf("scale %d lit %d pixels, want %d", tt.mode, n, tt.want)
+		}
+	}
+	// Fit letterboxes in the middle.
+	img := ConvertImage(white, bounds, nil)
+	if img.BitAt(0, 0) || !img.BitAt(32, 0) || !img.BitAt(95, 63) || img.BitAt(96, 63) {
+		t.Error("fit is not centered")
+	}
+}
+
+func TestConvertImageGamma(t *testing.T) {
+	bounds := image.Rect(0, 0, 8, 8)
+	dim := uniform(8, 8, color.Gray{Y: 80})
+	opts := DefaultConvertOptions
+	if n := countOn(ConvertImage(dim, bounds, &opts)); n != 0 {
+		t.Fatalf("lit %d pixels without gamma, want 0", n)
+	}
+	opts.Gamma = 2.2
+	if n := countOn(ConvertImage(dim, bounds, &opts)); n != 64 {
+		t.Fatalf("lit %d pixels with gamma 2.2, want 64", n)
+	}
+}
+
+// pngOfSize returns a 1x1 PNG whose header declares width x height.
+func pngOfSize(t *testing.T, width, height uint32) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	// The IHDR chunk follows the 8 byte signature: length, type, data, CRC.
+	binary.BigEndian.PutUint32(b[16:], width)
+	binary.BigEndian.PutUint32(b[20:], height)
+	binary.BigEndian.PutUint32(b[29:], crc32.ChecksumIEEE(b[12:29]))
+	return b
+}
+
+func TestDecodeImage(t *testing.T) {
+	if img, err := DecodeImage(pngOfSize(t, 1, 1)); err != nil || img.Bounds().Dx() != 1 {
+		t.Fatalf("DecodeImage(1x1) = %v, %v", img, err)
+	}
+	if _, err := DecodeImage(pngOfSize(t, 100000, 100000)); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("DecodeImage(100000x100000) = %v", err)
+	}
+}
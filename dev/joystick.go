@@ -0,0 +1,101 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"pi/driver"
+)
+
+// http://www.waveshare.net/wiki/Pioneer600
+// The joystick directions are wired to P0..P3 of the PCF8574, the center
+// press to the KEY on BCM 20. Both read low when pressed.
+const (
+	joystickLeft  byte = 0x01
+	joystickUp    byte = 0x02
+	joystickDown  byte = 0x04
+	joystickRight byte = 0x08
+	joystickMask  byte = 0x0F
+
+	pinJoystickKey int = 20
+)
+
+// JoystickState is a snapshot of the joystick buttons, true when pressed.
+type JoystickState struct {
+	Up    bool `json:"up"`
+	Down  bool `json:"down"`
+	Left  bool `json:"left"`
+	Right bool `json:"right"`
+	Press bool `json:"press"`
+}
+
+// Joystick reads the Pioneer600 five way joystick.
+type Joystick struct {
+	health
+	name string
+	port *pcf8574
+	key  *driver.DigitalPin
+}
+
+func NewJoystick() (*Joystick, error) {
+	return NewJoystickAt(I2cDev, I2cAddrPcf8574, pinJoystickKey)
+}
+
+// NewJoystickAt creates the joystick on the PCF8574 at address on bus with
+// the center press on the BCM key pin.
+func NewJoystickAt(bus string, address, key int) (*Joystick, error) {
+	port, err := openPCF8574(bus, address)
+	if err != nil {
+		return nil, err
+	}
+	return &Joystick{
+		name: fmt.Sprintf("%s+BCM%d", i2cName("joystick", bus, address), key),
+		port: port,
+		key:  driver.NewDigitalPin(key),
+	}, nil
+}
+
+// Name returns joystick@ and the bus, address and key pin.
+func (j *Joystick) Name() string {
+	return j.name
+}
+
+// Init exports the key pin as an input and reads the joystick once.
+func (j *Joystick) Init(ctx context.Context) error {
+	if err := j.key.Export(); err != nil {
+		return j.fail(err)
+	}
+	if err := j.key.Direction(driver.IN); err != nil {
+		return j.fail(err)
+	}
+	_, err := j.Read()
+	return j.fail(err)
+}
+
+// Close unexports the key pin and releases the PCF8574.
+func (j *Joystick) Close() error {
+	err := j.key.Unexport()
+	if cerr := j.port.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Read returns the current state of the joystick.
+func (j *Joystick) Read() (s JoystickState, err error) {
+	defer func() { err = j.observe(err) }()
+	v, err := j.port.Read()
+	if err != nil {
+		return
+	}
+	v = ^v & joystickMask
+	s.Left = v&joystickLeft != 0
+	s.Up = v&joystickUp != 0
+	s.Down = v&joystickDown != 0
+	s.Right = v&joystickRight != 0
+	key, err := j.key.Read()
+	if err != nil {
+		return
+	}
+	s.Press = key == driver.LOW
+	return
+}
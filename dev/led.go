@@ -1,8 +1,11 @@
 package dev
 
 import (
+	"context"
+	"fmt"
 	"pi/driver"
 	"pi/log"
+	"sync"
 )
 
 const (
@@ -13,34 +16,63 @@ const (
 )
 
 type LEDOne struct {
-	pin    *driver.DigitalPin
+	health
+	pinNo int
+	pin   *driver.DigitalPin
+
+	// mu guards status, the device worker and the commands switch the LED.
+	mu     sync.Mutex
 	status int
 }
 
 func NewLEDOne() *LEDOne {
+	return NewLEDOneAt(pinLedOne)
+}
+
+// NewLEDOneAt creates the LED on the BCM pin, wired active low.
+func NewLEDOneAt(pin int) *LEDOne {
 	return &LEDOne{
-		pin:    driver.NewDigitalPin(pinLedOne),
+		pinNo:  pin,
+		pin:    driver.NewDigitalPin(pin),
 		status: statusOFFLedOne,
 	}
 }
 
-func (led *LEDOne) Init() error {
+// Name returns led@BCM and the pin number.
+func (led *LEDOne) Name() string {
+	return fmt.Sprintf("led@BCM%d", led.pinNo)
+}
+
+// Init exports the pin as an output.
+func (led *LEDOne) Init(ctx context.Context) error {
 	log.Default().Info("Init LED1 here.!")
 	err := led.pin.Export()
 	if err != nil {
-		return err
+		return led.fail(err)
 	}
 	err = led.pin.Direction(driver.OUT)
 	if err != nil {
-		return err
+		return led.fail(err)
 	}
-	return nil
+	return led.fail(nil)
 }
 
-//低电平亮，高电平暗
+// 低电平亮，高电平暗
 func (led *LEDOne) On() error {
+	led.mu.Lock()
+	defer led.mu.Unlock()
+	return led.on()
+}
+
+func (led *LEDOne) Off() error {
+	led.mu.Lock()
+	defer led.mu.Unlock()
+	return led.off()
+}
+
+func (led *LEDOne) on() error {
 	log.Default().Info("Switch LED to On.!")
-	err := led.pin.Write(driver.LOW)
+	err := led.observe(led.pin.Write(driver.LOW))
 	if err != nil {
 		return err
 	}
@@ -48,9 +80,9 @@ func (led *LEDOne) On() error {
 	return nil
 }
 
-func (led *LEDOne) Off() error {
+func (led *LEDOne) off() error {
 	log.Default().Info("Switch LED to Off.!")
-	err := led.pin.Write(driver.HIGH)
+	err := led.observe(led.pin.Write(driver.HIGH))
 	if err != nil {
 		return err
 	}
@@ -59,13 +91,25 @@ func (led *LEDOne) Off() error {
 }
 
 func (led *LEDOne) Status() int {
+	led.mu.Lock()
+	defer led.mu.Unlock()
 	return led.status
 }
 
 func (led *LEDOne) Toggle() error {
+	led.mu.Lock()
+	defer led.mu.Unlock()
 	if led.status == statusOFFLedOne {
-		return led.On()
+		return led.on()
 	} else {
-		return led.Off()
+		return led.off()
+	}
+}
+
+// Close turns the LED off and unexports its pin.
+func (led *LEDOne) Close() error {
+	if err := led.Off(); err != nil {
+		return err
 	}
+	return led.pin.Unexport()
 }
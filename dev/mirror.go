@@ -0,0 +1,178 @@
+package dev
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"sync"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// Mirror wraps a Display to keep what the panel shows, the controllers are
+// write only so this is the only way to take a screenshot.
+type Mirror struct {
+	Display
+	mu    sync.Mutex
+	frame *image1bit.VerticalLSB
+	on    bool
+	subs  map[chan *image1bit.VerticalLSB]struct{}
+}
+
+// NewMirror creates a new Mirror of d, starting blank.
+func NewMirror(d Display) *Mirror {
+	return &Mirror{
+		Display: d,
+		frame:   image1bit.NewVerticalLSB(d.Bounds()),
+		on:      true,
+		subs:    make(map[chan *image1bit.VerticalLSB]struct{}),
+	}
+}
+
+// DrawFrame draws frame on the display and sends it to the subscribers.
+func (m *Mirror) DrawFrame(frame *image1bit.VerticalLSB) error {
+	m.mu.Lock()
+	m.frame = copyFrame(frame)
+	m.mu.Unlock()
+	m.publish()
+	return m.Display.DrawFrame(frame)
+}
+
+// On turns on the display.
+func (m *Mirror) On() error {
+	m.setOn(true)
+	return m.Display.On()
+}
+
+// Off turns off the display, snapshots are blank until On.
+func (m *Mirror) Off() error {
+	m.setOn(false)
+	return m.Display.Off()
+}
+
+// Halt turns off the display and releases it.
+func (m *Mirror) Halt() error {
+	m.setOn(false)
+	return m.Display.Halt()
+}
+
+// Name returns the Name of the display driver.
+func (m *Mirror) Name() string {
+	if d, ok := m.Display.(Device); ok {
+		return d.Name()
+	}
+	return "mirror"
+}
+
+// Init initializes the display driver.
+func (m *Mirror) Init(ctx context.Context) error {
+	if d, ok := m.Display.(Device); ok {
+		return d.Init(ctx)
+	}
+	return nil
+}
+
+// Close closes the display driver, snapshots are blank from now on.
+func (m *Mirror) Close() error {
+	m.setOn(false)
+	if d, ok := m.Display.(Device); ok {
+		return d.Close()
+	}
+	return m.Display.Halt()
+}
+
+// Health returns the Health of the display driver.
+func (m *Mirror) Health() Health {
+	if d, ok := m.Display.(Device); ok {
+		return d.Health()
+	}
+	return Health{State: HealthUnknown}
+}
+
+// Snapshot returns a copy of what the panel shows.
+func (m *Mirror) Snapshot() *image1bit.VerticalLSB {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.visible()
+}
+
+// Subscribe returns a channel receiving the current frame then each new
+// one, slow readers only get the latest. cancel closes the channel.
+func (m *Mirror) Subscribe() (frames <-chan *image1bit.VerticalLSB, cancel func()) {
+	ch := make(chan *image1bit.VerticalLSB, 1)
+	m.mu.Lock()
+	m.subs[ch] = struct{}{}
+	ch <- m.visible()
+	m.mu.Unlock()
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			m.mu.Lock()
+			delete(m.subs, ch)
+			m.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (m *Mirror) setOn(on bool) {
+	m.mu.Lock()
+	m.on = on
+	m.mu.Unlock()
+	m.publish()
+}
+
+func (m *Mirror) publish() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.subs) == 0 {
+		return
+	}
+	f := m.visible()
+	for ch := range m.subs {
+		// Replace a frame the reader has not taken yet.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- f
+	}
+}
+
+// visible returns a copy of the frame, blank while off.
+func (m *Mirror) visible() *image1bit.VerticalLSB {
+	if !m.on {
+		return image1bit.NewVerticalLSB(m.frame.Rect)
+	}
+	return copyFrame(m.frame)
+}
+
+// FrameImage returns frame as a gray image enlarged scale times, lit pixels
+// are white.
+func FrameImage(frame *image1bit.VerticalLSB, scale int) *image.Gray {
+	if scale < 1 {
+		scale = 1
+	}
+	r := frame.Bounds()
+	img := image.NewGray(image.Rect(0, 0, r.Dx()*scale, r.Dy()*scale))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if !frame.BitAt(x, y) {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetGray((x-r.Min.X)*scale+dx, (y-r.Min.Y)*scale+dy, color.Gray{Y: 0xff})
+				}
+			}
+		}
+	}
+	return img
+}
+
+// EncodePNG writes frame as a PNG enlarged scale times.
+func EncodePNG(w io.Writer, frame *image1bit.VerticalLSB, scale int) error {
+	return png.Encode(w, FrameImage(frame, scale))
+}
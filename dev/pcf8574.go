@@ -0,0 +1,106 @@
+package dev
+
+import "sync"
+
+// http://www.waveshare.net/wiki/Pioneer600
+// The PCF8574 at 0x20 drives LED2 on P4 and the buzzer on P7, both active
+// low, and reads the joystick on P0..P3. Pins written high are inputs.
+const (
+	pcf8574LED2   byte = 0x10
+	pcf8574Buzzer byte = 0x80
+)
+
+// byteIO is the byte access of driver.I2CDevice.
+type byteIO interface {
+	ReadByte() (byte, error)
+	WriteByte(val byte) error
+	Close() error
+}
+
+// pcf8574 is a PCF8574 shared by the devices wired to its pins. It keeps
+// the last port written so each device changes its own bits only.
+type pcf8574 struct {
+	name string
+	i2c  byteIO
+	refs int
+
+	mu   sync.Mutex
+	port byte
+}
+
+// expanders are the open PCF8574s by name.
+var expanders = struct {
+	sync.Mutex
+	m map[string]*pcf8574
+}{m: make(map[string]*pcf8574)}
+
+// openPCF8574 returns the PCF8574 at address on bus, opened once for all
+// the devices using it until the last one closes it.
+func openPCF8574(bus string, address int) (*pcf8574, error) {
+	name := i2cName("pcf8574", bus, address)
+	expanders.Lock()
+	defer expanders.Unlock()
+	if p, ok := expanders.m[name]; ok {
+		p.refs++
+		return p, nil
+	}
+	i2c, err := openI2C(bus, address)
+	if err != nil {
+		return nil, err
+	}
+	p := newPCF8574(i2c, name)
+	expanders.m[name] = p
+	return p, nil
+}
+
+// newPCF8574 creates the PCF8574 name on i2c, all pins high.
+func newPCF8574(i2c byteIO, name string) *pcf8574 {
+	return &pcf8574{name: name, i2c: i2c, refs: 1, port: 0xFF}
+}
+
+// Close closes the bus once every device using p closed it.
+func (p *pcf8574) Close() error {
+	expanders.Lock()
+	defer expanders.Unlock()
+	if p.refs--; p.refs > 0 {
+		return nil
+	}
+	if expanders.m[p.name] == p {
+		delete(expanders.m, p.name)
+	}
+	return p.i2c.Close()
+}
+
+// Read returns the level of the pins.
+func (p *pcf8574) Read() (byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.i2c.ReadByte()
+}
+
+// Write sets the whole port.
+func (p *pcf8574) Write(port byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.i2c.WriteByte(port); err != nil {
+		return err
+	}
+	p.port = port
+	return nil
+}
+
+// Set drives the pins of mask low, or high when low is false, leaving the
+// other pins as last written.
+func (p *pcf8574) Set(mask byte, low bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	port := p.port | mask
+	if low {
+		port &^= mask
+	}
+	if err := p.i2c.WriteByte(port); err != nil {
+		return err
+	}
+	p.port = port
+	return nil
+}
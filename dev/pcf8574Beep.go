@@ -1,8 +1,9 @@
 package dev
 
 import (
-	"pi/driver"
+	"context"
 	"pi/log"
+	"sync"
 	"time"
 )
 
@@ -137,30 +138,70 @@ const (
 )
 
 type PCF8574Beep struct {
-	i2c        *driver.I2CDevice
+	health
+	name string
+	port *pcf8574
+	// mu guards beepStatus, set from the device worker and the commands.
+	mu         sync.Mutex
 	beepStatus int
 	BPM        float64
 }
 
-func NewPCF8574Beep() *PCF8574Beep {
-	dev, err := driver.NewI2cDevice(I2cDev)
+func NewPCF8574Beep() (*PCF8574Beep, error) {
+	return NewPCF8574BeepAt(I2cDev, I2cAddrPcf8574)
+}
+
+// NewPCF8574BeepAt creates the buzzer of the PCF8574 at address on bus.
+func NewPCF8574BeepAt(bus string, address int) (*PCF8574Beep, error) {
+	port, err := openPCF8574(bus, address)
 	if err != nil {
-		log.Default().Error("err: ", err)
-		return nil
+		return nil, err
 	}
-	dev.SetAddress(I2cAddrPcf8574)
 	return &PCF8574Beep{
-		i2c:        dev,
+		name:       port.name,
+		port:       port,
 		beepStatus: StatusOffBeep,
 		BPM:        96.0,
+	}, nil
+}
+
+// Name returns pcf8574@ and the bus and address.
+func (l *PCF8574Beep) Name() string {
+	return l.name
+}
+
+// Init reads the port to check the chip answers.
+func (l *PCF8574Beep) Init(ctx context.Context) error {
+	_, err := l.port.Read()
+	return l.fail(err)
+}
+
+// Close turns the buzzer off and releases the PCF8574.
+func (l *PCF8574Beep) Close() error {
+	if err := l.Off(); err != nil {
+		l.port.Close()
+		return err
 	}
+	return l.port.Close()
 }
 
 // On sets the buzzer to a high state.
-func (l *PCF8574Beep) On() (err error) {
+func (l *PCF8574Beep) On() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.on()
+}
 
+// Off sets the buzzer to a low state.
+func (l *PCF8574Beep) Off() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.off()
+}
+
+func (l *PCF8574Beep) on() (err error) {
 	log.Default().Info("Beep On ...")
-	err = l.i2c.WriteByte(I2cBeepOn)
+	err = l.observe(l.port.Set(pcf8574Buzzer, true))
 	if err != nil {
 		return
 	}
@@ -168,10 +209,9 @@ func (l *PCF8574Beep) On() (err error) {
 	return
 }
 
-// Off sets the buzzer to a low state.
-func (l *PCF8574Beep) Off() (err error) {
-	log.Default().Info("Beep On ...")
-	err = l.i2c.WriteByte(I2cBeepOff)
+func (l *PCF8574Beep) off() (err error) {
+	log.Default().Info("Beep Off ...")
+	err = l.observe(l.port.Set(pcf8574Buzzer, false))
 	if err != nil {
 		return
 	}
@@ -182,14 +222,48 @@ func (l *PCF8574Beep) Off() (err error) {
 // Toggle sets the buzzer to the opposite of it's current state
 func (l *PCF8574Beep) Toggle() (err error) {
 	log.Default().Info("Beep Toggle ...")
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	if l.beepStatus == StatusOffBeep {
-		err = l.On()
+		err = l.on()
 	} else {
-		err = l.Off()
+		err = l.off()
 	}
 	return
 }
 
+// Play sounds hz for d, or stays silent when hz is Rest, until ctx is
+// done. Unlike Tone it does not log each period.
+func (l *PCF8574Beep) Play(ctx context.Context, hz float64, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	if hz <= Rest {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+	half := time.NewTicker(time.Duration(float64(time.Second) / (2 * hz)))
+	defer half.Stop()
+	on := true
+	for {
+		if err := l.observe(l.port.Set(pcf8574Buzzer, on)); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			l.port.Set(pcf8574Buzzer, false)
+			return ctx.Err()
+		case <-timer.C:
+			return l.observe(l.port.Set(pcf8574Buzzer, false))
+		case <-half.C:
+		}
+		on = !on
+	}
+}
+
 func (l *PCF8574Beep) Tone(hz, duration float64) (err error) {
 	// calculation based off https://www.arduino.cc/en/Tutorial/Melody
 	tone := (1.0 / (2.0 * hz)) * 1000000.0
@@ -1,8 +1,9 @@
 package dev
 
 import (
-	"pi/driver"
+	"context"
 	"pi/log"
+	"sync"
 )
 
 const (
@@ -19,26 +20,68 @@ const (
 )
 
 type PCF8574LED struct {
-	i2c          *driver.I2CDevice
+	health
+	name string
+	port *pcf8574
+
+	// mu guards ledTwoStatus, the device worker and the commands switch
+	// LED2.
+	mu           sync.Mutex
 	ledTwoStatus int
 }
 
-func NewPCF8574LED() *PCF8574LED {
-	dev, err := driver.NewI2cDevice(I2cDev)
+func NewPCF8574LED() (*PCF8574LED, error) {
+	return NewPCF8574LEDAt(I2cDev, I2cAddrPcf8574)
+}
+
+// NewPCF8574LEDAt creates the LED2 of the PCF8574 at address on bus.
+func NewPCF8574LEDAt(bus string, address int) (*PCF8574LED, error) {
+	port, err := openPCF8574(bus, address)
 	if err != nil {
-		log.Default().Infof("err: %v", err)
-		return nil
+		return nil, err
 	}
-	dev.SetAddress(I2cAddrPcf8574)
 	return &PCF8574LED{
-		i2c:          dev,
+		name:         port.name,
+		port:         port,
 		ledTwoStatus: StatusOffLedTwo,
+	}, nil
+}
+
+// Name returns pcf8574@ and the bus and address.
+func (p *PCF8574LED) Name() string {
+	return p.name
+}
+
+// Init reads the port to check the chip answers.
+func (p *PCF8574LED) Init(ctx context.Context) error {
+	_, err := p.port.Read()
+	return p.fail(err)
+}
+
+// Close turns LED2 off and releases the PCF8574.
+func (p *PCF8574LED) Close() error {
+	if err := p.LED2Off(); err != nil {
+		p.port.Close()
+		return err
 	}
+	return p.port.Close()
 }
 
 func (p *PCF8574LED) LED2On() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.led2On()
+}
+
+func (p *PCF8574LED) LED2Off() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.led2Off()
+}
+
+func (p *PCF8574LED) led2On() error {
 	log.Default().Info("LED2 On ...")
-	err := p.i2c.WriteByte(I2cLedTwoOn)
+	err := p.observe(p.port.Set(pcf8574LED2, true))
 	if err != nil {
 		return err
 	}
@@ -46,9 +89,9 @@ func (p *PCF8574LED) LED2On() error {
 	return nil
 }
 
-func (p *PCF8574LED) LED2Off() error {
+func (p *PCF8574LED) led2Off() error {
 	log.Default().Info("LED2 Off ...")
-	err := p.i2c.WriteByte(I2cLedTwoOff)
+	err := p.observe(p.port.Set(pcf8574LED2, false))
 	if err != nil {
 		return err
 	}
@@ -56,11 +99,42 @@ func (p *PCF8574LED) LED2Off() error {
 	return nil
 }
 
+// On turns LED2 on.
+func (p *PCF8574LED) On() error {
+	return p.LED2On()
+}
+
+// Off turns LED2 off.
+func (p *PCF8574LED) Off() error {
+	return p.LED2Off()
+}
+
+// Status returns StatusOnLedTwo or StatusOffLedTwo.
+func (p *PCF8574LED) Status() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.ledTwoStatus
+}
+
+// Read returns the PCF8574 port, LED2 is on P4 and the buzzer on P7, both
+// active low.
+func (p *PCF8574LED) Read() (byte, error) {
+	b, err := p.port.Read()
+	return b, p.observe(err)
+}
+
+// Write sets the PCF8574 port, bits set high are inputs.
+func (p *PCF8574LED) Write(port byte) error {
+	return p.observe(p.port.Write(port))
+}
+
 func (p *PCF8574LED) Toggle() error {
 	log.Default().Info("LED2 Toggle ...")
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	if p.ledTwoStatus == StatusOffLedTwo {
-		return p.LED2On()
+		return p.led2On()
 	} else {
-		return p.LED2Off()
+		return p.led2Off()
 	}
 }
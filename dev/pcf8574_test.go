@@ -0,0 +1,60 @@
+package dev
+
+import "testing"
+
+// fakePort records the bytes written to a PCF8574.
+type fakePort struct {
+	writes []byte
+	closed bool
+}
+
+func (f *fakePort) ReadByte() (byte, error) {
+	return f.writes[len(f.writes)-1], nil
+}
+
+func (f *fakePort) WriteByte(val byte) error {
+	f.writes = append(f.writes, val)
+	return nil
+}
+
+func (f *fakePort) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestPCF8574Shared(t *testing.T) {
+	f := &fakePort{}
+	p := newPCF8574(f, "pcf8574@test:0x20")
+	p.refs = 2
+	led := &PCF8574LED{port: p}
+	beep := &PCF8574Beep{port: p}
+
+	steps := []struct {
+		do   func() error
+		want byte
+	}{
+		{led.On, 0xEF},
+		{beep.On, 0x6F},
+		{led.Off, 0x7F},
+		{led.Toggle, 0x6F},
+		{beep.Off, 0xEF},
+	}
+	for i, s := range steps {
+		if err := s.do(); err != nil {
+			t.Fatal(err)
+		}
+		if got := f.writes[len(f.writes)-1]; got != s.want {
+			t.Errorf("step %d wrote %#02x, want %#02x", i, got, s.want)
+		}
+	}
+	if got, _ := p.Read(); got&0x0F != 0x0F {
+		t.Errorf("joystick inputs driven low: %#02x", got)
+	}
+
+	if err := p.Close(); err != nil || f.closed {
+		t.Errorf("Close() of a shared PCF8574 = %v, closed %v", err, f.closed)
+	}
+	if err := p.Close(); err != nil || !f.closed {
+		t.Errorf("last Close() = %v, closed %v", err, f.closed)
+	}
+}
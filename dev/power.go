@@ -0,0 +1,275 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"pi/log"
+	"sync"
+	"time"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// TimeSource gives the wall clock time, implemented by DS3231.
+type TimeSource interface {
+	Now() (time.Time, error)
+}
+
+// SystemClock is a TimeSource using the system clock.
+type SystemClock struct{}
+
+// Now returns time.Now().
+func (SystemClock) Now() (time.Time, error) {
+	return time.Now(), nil
+}
+
+// ContrastProfile sets Contrast between From and To ("HH:MM", To may be past
+// midnight).
+type ContrastProfile struct {
+	From     string
+	To       string
+	Contrast byte
+}
+
+// PowerOpts is display power management configuration struct
+type PowerOpts struct {
+	// IdleTimeout turns the panel off without input, 0 never sleeps.
+	IdleTimeout time.Duration
+	// ShiftInterval moves the whole frame by one pixel, 0 disables it.
+	ShiftInterval time.Duration
+	// ShiftRange is the maximum offset in pixels, 1 when 0.
+	ShiftRange int
+	// Contrast is used outside of the profiles, 0 leaves it untouched.
+	Contrast byte
+	Profiles []ContrastProfile
+}
+
+// shiftOrbit is the order the frame visits the offsets around the center.
+var shiftOrbit = []image.Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+
+// PowerManager wraps a Display to sleep it when idle, follow a time of day
+// contrast schedule and shift the frame periodically against burn-in.
+type PowerManager struct {
+	mu       sync.Mutex
+	display  Display
+	opts     PowerOpts
+	clock    TimeSource
+	frame    *image1bit.VerticalLSB
+	asleep   bool
+	lastWake time.Time
+	lastMove time.Time
+	orbit    int
+	contrast int
+}
+
+// NewPowerManager creates a new PowerManager, clock gives the time of day
+// for the contrast profiles (SystemClock if nil).
+func NewPowerManager(d Display, o *PowerOpts, clock TimeSource) (*PowerManager, error) {
+	for _, p := range o.Profiles {
+		if _, err := parseClock(p.From); err != nil {
+			return nil, err
+		}
+		if _, err := parseClock(p.To); err != nil {
+			return nil, err
+		}
+	}
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	now := time.Now()
+	return &PowerManager{
+		display:  d,
+		opts:     *o,
+		clock:    clock,
+		frame:    image1bit.NewVerticalLSB(d.Bounds()),
+		lastWake: now,
+		lastMove: now,
+		contrast: -1,
+	}, nil
+}
+
+// Bounds returns the panel size.
+func (p *PowerManager) Bounds() image.Rectangle {
+	return p.display.Bounds()
+}
+
+// DrawFrame keeps frame and shows it shifted unless the panel sleeps.
+func (p *PowerManager) DrawFrame(frame *image1bit.VerticalLSB) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.frame = copyFrame(frame)
+	if p.asleep {
+		return nil
+	}
+	return p.display.DrawFrame(p.shifted())
+}
+
+// SetContrast sets the contrast until the next profile change.
+func (p *PowerManager) SetContrast(contrast byte) error {
+	return p.display.SetContrast(contrast)
+}
+
+// On turns on the display.
+func (p *PowerManager) On() error {
+	return p.display.On()
+}
+
+// Off turns off the display.
+func (p *PowerManager) Off() error {
+	return p.display.Off()
+}
+
+// Halt turns off the display and releases it.
+func (p *PowerManager) Halt() error {
+	return p.display.Halt()
+}
+
+// Asleep reports whether the panel was turned off for inactivity.
+func (p *PowerManager) Asleep() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.asleep
+}
+
+// Offset returns the current burn-in shift.
+func (p *PowerManager) Offset() image.Point {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.offset()
+}
+
+// Wake records user activity and turns the panel back on. It returns true
+// when the panel was asleep, so the waking input can be ignored.
+func (p *PowerManager) Wake() (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastWake = time.Now()
+	if !p.asleep {
+		return false, nil
+	}
+	p.asleep = false
+	log.Default().Info("display wakes up")
+	if err := p.display.On(); err != nil {
+		return true, err
+	}
+	return true, p.display.DrawFrame(p.shifted())
+}
+
+// Run applies the power policy every second until ctx is done.
+func (p *PowerManager) Run(ctx context.Context) error {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+	for {
+		if err := p.Tick(time.Now()); err != nil {
+			log.Default().Error("display power error: ", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Tick applies the idle timeout, the contrast profile and the pixel shift
+// for the monotonic time now.
+func (p *PowerManager) Tick(now time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.opts.IdleTimeout > 0 && !p.asleep && now.Sub(p.lastWake) >= p.opts.IdleTimeout {
+		p.asleep = true
+		log.Default().Info("display goes to sleep")
+		if err := p.display.Off(); err != nil {
+			return err
+		}
+	}
+	if p.asleep {
+		return nil
+	}
+	if err := p.applyContrast(); err != nil {
+		return err
+	}
+	if p.opts.ShiftInterval > 0 && now.Sub(p.lastMove) >= p.opts.ShiftInterval {
+		p.lastMove = now
+		p.orbit = (p.orbit + 1) % len(shiftOrbit)
+		return p.display.DrawFrame(p.shifted())
+	}
+	return nil
+}
+
+// applyContrast sets the contrast of the profile matching the clock.
+func (p *PowerManager) applyContrast() error {
+	if len(p.opts.Profiles) == 0 && p.opts.Contrast == 0 {
+		return nil
+	}
+	t, err := p.clock.Now()
+	if err != nil {
+		return err
+	}
+	want := int(p.opts.Contrast)
+	if c, ok := profileContrast(p.opts.Profiles, t); ok {
+		want = int(c)
+	}
+	if want == 0 || want == p.contrast {
+		return nil
+	}
+	if err = p.display.SetContrast(byte(want)); err != nil {
+		return err
+	}
+	p.contrast = want
+	return nil
+}
+
+func (p *PowerManager) offset() image.Point {
+	r := p.opts.ShiftRange
+	if r <= 0 {
+		r = 1
+	}
+	return shiftOrbit[p.orbit].Mul(r)
+}
+
+// shifted returns the kept frame moved by the current offset.
+func (p *PowerManager) shifted() *image1bit.VerticalLSB {
+	off := p.offset()
+	if off == (image.Point{}) {
+		return p.frame
+	}
+	out := image1bit.NewVerticalLSB(p.frame.Rect)
+	r := p.frame.Rect
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if p.frame.BitAt(x, y) {
+				if q := (image.Point{X: x, Y: y}).Add(off); q.In(r) {
+					out.SetBit(q.X, q.Y, image1bit.On)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// profileContrast returns the contrast of the first profile containing t.
+func profileContrast(profiles []ContrastProfile, t time.Time) (byte, bool) {
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	for _, p := range profiles {
+		from, _ := parseClock(p.From)
+		to, _ := parseClock(p.To)
+		if from <= to && now >= from && now < to {
+			return p.Contrast, true
+		}
+		if from > to && (now >= from || now < to) {
+			return p.Contrast, true
+		}
+	}
+	return 0, false
+}
+
+// parseClock parses "HH:MM" into the time since midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, want HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
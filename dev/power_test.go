@@ -0,0 +1,131 @@
+package dev
+
+import (
+	"bytes"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// powerDisplay records the power related calls.
+type powerDisplay struct {
+	slowDisplay
+	on       bool
+	contrast byte
+}
+
+func (d *powerDisplay) SetContrast(c byte) error { d.contrast = c; return nil }
+func (d *powerDisplay) On() error                { d.on = true; return nil }
+func (d *powerDisplay) Off() error               { d.on = false; return nil }
+
+type fixedClock time.Time
+
+func (c *fixedClock) Now() (time.Time, error) { return time.Time(*c), nil }
+
+func TestPowerManagerSleep(t *testing.T) {
+	d := &powerDisplay{on: true}
+	pm, err := NewPowerManager(d, &PowerOpts{IdleTimeout: time.Minute}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frame := renderText(panel, PosTopLeft, "hello")
+	pm.DrawFrame(frame)
+	start := time.Now()
+	pm.Tick(start.Add(30 * time.Second))
+	if pm.Asleep() || !d.on {
+		t.Fatal("asleep before the idle timeout")
+	}
+	pm.Tick(start.Add(2 * time.Minute))
+	if !pm.Asleep() || d.on {
+		t.Fatal("awake after the idle timeout")
+	}
+	pm.DrawFrame(renderText(panel, PosTopLeft, "while asleep"))
+	if len(d.frames) != 1 {
+		t.Fatal("frame drawn while asleep")
+	}
+	woke, err := pm.Wake()
+	if err != nil || !woke || !d.on {
+		t.Fatalf("wake = %v, %v", woke, err)
+	}
+	if len(d.frames) != 2 || countOn(d.frames[1]) != countOn(renderText(panel, PosTopLeft, "while asleep")) {
+		t.Fatal("last frame not redrawn on wake")
+	}
+	if woke, _ = pm.Wake(); woke {
+		t.Fatal("second wake reported asleep")
+	}
+}
+
+func TestPowerManagerShift(t *testing.T) {
+	d := &powerDisplay{on: true}
+	pm, _ := NewPowerManager(d, &PowerOpts{ShiftInterval: time.Minute, ShiftRange: 2}, nil)
+	frame := image1bit.NewVerticalLSB(panel)
+	frame.SetBit(10, 10, image1bit.On)
+	pm.DrawFrame(frame)
+	pm.Tick(time.Now().Add(2 * time.Minute))
+	if pm.Offset() != image.Pt(2, 0) {
+		t.Fatalf("offset = %v", pm.Offset())
+	}
+	last := d.frames[len(d.frames)-1]
+	if !last.BitAt(12, 10) || last.BitAt(10, 10) {
+		t.Fatal("frame not shifted")
+	}
+	if !frame.BitAt(10, 10) {
+		t.Fatal("caller frame modified")
+	}
+}
+
+func TestPowerManagerContrast(t *testing.T) {
+	d := &powerDisplay{on: true}
+	clock := fixedClock(time.Date(2020, 6, 1, 23, 30, 0, 0, time.UTC))
+	opts := &PowerOpts{
+		Contrast: 200,
+		Profiles: []ContrastProfile{{From: "22:00", To: "07:00", Contrast: 10}, {From: "12:00", To: "13:00", Contrast: 255}},
+	}
+	pm, err := NewPowerManager(d, opts, &clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		hour int
+		want byte
+	}{{23, 10}, {3, 10}, {7, 200}, {12, 255}, {13, 200}} {
+		clock = fixedClock(time.Date(2020, 6, 1, tt.hour, 0, 0, 0, time.UTC))
+		pm.Tick(time.Now())
+		if d.contrast != tt.want {
+			t.Errorf("%02d:00 contrast = %d, want %d", tt.hour, d.contrast, tt.want)
+		}
+	}
+	if _, err = NewPowerManager(d, &PowerOpts{Profiles: []ContrastProfile{{From: "25:00", To: "07:00"}}}, nil); err == nil {
+		t.Error("invalid profile accepted")
+	}
+}
+
+func TestNewDisplayOpts(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	err := v.ReadConfig(bytes.NewBufferString(`
+display:
+  driver: emulator
+  power:
+    idleTimeout: "10m"
+    contrast: 207
+    profiles:
+      - from: "22:00"
+        to: "07:00"
+        contrast: 16
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := NewDisplayOpts(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Driver != DisplayEmulator || o.Power.IdleTimeout != 10*time.Minute || o.Power.Contrast != 207 ||
+		len(o.Power.Profiles) != 1 || o.Power.Profiles[0].Contrast != 16 {
+		t.Fatalf("opts = %+v", o)
+	}
+}
@@ -0,0 +1,455 @@
+package dev
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Device types of the devices section.
+const (
+	TypeLED        = "led"
+	TypePCF8574LED = "pcf8574-led"
+	TypeBuzzer     = "buzzer"
+	TypeDS18B20    = "ds18b20"
+	TypeDS3231     = "ds3231"
+	TypeBMP180     = "bmp180"
+	TypeJoystick   = "joystick"
+	TypeSSD1306    = "ssd1306"
+)
+
+// Kinds of the device options.
+const (
+	OptionInt    = "int"
+	OptionFloat  = "float"
+	OptionString = "string"
+)
+
+// DeviceConfig is a device declared in the devices section, eg.
+//
+//	name: temp
+//	type: ds18b20
+//	interval: "2s"
+//	options:
+//	  sensor: "28-00000a1b2c3d"
+type DeviceConfig struct {
+	// Name identifies the device in the logs and the events.
+	Name string
+	Type string
+	// Bus is the bus device, eg. /dev/i2c-1 or /dev/spidev0.0.
+	Bus     string
+	Address int
+	// Pin is a BCM pin number.
+	Pin      int
+	Interval time.Duration
+	Options  map[string]interface{}
+}
+
+// String returns the string option name.
+func (c *DeviceConfig) String(name string) string {
+	s, _ := c.Options[name].(string)
+	return s
+}
+
+// Int returns the int option name.
+func (c *DeviceConfig) Int(name string) int {
+	n, _ := c.Options[name].(int)
+	return n
+}
+
+// Float returns the float option name, ints are converted.
+func (c *DeviceConfig) Float(name string) float64 {
+	switch v := c.Options[name].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	}
+	return 0
+}
+
+// DeviceType describes how to check and create a type of device.
+type DeviceType struct {
+	// Buses are the accepted bus device prefixes, the first is the
+	// default bus. Empty when not on a bus.
+	Buses []string
+	// Address is the default I2C address, 0 when not addressed.
+	Address int
+	// Pin is the default BCM pin, 0 when not on a pin.
+	Pin int
+	// Interval is the default polling interval, 0 when not polled.
+	Interval time.Duration
+	// Options are the option kinds by name.
+	Options map[string]string
+	// New creates the device from a checked configuration, without
+	// initializing it.
+	New func(c *DeviceConfig) (Device, error)
+}
+
+// Registry creates the devices of the configuration by type.
+type Registry struct {
+	types map[string]DeviceType
+}
+
+// NewRegistry creates a new Registry knowing the Pioneer600 devices.
+func NewRegistry() *Registry {
+	r := &Registry{types: make(map[string]DeviceType)}
+	i2c := []string{I2cDev, "/dev/i2c-"}
+	r.Register(TypeLED, DeviceType{
+		Pin:      pinLedOne,
+		Interval: time.Second,
+		New: func(c *DeviceConfig) (Device, error) {
+			return NewLEDOneAt(c.Pin), nil
+		},
+	})
+	r.Register(TypePCF8574LED, DeviceType{
+		Buses:   i2c,
+		Address: I2cAddrPcf8574,
+		New: func(c *DeviceConfig) (Device, error) {
+			return NewPCF8574LEDAt(c.Bus, c.Address)
+		},
+	})
+	r.Register(TypeBuzzer, DeviceType{
+		Buses:   i2c,
+		Address: I2cAddrPcf8574,
+		Options: map[string]string{"bpm": OptionFloat},
+		New: func(c *DeviceConfig) (Device, error) {
+			b, err := NewPCF8574BeepAt(c.Bus, c.Address)
+			if err != nil {
+				return nil, err
+			}
+			if c.Float("bpm") > 0 {
+				b.BPM = c.Float("bpm")
+			}
+			return b, nil
+		},
+	})
+	r.Register(TypeDS18B20, DeviceType{
+		Interval: 2 * time.Second,
+		Options:  map[string]string{"sensor": OptionString},
+		New: func(c *DeviceConfig) (Device, error) {
+			return NewDS18B20Sensor(c.String("sensor")), nil
+		},
+	})
+	r.Register(TypeDS3231, DeviceType{
+		Buses:    i2c,
+		Address:  I2cAddrDS3231,
+		Interval: time.Minute,
+		New: func(c *DeviceConfig) (Device, error) {
+			return NewDS3231At(c.Bus, c.Address)
+		},
+	})
+	r.Register(TypeBMP180, DeviceType{
+		Buses:    i2c,
+		Address:  I2cAddrBMP180,
+		Interval: 10 * time.Second,
+		Options:  map[string]string{"oversampling": OptionInt},
+		New: func(c *DeviceConfig) (Device, error) {
+			b, err := NewBMP180At(c.Bus, c.Address)
+			if err != nil {
+				return nil, err
+			}
+			b.Oversampling = c.Int("oversampling")
+			return b, nil
+		},
+	})
+	r.Register(TypeJoystick, DeviceType{
+		Buses:    i2c,
+		Address:  I2cAddrPcf8574,
+		Pin:      pinJoystickKey,
+		Interval: 50 * time.Millisecond,
+		New: func(c *DeviceConfig) (Device, error) {
+			return NewJoystickAt(c.Bus, c.Address, c.Pin)
+		},
+	})
+	r.Register(TypeSSD1306, DeviceType{
+		Buses:   []string{"/dev/spidev0.0", "/dev/spidev", "/dev/i2c-"},
+		Address: I2cAddrSSD1306,
+		Options: map[string]string{
+			"driver":     OptionString,
+			"controller": OptionString,
+			"dc":         OptionInt,
+			"rst":        OptionInt,
+			"speed":      OptionInt,
+		},
+		New: func(c *DeviceConfig) (Device, error) {
+			d, err := newDisplay(DisplayOptsOf(c, &DisplayOpts{}))
+			if err != nil {
+				return nil, err
+			}
+			return d.(Device), nil
+		},
+	})
+	return r
+}
+
+// DisplayOptsOf returns base updated with the bus, address and options of
+// the ssd1306 device c.
+func DisplayOptsOf(c *DeviceConfig, base *DisplayOpts) *DisplayOpts {
+	o := *base
+	if d := c.String("driver"); d != "" {
+		o.Driver = d
+	}
+	if o.Driver == "" {
+		o.Driver = DisplaySSD1306H
+	}
+	if ctrl := c.String("controller"); ctrl != "" {
+		o.Controller = ctrl
+	}
+	if c.Bus != "" {
+		o.Device = c.Bus
+		o.Bus = BusSPI
+		if strings.HasPrefix(c.Bus, "/dev/i2c-") {
+			o.Bus = BusI2C
+		}
+	}
+	if c.Address != 0 {
+		o.Address = c.Address
+	}
+	if n := c.Int("dc"); n != 0 {
+		o.DCPin = n
+	}
+	if n := c.Int("rst"); n != 0 {
+		o.RSTPin = n
+	}
+	if n := c.Int("speed"); n != 0 {
+		o.Speed = int64(n)
+	}
+	return &o
+}
+
+// Register adds or replaces the device type name.
+func (r *Registry) Register(name string, t DeviceType) {
+	r.types[name] = t
+}
+
+// Types returns the registered type names, sorted.
+func (r *Registry) Types() []string {
+	names := make([]string, 0, len(r.types))
+	for name := range r.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks the configurations and fills in the defaults of their
+// type. All the errors are reported, one per line.
+func (r *Registry) Validate(configs []DeviceConfig) error {
+	var errs []string
+	names := make(map[string]bool)
+	displays := 0
+	for i := range configs {
+		c := &configs[i]
+		fail := func(format string, a ...interface{}) {
+			errs = append(errs, fmt.Sprintf("devices[%d] (%s): ", i, c.Name)+fmt.Sprintf(format, a...))
+		}
+		if c.Name == "" {
+			fail("missing name")
+		} else if names[c.Name] {
+			fail("duplicate name")
+		}
+		names[c.Name] = true
+		t, ok := r.types[c.Type]
+		if !ok {
+			fail("unknown type %q, want one of %s", c.Type, strings.Join(r.Types(), ", "))
+			continue
+		}
+		if len(t.Buses) == 0 && c.Bus != "" {
+			fail("type %s is not on a bus", c.Type)
+		}
+		if len(t.Buses) > 0 {
+			if c.Bus == "" {
+				c.Bus = t.Buses[0]
+			}
+			if !hasPrefix(c.Bus, t.Buses) {
+				fail("invalid bus %q for type %s", c.Bus, c.Type)
+			}
+		}
+		if t.Address == 0 && c.Address != 0 {
+			fail("type %s has no address", c.Type)
+		}
+		if t.Address != 0 {
+			if c.Address == 0 {
+				c.Address = t.Address
+			}
+			if c.Address < 0x03 || c.Address > 0x77 {
+				fail("address %#x out of the 0x03-0x77 range", c.Address)
+			}
+		}
+		if t.Pin == 0 && c.Pin != 0 {
+			fail("type %s has no pin", c.Type)
+		}
+		if t.Pin != 0 {
+			if c.Pin == 0 {
+				c.Pin = t.Pin
+			}
+			if c.Pin < 1 || c.Pin > 27 {
+				fail("pin %d out of the BCM 1-27 range", c.Pin)
+			}
+		}
+		if c.Type == TypeSSD1306 {
+			// The remote view and the menu mirror a single display.
+			if displays++; displays > 1 {
+				fail("more than one %s", TypeSSD1306)
+			}
+		}
+		if c.Interval < 0 {
+			fail("negative interval %v", c.Interval)
+		}
+		if c.Interval == 0 {
+			c.Interval = t.Interval
+		}
+		for _, name := range sortedKeys(c.Options) {
+			kind, ok := t.Options[name]
+			if !ok {
+				fail("unknown option %q for type %s", name, c.Type)
+				continue
+			}
+			if !isKind(c.Options[name], kind) {
+				fail("option %q must be a %s, got %v", name, kind, c.Options[name])
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New("invalid devices configuration:\n  " + strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// Open creates and initializes the device of the validated configuration
+// c. The device is closed when Init fails.
+func (r *Registry) Open(ctx context.Context, c *DeviceConfig) (Device, error) {
+	t, ok := r.types[c.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown device type %q", c.Type)
+	}
+	d, err := t.New(c)
+	if err != nil {
+		return nil, fmt.Errorf("open %s (%s): %v", c.Name, c.Type, err)
+	}
+	if err = d.Init(ctx); err != nil {
+		d.Close()
+		return nil, fmt.Errorf("init %s (%s): %v", c.Name, c.Type, err)
+	}
+	return d, nil
+}
+
+// LoadDevices reads and validates the devices section of the configuration.
+func LoadDevices(v *viper.Viper, r *Registry) ([]DeviceConfig, error) {
+	var configs []DeviceConfig
+	if err := v.UnmarshalKey("devices", &configs); err != nil {
+		return nil, err
+	}
+	return configs, r.Validate(configs)
+}
+
+// Kinds of DeviceChange.
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeInterval = "interval"
+	ChangeUpdated  = "updated"
+)
+
+// DeviceChange is a difference between two devices sections. Old is nil
+// for an added device, New for a removed one.
+type DeviceChange struct {
+	Kind string
+	Old  *DeviceConfig
+	New  *DeviceConfig
+}
+
+func (c DeviceChange) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s (%s) added", c.New.Name, c.New.Type)
+	case ChangeRemoved:
+		return fmt.Sprintf("%s (%s) removed", c.Old.Name, c.Old.Type)
+	}
+	var fields []string
+	diff := func(name string, old, new interface{}) {
+		if !reflect.DeepEqual(old, new) {
+			fields = append(fields, fmt.Sprintf("%s %v -> %v", name, old, new))
+		}
+	}
+	diff("type", c.Old.Type, c.New.Type)
+	diff("bus", c.Old.Bus, c.New.Bus)
+	diff("address", c.Old.Address, c.New.Address)
+	diff("pin", c.Old.Pin, c.New.Pin)
+	diff("interval", c.Old.Interval, c.New.Interval)
+	diff("options", c.Old.Options, c.New.Options)
+	return fmt.Sprintf("%s %s: %s", c.New.Name, c.Kind, strings.Join(fields, ", "))
+}
+
+// DiffDevices returns the changes from the validated configurations old to
+// new, in the order of new then the removed devices. A device whose only
+// change is its interval is a ChangeInterval, it does not need reopening.
+func DiffDevices(old, new []DeviceConfig) []DeviceChange {
+	var changes []DeviceChange
+	byName := make(map[string]*DeviceConfig)
+	for i := range old {
+		byName[old[i].Name] = &old[i]
+	}
+	for i := range new {
+		n := &new[i]
+		o, ok := byName[n.Name]
+		delete(byName, n.Name)
+		switch {
+		case !ok:
+			changes = append(changes, DeviceChange{Kind: ChangeAdded, New: n})
+		case reflect.DeepEqual(o, n):
+		case o.Interval != n.Interval && reflect.DeepEqual(*o, withInterval(*n, o.Interval)):
+			changes = append(changes, DeviceChange{Kind: ChangeInterval, Old: o, New: n})
+		default:
+			changes = append(changes, DeviceChange{Kind: ChangeUpdated, Old: o, New: n})
+		}
+	}
+	for i := range old {
+		if o, ok := byName[old[i].Name]; ok {
+			changes = append(changes, DeviceChange{Kind: ChangeRemoved, Old: o})
+		}
+	}
+	return changes
+}
+
+func withInterval(c DeviceConfig, d time.Duration) DeviceConfig {
+	c.Interval = d
+	return c
+}
+
+func hasPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func isKind(v interface{}, kind string) bool {
+	switch v.(type) {
+	case int:
+		return kind == OptionInt || kind == OptionFloat
+	case float64:
+		return kind == OptionFloat
+	case string:
+		return kind == OptionString
+	}
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
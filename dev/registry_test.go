@@ -0,0 +1,149 @@
+package dev
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func loadDevices(t *testing.T, yaml string) ([]DeviceConfig, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewBufferString(yaml)); err != nil {
+		t.Fatal(err)
+	}
+	return LoadDevices(v, NewRegistry())
+}
+
+func TestLoadDevices(t *testing.T) {
+	configs, err := loadDevices(t, `
+devices:
+  - name: led1
+    type: led
+  - name: temp
+    type: ds18b20
+    interval: "5s"
+    options:
+      sensor: "28-00000a1b2c3d"
+  - name: rtc
+    type: ds3231
+    bus: /dev/i2c-3
+  - name: oled
+    type: ssd1306
+    bus: /dev/i2c-1
+    address: 0x3d
+    options:
+      controller: sh1106
+      speed: 8000000
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	led, temp, rtc, oled := configs[0], configs[1], configs[2], configs[3]
+	if led.Pin != 26 || led.Bus != "" || led.Address != 0 {
+		t.Errorf("led defaults = %+v", led)
+	}
+	if temp.Interval != 5*time.Second || temp.String("sensor") != "28-00000a1b2c3d" {
+		t.Errorf("temp = %+v", temp)
+	}
+	if rtc.Bus != "/dev/i2c-3" || rtc.Address != I2cAddrDS3231 || rtc.Interval != time.Minute {
+		t.Errorf("rtc defaults = %+v", rtc)
+	}
+	o := DisplayOptsOf(&oled, &DisplayOpts{Power: PowerOpts{Contrast: 10}})
+	if o.Bus != BusI2C || o.Device != "/dev/i2c-1" || o.Address != 0x3D || o.Controller != ControllerSH1106 ||
+		o.Speed != 8000000 || o.Driver != DisplaySSD1306H || o.Power.Contrast != 10 {
+		t.Errorf("display opts = %+v", o)
+	}
+
+	if _, err := NewRegistry().Open(context.Background(), &temp); err == nil || !strings.HasPrefix(err.Error(), "init temp (ds18b20)") {
+		t.Errorf("missing sensor opened: %v", err)
+	}
+	oled.Options["driver"] = DisplayEmulator
+	d, err := NewRegistry().Open(context.Background(), &oled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name() != "emulator:terminal" || d.Health().State != HealthPresent {
+		t.Fatalf("opened %s: %+v", d.Name(), d.Health())
+	}
+}
+
+func TestValidateDevices(t *testing.T) {
+	_, err := loadDevices(t, `
+devices:
+  - name: led1
+    type: led
+    pin: 40
+  - name: led1
+    type: ds18b20
+    address: 0x20
+  - type: lcd
+  - name: rtc
+    type: ds3231
+    bus: /dev/spidev0.0
+    address: 0x80
+  - name: buzzer
+    type: buzzer
+    options:
+      bpm: fast
+      volume: 3
+  - name: oled
+    type: ssd1306
+  - name: oled2
+    type: ssd1306
+`)
+	if err == nil {
+		t.Fatal("invalid configuration accepted")
+	}
+	for _, want := range []string{
+		"devices[0] (led1): pin 40 out of the BCM 1-27 range",
+		"devices[1] (led1): duplicate name",
+		"devices[1] (led1): type ds18b20 has no address",
+		"devices[2] (): missing name",
+		`devices[2] (): unknown type "lcd"`,
+		`devices[3] (rtc): invalid bus "/dev/spidev0.0" for type ds3231`,
+		"devices[3] (rtc): address 0x80 out of the 0x03-0x77 range",
+		`devices[4] (buzzer): option "bpm" must be a float, got fast`,
+		`devices[4] (buzzer): unknown option "volume" for type buzzer`,
+		"devices[6] (oled2): more than one ssd1306",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error lacks %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestDiffDevices(t *testing.T) {
+	old := []DeviceConfig{
+		{Name: "led1", Type: TypeLED, Pin: 26, Interval: time.Second},
+		{Name: "temp", Type: TypeDS18B20, Interval: 2 * time.Second},
+		{Name: "rtc", Type: TypeDS3231, Bus: I2cDev, Address: 0x68, Interval: time.Minute},
+		{Name: "buzzer", Type: TypeBuzzer, Bus: I2cDev, Address: 0x20},
+	}
+	new := []DeviceConfig{
+		{Name: "led1", Type: TypeLED, Pin: 26, Interval: time.Second},
+		{Name: "temp", Type: TypeDS18B20, Interval: 5 * time.Second},
+		{Name: "rtc", Type: TypeDS3231, Bus: "/dev/i2c-3", Address: 0x68, Interval: 30 * time.Second},
+		{Name: "led2", Type: TypePCF8574LED, Bus: I2cDev, Address: 0x20},
+	}
+	var got []string
+	for _, c := range DiffDevices(old, new) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"temp interval: interval 2s -> 5s",
+		"rtc updated: bus /dev/i2c-1 -> /dev/i2c-3, interval 1m0s -> 30s",
+		"led2 (pcf8574-led) added",
+		"buzzer (buzzer) removed",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if c := DiffDevices(new, new); len(c) != 0 {
+		t.Errorf("changes of an unchanged section: %v", c)
+	}
+}
@@ -0,0 +1,41 @@
+package dev
+
+import (
+	"pi/driver"
+)
+
+const (
+	// I2cAddrPCF8591 is the ADC of the Pioneer600.
+	I2cAddrPCF8591 int = 0x48
+	// I2cAddrBMP180 is the pressure sensor of the Pioneer600.
+	I2cAddrBMP180 int = 0x77
+)
+
+// I2CNames are the Pioneer600 chips by I2C address.
+var I2CNames = map[int]string{
+	I2cAddrPcf8574: "PCF8574 expander (LED2, buzzer, joystick)",
+	I2cAddrSSD1306: "SSD1306 OLED",
+	I2cAddrPCF8591: "PCF8591 ADC",
+	I2cAddrDS3231:  "DS3231 RTC",
+	I2cAddrBMP180:  "BMP180 pressure sensor",
+}
+
+// ScanI2C probes the addresses 0x03-0x77 on bus with a byte read, like
+// i2cdetect -r, and returns the ones answering.
+func ScanI2C(bus string) ([]int, error) {
+	dev, err := driver.NewI2cDevice(bus)
+	if err != nil {
+		return nil, err
+	}
+	defer dev.Close()
+	var found []int
+	for addr := 0x03; addr <= 0x77; addr++ {
+		if err = dev.SetAddress(addr); err != nil {
+			continue
+		}
+		if _, err = dev.ReadByte(); err == nil {
+			found = append(found, addr)
+		}
+	}
+	return found, nil
+}
@@ -1,11 +1,14 @@
 package dev
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"image"
+	"io"
 	"pi/driver"
 	"time"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
 )
 
 //https://github.com/google/periph
@@ -22,6 +25,15 @@ const (
 	ssd1306DcPin  = 16 // for raspberry pi
 	ssd1306Width  = 128
 	ssd1306Height = 64
+	// I2cAddrSSD1306 is the OLED address with the I2C jumpers set (0x3D with
+	// D/C high).
+	I2cAddrSSD1306 = 0x3C
+	// I2C control bytes, Co bit clear: the rest of the write is commands or
+	// display RAM.
+	ssd1306I2CCommand = 0x00
+	ssd1306I2CData    = 0x40
+	// i2cMaxBlock is the WriteBlockData limit.
+	i2cMaxBlock = 32
 
 	ssd1306ExternalVcc  = false
 	ssd1306SetStartLine = 0x40
@@ -60,6 +72,24 @@ const (
 	ssd1306NOOP                 = 0xE3
 	// charge pump command
 	ssd1306ChargePumpSetting = 0x8D
+
+	// SH1106 specific commands
+	sh1106SetPageAddr    = 0xB0
+	sh1106SetLowColumn   = 0x00
+	sh1106SetHighColumn  = 0x10
+	sh1106SetDCDC        = 0xAD
+	sh1106DCDCOn         = 0x8B
+	sh1106SetPumpVoltage = 0x32
+	sh1106ColumnOffset   = 2
+)
+
+const (
+	// ControllerSSD1306 is the original controller (default).
+	ControllerSSD1306 = "ssd1306"
+	// ControllerSH1106 has a 132 column RAM and only supports page mode.
+	ControllerSH1106 = "sh1106"
+	// ControllerSSD1309 is found on 2.42" modules, it has no charge pump.
+	ControllerSSD1309 = "ssd1309"
 )
 
 // DisplayBuffer represents the display buffer intermediate memory
@@ -105,53 +135,247 @@ func (d *DisplayBuffer) Set(buf []byte) {
 	d.buffer = buf
 }
 
+// ssd1306Bus frames commands and display RAM writes on SPI or I2C.
+type ssd1306Bus interface {
+	// commands sends a command with its arguments.
+	commands(b ...byte) error
+	// data sends display RAM content.
+	data(b []byte) error
+	// close releases the bus.
+	close() error
+}
+
+// spiBus tells commands from data with the D/C pin.
+type spiBus struct {
+	connection driver.Connection
+	dcDriver   driver.DigitalPinner
+}
+
+func (b *spiBus) commands(c ...byte) (err error) {
+	if err = b.dcDriver.Write(driver.LOW); err != nil {
+		return err
+	}
+	return b.connection.Tx(c, nil)
+}
+
+func (b *spiBus) data(d []byte) (err error) {
+	if err = b.dcDriver.Write(driver.HIGH); err != nil {
+		return err
+	}
+	return b.connection.Tx(d, nil)
+}
+
+func (b *spiBus) close() error {
+	return b.connection.Close()
+}
+
+// blockWriter is implemented by driver.I2CDevice.
+type blockWriter interface {
+	WriteBlockData(reg uint8, data []byte) error
+}
+
+// i2cBus prefixes each write with a control byte, split in blocks the
+// adapter accepts.
+type i2cBus struct {
+	dev blockWriter
+}
+
+func (b *i2cBus) commands(c ...byte) error {
+	return b.write(ssd1306I2CCommand, c)
+}
+
+func (b *i2cBus) data(d []byte) error {
+	return b.write(ssd1306I2CData, d)
+}
+
+func (b *i2cBus) close() error {
+	if c, ok := b.dev.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (b *i2cBus) write(control byte, p []byte) error {
+	for len(p) > 0 {
+		n := len(p)
+		if n > i2cMaxBlock {
+			n = i2cMaxBlock
+		}
+		if err := b.dev.WriteBlockData(control, p[:n]); err != nil {
+			return err
+		}
+		p = p[n:]
+	}
+	return nil
+}
+
 type SSD1306 struct {
-	connection    driver.Connection
+	health
+	bus           ssd1306Bus
 	name          string
-	dcDriver      *driver.DigitalPin
-	rstDriver     *driver.DigitalPin
+	lastErr       error
+	rstDriver     driver.DigitalPinner
 	pageSize      int
 	DisplayWidth  int
 	DisplayHeight int
 	DCPin         int
 	RSTPin        int
 	ExternalVcc   bool
-	buffer        *DisplayBuffer
+	// Controller is one of ControllerSSD1306, ControllerSH1106 or
+	// ControllerSSD1309.
+	Controller string
+	// ColumnOffset is the first RAM column shown, 2 on SH1106 modules.
+	ColumnOffset int
+	buffer       *DisplayBuffer
 }
 
-// NewSSD1306 creates a new NewSSD1306.
-func NewSSD1306() *SSD1306 {
+// NewSSD1306 creates a new driver for the SSD1306 on the default SPI bus.
+func NewSSD1306() (*SSD1306, error) {
+	return NewSSD1306Controller(ControllerSSD1306)
+}
+
+// NewSSD1306Controller creates a new driver for an SSD1306 compatible
+// controller on the default SPI bus.
+func NewSSD1306Controller(controller string) (*SSD1306, error) {
+	return openSSD1306(&DisplayOpts{Controller: controller})
+}
+
+// NewSSD1306I2C creates a new driver for an SSD1306 compatible controller
+// at address on the I2C bus.
+func NewSSD1306I2C(controller string, address int) (*SSD1306, error) {
+	return openSSD1306(&DisplayOpts{Controller: controller, Bus: BusI2C, Address: address})
+}
+
+// openSSD1306 creates the built-in driver for o, zero fields take the
+// Pioneer600 wiring. The pins and the bus are released when it fails.
+func openSSD1306(o *DisplayOpts) (s *SSD1306, err error) {
+	rstPin, dcPin, speed := ssd1306RstPin, ssd1306DcPin, int64(spiDefaultMaxSpeed)
+	if o.RSTPin != 0 {
+		rstPin = o.RSTPin
+	}
+	if o.DCPin != 0 {
+		dcPin = o.DCPin
+	}
+	if o.Speed != 0 {
+		speed = o.Speed
+	}
+	rst := driver.NewDigitalPin(rstPin)
+	rst.Export()
+	defer func() {
+		if err != nil {
+			rst.Unexport()
+		}
+	}()
+	rst.Direction(driver.OUT)
+	if o.Bus == BusI2C {
+		location := o.Device
+		if location == "" {
+			location = I2cDev
+		}
+		var dev *driver.I2CDevice
+		if dev, err = driver.NewI2cDevice(location); err != nil {
+			return nil, err
+		}
+		address := o.Address
+		if address == 0 {
+			address = I2cAddrSSD1306
+		}
+		if err = dev.SetAddress(address); err == nil {
+			s, err = newSSD1306(&i2cBus{dev: dev}, rst, o.Controller)
+		}
+		if err != nil {
+			dev.Close()
+			return nil, err
+		}
+		s.RSTPin = rstPin
+		s.name = i2cName(s.Controller, location, address)
+		return s, nil
+	}
+	bus, chip := spiDefaultBus, spiDefaultChip
+	if o.Device != "" {
+		if _, err := fmt.Sscanf(o.Device, "/dev/spidev%d.%d", &bus, &chip); err != nil {
+			return nil, fmt.Errorf("invalid SPI device %q", o.Device)
+		}
+	}
 	// cast adaptor to spi connector since we also need the adaptor for gpio
 	c, err := driver.GetSpiConnection(
-		spiDefaultBus,
-		spiDefaultChip,
+		bus,
+		chip,
 		spiDefaultMode,
 		spiDefaultBits,
-		spiDefaultMaxSpeed)
+		speed)
 	if err != nil {
-		panic("unable to get connector for ssd1306")
+		return nil, err
+	}
+	dc := driver.NewDigitalPin(dcPin)
+	dc.Export()
+	dc.Direction(driver.OUT)
+	if s, err = newSSD1306(&spiBus{connection: c, dcDriver: dc}, rst, o.Controller); err != nil {
+		dc.Unexport()
+		c.Close()
+		return nil, err
+	}
+	s.DCPin, s.RSTPin = dcPin, rstPin
+	s.name = fmt.Sprintf("%s@/dev/spidev%d.%d", s.Controller, bus, chip)
+	return s, nil
+}
+
+func newSSD1306(bus ssd1306Bus, rst driver.DigitalPinner, controller string) (*SSD1306, error) {
+	if controller == "" {
+		controller = ControllerSSD1306
+	}
+	switch controller {
+	case ControllerSSD1306, ControllerSH1106, ControllerSSD1309:
+	default:
+		return nil, fmt.Errorf("unknown display controller %q", controller)
 	}
-	fmt.Println("SpiConnection = ", c)
 	s := &SSD1306{
-		name:          "SSD1306",
-		connection:    c,
+		name:          controller,
+		bus:           bus,
+		rstDriver:     rst,
 		DisplayWidth:  ssd1306Width,
 		DisplayHeight: ssd1306Height,
 		DCPin:         ssd1306DcPin,
 		RSTPin:        ssd1306RstPin,
 		ExternalVcc:   ssd1306ExternalVcc,
+		Controller:    controller,
+	}
+	if controller == ControllerSH1106 {
+		s.ColumnOffset = sh1106ColumnOffset
 	}
-	s.dcDriver = driver.NewDigitalPin(s.DCPin)
-	s.dcDriver.Export()
-	s.dcDriver.Direction(driver.OUT)
-	s.rstDriver = driver.NewDigitalPin(s.RSTPin)
-	s.rstDriver.Export()
-	s.rstDriver.Direction(driver.OUT)
 	s.pageSize = s.DisplayHeight / 8
-	s.buffer = NewDisplayBuffer(s.DisplayWidth, s.DisplayHeight, s.pageSize)
+	s.buffer = NewDisplayBuffer(s.DisplayWidth, s.DisplayHeight, 8)
+	return s, nil
+}
+
+// Name returns the controller and the bus, eg. sh1106@/dev/i2c-1:0x3c.
+func (s *SSD1306) Name() string {
+	return s.name
+}
+
+// Init resets the panel and sends the init sequence of the controller.
+func (s *SSD1306) Init(ctx context.Context) error {
+	s.lastErr = nil
 	s.Reset()
-	s.ssd1306Init()
-	return s
+	switch s.Controller {
+	case ControllerSSD1306:
+		s.ssd1306Init()
+	case ControllerSH1106:
+		s.sh1106Init()
+	case ControllerSSD1309:
+		s.ssd1309Init()
+	}
+	return s.fail(s.lastErr)
+}
+
+// Close turns the panel off and releases the bus and the pins.
+func (s *SSD1306) Close() error {
+	s.Halt()
+	if dc, ok := s.bus.(*spiBus); ok {
+		dc.dcDriver.Unexport()
+	}
+	s.rstDriver.Unexport()
+	return s.bus.close()
 }
 
 func (s *SSD1306) ssd1306Init() {
@@ -209,6 +433,47 @@ func (s *SSD1306) ssd1306Init() {
 	s.command(ssd1306SetDisplayOn)
 }
 
+// sh1106Init has no memory addressing mode nor 0x8D charge pump, the DC-DC
+// converter is enabled with 0xAD.
+func (s *SSD1306) sh1106Init() {
+	s.commands(ssd1306SetDisplayOff)
+	s.commands(ssd1306SetDisplayClock, 0x80)
+	s.commands(ssd1306SetMultiplexRatio, uint8(s.DisplayHeight)-1)
+	s.commands(ssd1306SetDisplayOffset, 0x00)
+	s.commands(ssd1306SetStartLine)
+	s.commands(sh1106SetDCDC, sh1106DCDCOn)
+	s.commands(ssd1306SetSegmentRemap127)
+	s.commands(ssd1306ComScanDec)
+	s.commands(ssd1306SetComPins, 0x12)
+	s.commands(ssd1306SetContrast, 0x80)
+	s.commands(ssd1306SetPrechargePeriod, 0x1F)
+	s.commands(ssd1306SetVComDeselectLevel, 0x40)
+	s.commands(sh1106SetPumpVoltage)
+	s.commands(ssd1306DisplayOnResumeToRAM)
+	s.commands(ssd1306SetDisplayNormal)
+	s.commands(ssd1306SetDisplayOn)
+}
+
+// ssd1309Init runs from an external VCC and has no charge pump.
+func (s *SSD1306) ssd1309Init() {
+	s.commands(ssd1306SetDisplayOff)
+	s.commands(ssd1306SetDisplayClock, 0xA0)
+	s.commands(ssd1306SetMultiplexRatio, uint8(s.DisplayHeight)-1)
+	s.commands(ssd1306SetDisplayOffset, 0x00)
+	s.commands(ssd1306SetStartLine)
+	s.commands(ssd1306SetMemoryAddressingMode, 0x00)
+	s.commands(ssd1306SetSegmentRemap127)
+	s.commands(ssd1306ComScanDec)
+	s.commands(ssd1306SetComPins, 0x12)
+	s.commands(ssd1306SetContrast, 0xDF)
+	s.commands(ssd1306SetPrechargePeriod, 0x82)
+	s.commands(ssd1306SetVComDeselectLevel, 0x34)
+	s.commands(ssd1306DisplayOnResumeToRAM)
+	s.commands(ssd1306SetDisplayNormal)
+	s.commands(ssd1306DeactivateScroll)
+	s.commands(ssd1306SetDisplayOn)
+}
+
 // Halt returns true if device is halted successfully.
 func (s *SSD1306) Halt() (err error) {
 	s.Reset()
@@ -266,29 +531,58 @@ func (s *SSD1306) SetContrast(contrast byte) (err error) {
 
 // Display sends the memory buffer to the display.
 func (s *SSD1306) Display() (err error) {
-	s.command(ssd1306ColumnAddr)
-	s.command(0)
-	s.command(uint8(s.DisplayWidth) - 1)
-	s.command(ssd1306PageAddr)
-	s.command(0)
-	s.command(uint8(s.pageSize) - 1)
-	if err = s.dcDriver.Write(driver.HIGH); err != nil {
+	defer driver.ObserveFrame(s.Controller, time.Now())
+	if s.Controller == ControllerSH1106 {
+		return s.displayPages()
+	}
+	err = s.commands(ssd1306ColumnAddr, 0, uint8(s.DisplayWidth)-1)
+	if err != nil {
 		return err
 	}
-	return s.connection.Tx(append([]byte{0x40}, s.buffer.buffer...), nil)
+	if err = s.commands(ssd1306PageAddr, 0, uint8(s.pageSize)-1); err != nil {
+		return err
+	}
+	return s.data(s.buffer.buffer)
+}
+
+// displayPages sends the buffer one page at a time for controllers without
+// horizontal addressing mode.
+func (s *SSD1306) displayPages() (err error) {
+	col := byte(s.ColumnOffset)
+	for page := 0; page < s.pageSize; page++ {
+		err = s.commands(sh1106SetPageAddr|byte(page), sh1106SetLowColumn|col&0x0F, sh1106SetHighColumn|col>>4)
+		if err != nil {
+			return err
+		}
+		if err = s.data(s.buffer.buffer[page*s.DisplayWidth : (page+1)*s.DisplayWidth]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // ShowImage takes a standard Go image and shows it on the display in monochrome.
+// The image is fitted to the display and thresholded at mid gray.
 func (s *SSD1306) ShowImage(img image.Image) (err error) {
-	if img.Bounds().Dx() != s.DisplayWidth || img.Bounds().Dy() != s.DisplayHeight {
-		return errors.New("Image must match the display width and height")
-	}
+	return s.ShowImageWithOptions(img, nil)
+}
+
+// ShowImageWithOptions converts img with opts (nil for defaults) and shows it.
+func (s *SSD1306) ShowImageWithOptions(img image.Image, opts *ConvertOptions) (err error) {
+	return ShowImage(s, img, opts)
+}
 
+// Bounds returns the panel size.
+func (s *SSD1306) Bounds() image.Rectangle {
+	return image.Rect(0, 0, s.DisplayWidth, s.DisplayHeight)
+}
+
+// DrawFrame copies a whole 1-bit frame to the display buffer and displays it.
+func (s *SSD1306) DrawFrame(frame *image1bit.VerticalLSB) (err error) {
 	s.Clear()
-	for y, w, h := 0, img.Bounds().Dx(), img.Bounds().Dy(); y < h; y++ {
-		for x := 0; x < w; x++ {
-			c := img.At(x, y)
-			if r, g, b, _ := c.RGBA(); r > 0 || g > 0 || b > 0 {
+	for y := 0; y < s.DisplayHeight; y++ {
+		for x := 0; x < s.DisplayWidth; x++ {
+			if frame.BitAt(x, y) {
 				s.Set(x, y, 1)
 			}
 		}
@@ -298,9 +592,24 @@ func (s *SSD1306) ShowImage(img image.Image) (err error) {
 
 // command sends a unique command
 func (s *SSD1306) command(b byte) (err error) {
-	if err = s.dcDriver.Write(driver.LOW); err != nil {
-		return err
+	return s.commands(b)
+}
+
+// commands sends a command with its arguments in one transfer.
+func (s *SSD1306) commands(b ...byte) (err error) {
+	return s.record(s.bus.commands(b...))
+}
+
+// data sends display RAM content.
+func (s *SSD1306) data(b []byte) (err error) {
+	return s.record(s.bus.data(b))
+}
+
+// record observes the result of a transfer and keeps the first error of
+// Init, whose sequences ignore them.
+func (s *SSD1306) record(err error) error {
+	if err != nil && s.lastErr == nil {
+		s.lastErr = err
 	}
-	err = s.connection.Tx([]byte{b}, nil)
-	return err
+	return s.observe(err)
 }
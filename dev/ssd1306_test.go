@@ -0,0 +1,203 @@
+package dev
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+)
+
+// spiRecord is one recorded transfer, dc is the D/C pin level.
+type spiRecord struct {
+	dc   int
+	data []byte
+}
+
+// recorder is a fake SPI connection and D/C pin keeping the command stream.
+type recorder struct {
+	level   int
+	records []spiRecord
+}
+
+func (r *recorder) Close() error { return nil }
+func (r *recorder) Tx(w, _ []byte) error {
+	r.records = append(r.records, spiRecord{dc: r.level, data: append([]byte(nil), w...)})
+	return nil
+}
+
+// commands returns the concatenated command bytes.
+func (r *recorder) commands() []byte {
+	var b []byte
+	for _, rec := range r.records {
+		if rec.dc == 0 {
+			b = append(b, rec.data...)
+		}
+	}
+	return b
+}
+
+// fakePin is a DigitalPinner writing into a recorder.
+type fakePin struct{ r *recorder }
+
+func (p fakePin) Export() error          { return nil }
+func (p fakePin) Unexport() error        { return nil }
+func (p fakePin) Direction(string) error { return nil }
+func (p fakePin) Read() (int, error)     { return p.r.level, nil }
+func (p fakePin) Write(v int) error {
+	if p.r != nil {
+		p.r.level = v
+	}
+	return nil
+}
+
+func newRecorded(t *testing.T, controller string) (*SSD1306, *recorder) {
+	r := &recorder{}
+	s, err := newSSD1306(&spiBus{connection: r, dcDriver: fakePin{r}}, fakePin{}, controller)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Init(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return s, r
+}
+
+func TestSSD1306InitSequences(t *testing.T) {
+	tests := []struct {
+		controller string
+		want       [][]byte
+		absent     []byte
+	}{
+		{ControllerSSD1306, [][]byte{{0x8D, 0x14}, {0x20, 0x00}}, nil},
+		{ControllerSH1106, [][]byte{{0xAD, 0x8B}, {0xA1, 0xC8}, {0x32}}, []byte{0x8D, 0x20}},
+		{ControllerSSD1309, [][]byte{{0xD5, 0xA0}, {0x20, 0x00}, {0xDB, 0x34}}, []byte{0x8D}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.controller, func(t *testing.T) {
+			_, r := newRecorded(t, tt.controller)
+			cmds := r.commands()
+			if cmds[0] != ssd1306SetDisplayOff || cmds[len(cmds)-1] != ssd1306SetDisplayOn {
+				t.Fatalf("init must start with display off and end with on: % X", cmds)
+			}
+			for _, want := range tt.want {
+				if !bytes.Contains(cmds, want) {
+					t.Errorf("init % X lacks % X", cmds, want)
+				}
+			}
+			for _, b := range tt.absent {
+				if bytes.IndexByte(cmds, b) >= 0 {
+					t.Errorf("init % X contains %#x", cmds, b)
+				}
+			}
+			for _, rec := range r.records {
+				if rec.dc != 0 {
+					t.Fatal("data sent during init")
+				}
+			}
+		})
+	}
+	if _, err := newSSD1306(&spiBus{connection: &recorder{}, dcDriver: fakePin{}}, fakePin{}, "ssd9999"); err == nil {
+		t.Error("unknown controller accepted")
+	}
+}
+
+func TestSSD1306DisplayHorizontal(t *testing.T) {
+	for _, controller := range []string{ControllerSSD1306, ControllerSSD1309} {
+		s, r := newRecorded(t, controller)
+		s.Set(0, 0, 1)
+		s.Set(127, 63, 1)
+		r.records = nil
+		if err := s.Display(); err != nil {
+			t.Fatal(err)
+		}
+		want := []byte{ssd1306ColumnAddr, 0, 127, ssd1306PageAddr, 0, 7}
+		if !bytes.Equal(r.commands(), want) {
+			t.Errorf("%s: commands % X, want % X", controller, r.commands(), want)
+		}
+		last := r.records[len(r.records)-1]
+		if last.dc != 1 || len(last.data) != 1024 {
+			t.Fatalf("%s: data transfer dc=%d len=%d", controller, last.dc, len(last.data))
+		}
+		if last.data[0] != 0x01 || last.data[1023] != 0x80 {
+			t.Errorf("%s: pixels at % X ... % X", controller, last.data[0], last.data[1023])
+		}
+	}
+}
+
+func TestSH1106DisplayPages(t *testing.T) {
+	s, r := newRecorded(t, ControllerSH1106)
+	s.Set(0, 8, 1)
+	r.records = nil
+	if err := s.Display(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.records) != 16 {
+		t.Fatalf("got %d transfers, want 8 pages of command + data", len(r.records))
+	}
+	for page := 0; page < 8; page++ {
+		cmd, data := r.records[2*page], r.records[2*page+1]
+		want := []byte{0xB0 | byte(page), 0x02, 0x10}
+		if cmd.dc != 0 || !bytes.Equal(cmd.data, want) {
+			t.Errorf("page %d: command % X, want % X", page, cmd.data, want)
+		}
+		if data.dc != 1 || len(data.data) != 128 {
+			t.Errorf("page %d: data dc=%d len=%d", page, data.dc, len(data.data))
+		}
+	}
+	if r.records[3].data[0] != 0x01 {
+		t.Error("pixel (0, 8) not in the first byte of page 1")
+	}
+}
+
+// blockRecorder is a fake I2C device keeping each block write.
+type blockRecorder struct {
+	blocks [][]byte
+}
+
+func (b *blockRecorder) WriteBlockData(reg uint8, data []byte) error {
+	if len(data) > i2cMaxBlock {
+		return fmt.Errorf("block of %d bytes", len(data))
+	}
+	b.blocks = append(b.blocks, append([]byte{reg}, data...))
+	return nil
+}
+
+func TestSSD1306I2C(t *testing.T) {
+	b := &blockRecorder{}
+	s, err := newSSD1306(&i2cBus{dev: b}, fakePin{}, ControllerSSD1306)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Init(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, blk := range b.blocks {
+		if blk[0] != ssd1306I2CCommand {
+			t.Fatalf("init block % X is not a command", blk)
+		}
+	}
+	b.blocks = nil
+	s.Set(127, 63, 1)
+	if err = s.Display(); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]byte{{0x00, ssd1306ColumnAddr, 0, 127}, {0x00, ssd1306PageAddr, 0, 7}}
+	if len(b.blocks) != 2+1024/i2cMaxBlock {
+		t.Fatalf("got %d blocks", len(b.blocks))
+	}
+	for i, w := range want {
+		if !bytes.Equal(b.blocks[i], w) {
+			t.Errorf("block %d = % X, want % X", i, b.blocks[i], w)
+		}
+	}
+	var ram []byte
+	for _, blk := range b.blocks[2:] {
+		if blk[0] != ssd1306I2CData {
+			t.Fatalf("data block % X lacks the 0x40 control byte", blk[:1])
+		}
+		ram = append(ram, blk[1:]...)
+	}
+	if len(ram) != 1024 || ram[1023] != 0x80 {
+		t.Fatalf("display RAM of %d bytes, last %#x", len(ram), ram[len(ram)-1])
+	}
+}
@@ -1,16 +1,21 @@
 package dev
 
 import (
+	"context"
+	"fmt"
 	"image"
 	"image/draw"
+	"io"
 	"pi/driver"
 	"pi/log"
+	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 
-	"periph.io/x/periph/conn/display"
 	"periph.io/x/periph/conn/gpio"
 	"periph.io/x/periph/conn/gpio/gpioreg"
+	"periph.io/x/periph/conn/i2c/i2creg"
 	"periph.io/x/periph/conn/spi/spireg"
 	"periph.io/x/periph/devices/ssd1306"
 	"periph.io/x/periph/devices/ssd1306/image1bit"
@@ -29,6 +34,8 @@ const (
 )
 
 type SSD1306H struct {
+	health
+	name          string
 	spidev        string
 	dc            string
 	height        int
@@ -40,9 +47,22 @@ type SSD1306H struct {
 	dcpin         gpio.PinOut
 	rsPinNo       int
 	rstDriver     *driver.DigitalPin
+	closer        io.Closer
 }
 
-func NewSSD1306H() *SSD1306H {
+// NewSSD1306H opens the OLED on SPI.
+func NewSSD1306H() (*SSD1306H, error) {
+	return openSSD1306H(&DisplayOpts{})
+}
+
+// NewSSD1306HI2C opens the OLED on I2C, periph.io only uses address 0x3C.
+func NewSSD1306HI2C() (*SSD1306H, error) {
+	return openSSD1306H(&DisplayOpts{Bus: BusI2C})
+}
+
+// openSSD1306H opens the OLED for o, zero fields take the Pioneer600
+// wiring. periph.io picks the SPI speed itself and initializes the panel.
+func openSSD1306H(o *DisplayOpts) (*SSD1306H, error) {
 	s := &SSD1306H{
 		spidev:        "/dev/spidev0.0",
 		dc:            "16",
@@ -53,61 +73,116 @@ func NewSSD1306H() *SSD1306H {
 		swapTopBottom: false,
 		rsPinNo:       19,
 	}
+	if o.Device != "" && o.Bus != BusI2C {
+		s.spidev = o.Device
+	}
+	if o.DCPin != 0 {
+		s.dc = strconv.Itoa(o.DCPin)
+	}
+	if o.RSTPin != 0 {
+		s.rsPinNo = o.RSTPin
+	}
 	_, err := host.Init()
 	if err != nil {
-		log.Default().Error("Host init Fail!")
-		return nil
+		return nil, fmt.Errorf("periph host init: %v", err)
 	}
 
-	s.dcpin = gpioreg.ByName(s.dc)
 	s.rstDriver = driver.NewDigitalPin(s.rsPinNo)
 	s.rstDriver.Export()
 	s.rstDriver.Direction(driver.OUT)
 	s.Reset()
 
 	opts := ssd1306.Opts{W: s.width, H: s.height, Rotated: s.rotated, Sequential: s.sequential, SwapTopBottom: s.swapTopBottom}
+	if o.Bus == BusI2C {
+		// periph.io names the buses by number, "" opens the first one.
+		b, err := i2creg.Open(strings.TrimPrefix(o.Device, "/dev/i2c-"))
+		if err != nil {
+			return nil, fmt.Errorf("open I2C %q: %v", o.Device, err)
+		}
+		if s.dev, err = ssd1306.NewI2C(b, &opts); err != nil {
+			b.Close()
+			return nil, s.fail(err)
+		}
+		location := o.Device
+		if location == "" {
+			location = I2cDev
+		}
+		s.name, s.closer = i2cName("ssd1306", location, I2cAddrSSD1306), b
+		return s, s.fail(nil)
+	}
+
+	s.dcpin = gpioreg.ByName(s.dc)
 	c, err := spireg.Open(s.spidev)
 	if err != nil {
-		log.Default().Error("SPI Open Fail!")
-		return nil
+		return nil, fmt.Errorf("open SPI %s: %v", s.spidev, err)
 	}
 
 	s.dev, err = ssd1306.NewSPI(c, s.dcpin, &opts)
 	if err != nil {
-		log.Default().Error("Connect to SPI Dev Fail!")
-		return nil
+		c.Close()
+		return nil, s.fail(err)
 	}
-	return s
+	s.name, s.closer = "ssd1306@"+s.spidev, c
+	return s, s.fail(nil)
 }
 
-func (ssd *SSD1306H) DrawText(pos SSD1306Pos, text string) error {
-	log.Default().Info("dev Bounds = ", ssd.dev.Bounds())
-	src := image1bit.NewVerticalLSB(ssd.dev.Bounds())
-	img := convert(ssd.dev, src)
-	switch pos {
-	case PosTopCenter:
-		drawTextTopCenter(img, text)
-	case PosTopLeft:
-		drawTextTopLeft(img, text)
-	case PosTopRight:
-		drawTextTopRight(img, text)
-	case PosBottomLeft:
-		drawTextBottomLeft(img, text)
-	case PosBottomRight:
-		drawTextBottomRight(img, text)
-	case PosBottomCenter:
-		drawTextBottomCenter(img, text)
-	default:
+// Name returns ssd1306@ and the bus.
+func (ssd *SSD1306H) Name() string {
+	return ssd.name
+}
 
-	}
+// Init wakes the panel up, periph.io sent the init sequence on open.
+func (ssd *SSD1306H) Init(ctx context.Context) error {
+	return ssd.fail(ssd.dev.Invert(false))
+}
 
-	if err := ssd.dev.Draw(ssd.dev.Bounds(), img, image.Point{}); err != nil {
+// Close turns the panel off and releases the bus and the reset pin.
+func (ssd *SSD1306H) Close() error {
+	ssd.Halt()
+	ssd.rstDriver.Unexport()
+	return ssd.closer.Close()
+}
+
+func (ssd *SSD1306H) DrawText(pos SSD1306Pos, text string) error {
+	if err := ShowText(ssd, pos, text); err != nil {
 		log.Default().Error("Draw error!")
 		return err
 	}
 	return nil
 }
 
+// Bounds returns the panel size.
+func (ssd *SSD1306H) Bounds() image.Rectangle {
+	return ssd.dev.Bounds()
+}
+
+// DrawFrame sends a whole 1-bit frame to the panel.
+func (ssd *SSD1306H) DrawFrame(frame *image1bit.VerticalLSB) error {
+	defer driver.ObserveFrame(ControllerSSD1306, time.Now())
+	return ssd.observe(ssd.dev.Draw(ssd.dev.Bounds(), frame, image.Point{}))
+}
+
+// SetContrast sets the display contrast (0-255).
+func (ssd *SSD1306H) SetContrast(contrast byte) error {
+	return ssd.observe(ssd.dev.SetContrast(contrast))
+}
+
+// On turns on the display. periph.io re-enables a halted panel on the next
+// command, so resend the normal (non inverted) mode.
+func (ssd *SSD1306H) On() error {
+	return ssd.observe(ssd.dev.Invert(false))
+}
+
+// Off turns off the display.
+func (ssd *SSD1306H) Off() error {
+	return ssd.observe(ssd.dev.Halt())
+}
+
+// Halt turns off the display.
+func (ssd *SSD1306H) Halt() error {
+	return ssd.observe(ssd.dev.Halt())
+}
+
 // Reset SSD1306H
 func (ssd *SSD1306H) Reset() (err error) {
 	ssd.rstDriver.Write(driver.HIGH)
@@ -118,31 +193,16 @@ func (ssd *SSD1306H) Reset() (err error) {
 	return nil
 }
 
-func (ssd *SSD1306H) DrawImage() error {
-	return nil
+// ShowImage converts img with opts (nil for defaults) and draws it.
+func (ssd *SSD1306H) ShowImage(img image.Image, opts *ConvertOptions) error {
+	return ShowImage(ssd, img, opts)
 }
 
-func convert(display display.Drawer, src image.Image) *image1bit.VerticalLSB {
-	screenBounds := display.Bounds()
-	size := screenBounds.Size()
-	src = resize(src, size)
-	img := image1bit.NewVerticalLSB(screenBounds)
-	r := src.Bounds()
-	r = r.Add(image.Point{X: (size.X - r.Max.X) / 2, Y: (size.Y - r.Max.Y) / 2})
-	draw.Draw(img, r, src, image.Point{}, draw.Src)
-	return img
-}
-
-func resize(src image.Image, size image.Point) *image.NRGBA {
-	srcMax := src.Bounds().Max
-	dst := image.NewNRGBA(image.Rectangle{Max: size})
-	for y := 0; y < size.Y; y++ {
-		sY := (y*srcMax.Y + size.Y/2) / size.Y
-		for x := 0; x < size.X; x++ {
-			dst.Set(x, y, src.At((x*srcMax.X+size.X/2)/size.X, sY))
-		}
-	}
-	return dst
+// DrawImage plays a once at its own frame delays, eg. a boot splash.
+func (ssd *SSD1306H) DrawImage(a *Animation) error {
+	stats, err := Play(context.Background(), ssd, a, &PlayOptions{Loops: 1})
+	log.Default().Infof("animation: %d frames shown, %d dropped in %v", stats.Frames, stats.Dropped, stats.Elapsed)
+	return err
 }
 
 func drawTextBottomRight(img draw.Image, text string) {
@@ -0,0 +1,132 @@
+package dev
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+var panel = image.Rect(0, 0, ssd1306Width, ssd1306Height)
+
+func TestDrawTextPositions(t *testing.T) {
+	tests := []struct {
+		name string
+		pos  SSD1306Pos
+	}{
+		{"pos_top_center", PosTopCenter},
+		{"pos_top_left", PosTopLeft},
+		{"pos_top_right", PosTopRight},
+		{"pos_bottom_left", PosBottomLeft},
+		{"pos_bottom_right", PosBottomRight},
+		{"pos_bottom_center", PosBottomCenter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkGolden(t, tt.name, renderText(panel, tt.pos, "Super Google."))
+		})
+	}
+}
+
+func TestDrawTextUnknownPos(t *testing.T) {
+	if n := countOn(renderText(panel, SSD1306Pos(99), "Super Google.")); n != 0 {
+		t.Fatalf("unknown position lit %d pixels, want 0", n)
+	}
+}
+
+func TestDrawTextOverflow(t *testing.T) {
+	// Text wider than the panel starts at the left edge in every position.
+	long := strings.Repeat("W", 20)
+	checkGolden(t, "text_overflow_top", renderText(panel, PosTopRight, long))
+	checkGolden(t, "text_overflow_bottom", renderText(panel, PosBottomCenter, long))
+}
+
+func TestFontTable(t *testing.T) {
+	// Every printable ASCII glyph, 18 per row.
+	const perRow = 18
+	n := len(glyphs)
+	rows := (n + perRow - 1) / perRow
+	img := image1bit.NewVerticalLSB(image.Rect(0, 0, perRow*7, rows*13))
+	for i := 0; i < n; i++ {
+		p := image.Point{X: i % perRow * 7, Y: i / perRow * 13}
+		drawText(img, p, string(rune(0x21+i)))
+	}
+	checkGolden(t, "font_table", img)
+
+	for i := range glyphs {
+		one := image1bit.NewVerticalLSB(image.Rect(0, 0, 7, 13))
+		drawText(one, image.Point{}, string(rune(0x21+i)))
+		if countOn(one) == 0 {
+			t.Errorf("glyph %q is empty", rune(0x21+i))
+		}
+	}
+	// Space and runes outside the table only advance.
+	blank := image1bit.NewVerticalLSB(image.Rect(0, 0, 21, 13))
+	drawText(blank, image.Point{}, " \u00a0€")
+	if countOn(blank) != 0 {
+		t.Error("space and unknown runes must not draw")
+	}
+}
+
+// testPattern returns a 64x32 horizontal gradient with a white border.
+func testPattern() image.Image {
+	img := image.NewGray(image.Rect(0, 0, 64, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 64; x++ {
+			v := uint8(x * 255 / 63)
+			if x == 0 || y == 0 || x == 63 || y == 31 {
+				v = 0xff
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+func TestConvert(t *testing.T) {
+	e, err := NewEmulator(&EmulatorOpts{Output: EmulatorGIF})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		opts ConvertOptions
+	}{
+		{"convert_threshold_fit", DefaultConvertOptions},
+		{"convert_floyd_fill", ConvertOptions{Dither: DitherFloydSteinberg, Scale: ScaleFill}},
+		{"convert_bayer_crop", ConvertOptions{Dither: DitherBayer, Scale: ScaleCrop}},
+		{"convert_bayer_stretch_gamma", ConvertOptions{Dither: DitherBayer, Scale: ScaleStretch, Gamma: 2.2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			checkGolden(t, tt.name, convert(e, testPattern(), &opts))
+		})
+	}
+}
+
+func TestResize(t *testing.T) {
+	src := testPattern()
+	for _, size := range []image.Point{{32, 16}, {128, 64}, {100, 10}} {
+		dst := resize(src, size)
+		if dst.Bounds().Size() != size {
+			t.Fatalf("resize to %v returned %v", size, dst.Bounds().Size())
+		}
+		// Corners are on the white border in every size.
+		for _, p := range []image.Point{{0, 0}, {size.X - 1, 0}, {0, size.Y - 1}, {size.X - 1, size.Y - 1}} {
+			if c := dst.NRGBAAt(p.X, p.Y); c.R < 0x80 || c.A != 0xff {
+				t.Errorf("resize to %v: corner %v is %v", size, p, c)
+			}
+		}
+	}
+	// Shrinking averages: a 2x1 black and white source becomes mid gray.
+	bw := image.NewGray(image.Rect(0, 0, 2, 1))
+	bw.SetGray(1, 0, color.Gray{Y: 0xff})
+	if c := resize(bw, image.Point{1, 1}).NRGBAAt(0, 0); c.R < 0x70 || c.R > 0x90 {
+		t.Errorf("shrunk pixel is %v, want mid gray", c)
+	}
+	frame := ConvertImage(resize(src, image.Point{128, 64}), panel, nil)
+	checkGolden(t, "resize_128x64", frame)
+}
@@ -78,7 +78,16 @@ func (d *DigitalPin) Read() (n int, err error) {
 	return strconv.Atoi(string(buf[0]))
 }
 
+// Export exports the pin, the failures are counted.
 func (d *DigitalPin) Export() error {
+	err := d.export()
+	if err != nil {
+		gpioExportFailures.WithLabelValues(d.pin).Inc()
+	}
+	return err
+}
+
+func (d *DigitalPin) export() error {
 	export, err := os.OpenFile(GPIOPATH+"/export", os.O_WRONLY, 0644)
 	if err != nil {
 		return err
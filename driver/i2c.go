@@ -0,0 +1,13 @@
+package driver
+
+import "time"
+
+// count counts a transaction of the device.
+func (d *I2CDevice) count(err error) error {
+	return transaction(BusI2C, d.location, d.address, err)
+}
+
+// countRead counts a read of the device started at start.
+func (d *I2CDevice) countRead(start time.Time, err error) error {
+	return read(BusI2C, d.location, d.address, start, err)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"syscall"
+	"time"
 	"unsafe"
 )
 
@@ -27,6 +28,7 @@ const (
 	I2C_FUNC_SMBUS_WRITE_WORD_DATA  = 0x00400000
 	I2C_FUNC_SMBUS_READ_BLOCK_DATA  = 0x01000000
 	I2C_FUNC_SMBUS_WRITE_BLOCK_DATA = 0x02000000
+	I2C_FUNC_SMBUS_READ_I2C_BLOCK   = 0x04000000
 	// Transaction types
 	I2C_SMBUS_BYTE             = 1
 	I2C_SMBUS_BYTE_DATA        = 2
@@ -46,14 +48,16 @@ type i2cSmbusIoctlData struct {
 }
 
 type I2CDevice struct {
-	file  *os.File
-	funcs uint64 // adapter functionality mask
+	file     *os.File
+	funcs    uint64 // adapter functionality mask
+	location string
+	address  string
 }
 
 // NewI2cDevice returns an io.ReadWriteCloser with the proper ioctrl given
 // an i2c bus location.
 func NewI2cDevice(location string) (d *I2CDevice, err error) {
-	d = &I2CDevice{}
+	d = &I2CDevice{location: location}
 
 	if d.file, err = os.OpenFile(location, os.O_RDWR, os.ModeExclusive); err != nil {
 		return
@@ -91,6 +95,9 @@ func (d *I2CDevice) SetAddress(address int) (err error) {
 		err = fmt.Errorf("Setting address failed with syscall.Errno %v", errno)
 	}
 
+	if err == nil {
+		d.address = i2cAddress(address)
+	}
 	return
 }
 
@@ -157,6 +164,23 @@ func (d *I2CDevice) WriteWordData(reg uint8, val uint16) (err error) {
 	return err
 }
 
+// ReadBlockData reads len(data), up to 32, registers from reg in a single
+// transaction.
+func (d *I2CDevice) ReadBlockData(reg uint8, data []byte) (err error) {
+	if len(data) > 32 {
+		return fmt.Errorf("Reading blocks larger than 32 bytes (%v) not supported", len(data))
+	}
+	if d.funcs&I2C_FUNC_SMBUS_READ_I2C_BLOCK == 0 {
+		return fmt.Errorf("SMBus read i2c block not supported")
+	}
+
+	var block [34]byte
+	block[0] = byte(len(data))
+	err = d.smbusAccess(I2C_SMBUS_READ, reg, I2C_SMBUS_I2C_BLOCK_DATA, uintptr(unsafe.Pointer(&block)))
+	copy(data, block[1:])
+	return err
+}
+
 func (d *I2CDevice) WriteBlockData(reg uint8, data []byte) (err error) {
 	if len(data) > 32 {
 		return fmt.Errorf("Writing blocks larger than 32 bytes (%v) not supported", len(data))
@@ -167,6 +191,7 @@ func (d *I2CDevice) WriteBlockData(reg uint8, data []byte) (err error) {
 	buf[0] = reg
 
 	n, err := d.file.Write(buf)
+	d.count(err)
 
 	if err != nil {
 		return err
@@ -181,15 +206,26 @@ func (d *I2CDevice) WriteBlockData(reg uint8, data []byte) (err error) {
 
 // Read implements the io.ReadWriteCloser method by direct I2C read operations.
 func (d *I2CDevice) Read(b []byte) (n int, err error) {
-	return d.file.Read(b)
+	start := time.Now()
+	n, err = d.file.Read(b)
+	return n, d.countRead(start, err)
 }
 
 // Write implements the io.ReadWriteCloser method by direct I2C write operations.
 func (d *I2CDevice) Write(b []byte) (n int, err error) {
-	return d.file.Write(b)
-}
-
-func (d *I2CDevice) smbusAccess(readWrite byte, command byte, size uint32, data uintptr) error {
+	n, err = d.file.Write(b)
+	return n, d.count(err)
+}
+
+func (d *I2CDevice) smbusAccess(readWrite byte, command byte, size uint32, data uintptr) (err error) {
+	start := time.Now()
+	defer func() {
+		if readWrite == I2C_SMBUS_READ {
+			err = d.countRead(start, err)
+		} else {
+			err = d.count(err)
+		}
+	}()
 	smbus := &i2cSmbusIoctlData{
 		readWrite: readWrite,
 		command:   command,
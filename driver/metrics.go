@@ -0,0 +1,81 @@
+package driver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace starts the names of the metrics.
+const Namespace = "pioneer600"
+
+// Bus types of the transaction metrics.
+const (
+	BusI2C     = "i2c"
+	BusSPI     = "spi"
+	BusOneWire = "1-wire"
+)
+
+var (
+	transactions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "bus_transactions_total",
+		Help:      "Transactions by bus type, bus device and address.",
+	}, []string{"type", "bus", "address"})
+	transactionErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "bus_transaction_errors_total",
+		Help:      "Failed transactions by bus type, bus device and address.",
+	}, []string{"type", "bus", "address"})
+	readSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "bus_read_duration_seconds",
+		Help:      "Latency of the reads by bus type, bus device and address.",
+		// From an I2C byte to a 750ms DS18B20 conversion.
+		Buckets: prometheus.ExponentialBuckets(50e-6, 4, 9),
+	}, []string{"type", "bus", "address"})
+	gpioExportFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "gpio_export_failures_total",
+		Help:      "Failed sysfs exports by GPIO pin.",
+	}, []string{"pin"})
+	frameSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "display_frame_duration_seconds",
+		Help:      "Time to send a frame to the display by controller.",
+		Buckets:   prometheus.ExponentialBuckets(1e-3, 2, 10),
+	}, []string{"controller"})
+)
+
+// Collectors returns the metrics of the drivers.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{transactions, transactionErrors, readSeconds, gpioExportFailures, frameSeconds}
+}
+
+// transaction counts a transaction of a bus and its error, if any, and
+// returns err. SPI devices have no address, 1-Wire ones their id.
+func transaction(typ, bus, address string, err error) error {
+	transactions.WithLabelValues(typ, bus, address).Inc()
+	if err != nil {
+		transactionErrors.WithLabelValues(typ, bus, address).Inc()
+	}
+	return err
+}
+
+// read counts a read transaction started at start.
+func read(typ, bus, address string, start time.Time, err error) error {
+	readSeconds.WithLabelValues(typ, bus, address).Observe(time.Since(start).Seconds())
+	return transaction(typ, bus, address, err)
+}
+
+// i2cAddress formats an I2C address label.
+func i2cAddress(address int) string {
+	return fmt.Sprintf("0x%02x", address)
+}
+
+// ObserveFrame records the time a display controller took to show a frame
+// started at start.
+func ObserveFrame(controller string, start time.Time) {
+	frameSeconds.WithLabelValues(controller).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,16 @@
+package driver
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+)
+
+// ReadW1Slave reads the w1_slave file of the 1-Wire device id, eg.
+// 28-00000a1b2c3d, in the sysfs directory dir. The kernel converts while
+// reading.
+func ReadW1Slave(dir, id string) ([]byte, error) {
+	start := time.Now()
+	data, err := ioutil.ReadFile(filepath.Join(dir, id, "w1_slave"))
+	return data, read(BusOneWire, dir, id, start, err)
+}
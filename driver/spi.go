@@ -1,6 +1,9 @@
 package driver
 
 import (
+	"fmt"
+	"time"
+
 	"periph.io/x/periph/conn/physic"
 	xspi "periph.io/x/periph/conn/spi"
 	xsysfs "periph.io/x/periph/host/sysfs"
@@ -70,8 +73,15 @@ func (c *SpiConnection) Close() error {
 }
 
 // Tx uses the SPI device to send/receive data.
+// Reads, with r not empty, are timed.
 func (c *SpiConnection) Tx(w, r []byte) error {
-	return c.dev.Tx(w, r)
+	start := time.Now()
+	err := c.dev.Tx(w, r)
+	bus := fmt.Sprintf("/dev/spidev%d.%d", c.bus, c.chip)
+	if len(r) > 0 {
+		return read(BusSPI, bus, "", start, err)
+	}
+	return transaction(BusSPI, bus, "", err)
 }
 
 // GetSpiConnection is a helper to return a SPI device.
@@ -84,5 +94,7 @@ func GetSpiConnection(busNum, chipNum, mode, bits int, maxSpeed int64) (Connecti
 	if err != nil {
 		return nil, err
 	}
-	return NewConnection(p, c), nil
+	conn := NewConnection(p, c)
+	conn.bus, conn.chip = busNum, chipNum
+	return conn, nil
 }
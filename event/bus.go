@@ -0,0 +1,89 @@
+package event
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is a device reading or state change, eg. the temperature of the
+// DS18B20 or the LED turning on (Value 1).
+type Event struct {
+	Device string    `json:"device"`
+	Name   string    `json:"name"`
+	Value  float64   `json:"value"`
+	Unit   string    `json:"unit,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// key identifies the latest value of a device.
+type key struct{ device, name string }
+
+// Bus fans events out to the subscribers and keeps the latest of each
+// device and name.
+type Bus struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+	last map[key]Event
+}
+
+// New creates a new Bus.
+func New() *Bus {
+	return &Bus{
+		subs: make(map[chan Event]struct{}),
+		last: make(map[key]Event),
+	}
+}
+
+// Publish sends e to the subscribers, the time is set when zero. It never
+// blocks, events are dropped for subscribers with a full buffer.
+func (b *Bus) Publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.last[key{e.Device, e.Name}] = e
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving the events published from now on,
+// buffer events can wait before being dropped. cancel closes the channel.
+func (b *Bus) Subscribe(buffer int) (events <-chan Event, cancel func()) {
+	ch := make(chan Event, buffer)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Last returns the latest event of device and name.
+func (b *Bus) Last(device, name string) (Event, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e, ok := b.last[key{device, name}]
+	return e, ok
+}
+
+// Snapshot returns the latest event of each device and name.
+func (b *Bus) Snapshot() []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	events := make([]Event, 0, len(b.last))
+	for _, e := range b.last {
+		events = append(events, e)
+	}
+	return events
+}
@@ -0,0 +1,41 @@
+package event
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBus(t *testing.T) {
+	b := New()
+	events, cancel := b.Subscribe(1)
+	b.Publish(Event{Device: "temp", Name: "temperature", Value: 21.5, Unit: "°C"})
+	// The buffer is full, this one is dropped for the subscriber but kept
+	// as the latest value.
+	b.Publish(Event{Device: "temp", Name: "temperature", Value: 22})
+
+	e := <-events
+	if e.Value != 21.5 || e.Time.IsZero() {
+		t.Fatalf("event = %+v", e)
+	}
+	select {
+	case e = <-events:
+		t.Fatalf("dropped event received: %+v", e)
+	case <-time.After(10 * time.Millisecond):
+	}
+	if last, ok := b.Last("temp", "temperature"); !ok || last.Value != 22 {
+		t.Fatalf("last = %+v, %v", last, ok)
+	}
+	if _, ok := b.Last("temp", "humidity"); ok {
+		t.Fatal("unknown value found")
+	}
+
+	cancel()
+	cancel()
+	if _, ok := <-events; ok {
+		t.Fatal("channel open after cancel")
+	}
+	b.Publish(Event{Device: "led", Name: "state", Value: 1})
+	if n := len(b.Snapshot()); n != 2 {
+		t.Fatalf("snapshot has %d events", n)
+	}
+}
@@ -1,14 +1,21 @@
 module pi
 
-go 1.14
+go 1.16
 
 require (
+	github.com/eclipse/paho.mqtt.golang v1.3.5
+	github.com/fsnotify/fsnotify v1.4.7
 	github.com/google/wire v0.4.0 // indirect
+	github.com/gorilla/websocket v1.4.2
+	github.com/prometheus/client_golang v1.11.1
 	github.com/spf13/viper v1.7.0
 	github.com/urfave/cli v1.22.4
+	github.com/yuin/gopher-lua v0.0.0-20210529063254-f4c35e4016d9
 	go.uber.org/dig v1.9.0 // indirect
 	go.uber.org/zap v1.10.0
 	golang.org/x/tools v0.0.0-20191112195655-aa38f8e97acc // indirect
+	google.golang.org/grpc v1.43.0
+	google.golang.org/protobuf v1.27.1
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0
 	periph.io/x/periph v3.6.3+incompatible
 )
@@ -0,0 +1,169 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Point is an aggregate of the samples of a series from Time, a raw
+// sample has a Count of 1.
+type Point struct {
+	Time  time.Time
+	Min   float64
+	Max   float64
+	Sum   float64
+	Count int
+}
+
+// Mean returns the average of the samples.
+func (p Point) Mean() float64 {
+	if p.Count == 0 {
+		return math.NaN()
+	}
+	return p.Sum / float64(p.Count)
+}
+
+// add merges q into p, p keeps its time.
+func (p *Point) add(q Point) {
+	if p.Count == 0 {
+		p.Min, p.Max = q.Min, q.Max
+	}
+	p.Min = math.Min(p.Min, q.Min)
+	p.Max = math.Max(p.Max, q.Max)
+	p.Sum += q.Sum
+	p.Count += q.Count
+}
+
+// Query returns the points of series from from to to, aggregated by step,
+// raw samples when step is 0. It reads the coarsest tier not above step
+// which still keeps from, or the finest one which does.
+func (s *Store) Query(series string, from, to time.Time, step time.Duration) ([]Point, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.pick(from, step)
+	starts, err := t.segments()
+	if err != nil {
+		return nil, err
+	}
+	buckets := make(map[int64]*Point)
+	add := func(name string, p Point) {
+		if name != series || p.Time.Before(from) || !p.Time.Before(to) {
+			return
+		}
+		at := p.Time
+		if step > 0 {
+			at = at.Truncate(step)
+		}
+		b := buckets[at.UnixNano()]
+		if b == nil {
+			b = &Point{Time: at}
+			buckets[at.UnixNano()] = b
+		}
+		b.add(p)
+	}
+	for _, start := range starts {
+		if !start.Before(to) || !start.Add(t.span).After(from) {
+			continue
+		}
+		if err := t.read(start, add); err != nil {
+			return nil, err
+		}
+	}
+	if b := t.buckets[series]; b != nil {
+		add(series, *b)
+	}
+	points := make([]Point, 0, len(buckets))
+	for _, b := range buckets {
+		points = append(points, *b)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	return points, nil
+}
+
+// pick returns the tier of a query.
+func (s *Store) pick(from time.Time, step time.Duration) *tier {
+	i := 0
+	for j, t := range s.tiers {
+		if t.resolution <= step {
+			i = j
+		}
+	}
+	oldest := s.now()
+	for ; i < len(s.tiers); i++ {
+		if !from.Before(oldest.Add(-s.tiers[i].retention)) {
+			break
+		}
+	}
+	if i == len(s.tiers) {
+		i--
+	}
+	return s.tiers[i]
+}
+
+// Aggregate returns the minimum, maximum, sum and count of the samples of
+// series from from to to, with a zero Count without any.
+func (s *Store) Aggregate(series string, from, to time.Time) (Point, error) {
+	points, err := s.Query(series, from, to, to.Sub(from))
+	if err != nil {
+		return Point{}, err
+	}
+	total := Point{Time: from}
+	for _, p := range points {
+		total.add(p)
+	}
+	return total, nil
+}
+
+// WriteCSV writes the points of series with a time,series,min,max,mean,count
+// header, times in RFC 3339.
+func WriteCSV(w io.Writer, series string, points []Point) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"time", "series", "min", "max", "mean", "count"})
+	for _, p := range points {
+		cw.Write([]string{
+			p.Time.UTC().Format(time.RFC3339Nano), series,
+			formatFloat(p.Min), formatFloat(p.Max), formatFloat(p.Mean()), strconv.Itoa(p.Count),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonPoint is the JSON of a Point.
+type jsonPoint struct {
+	Time  time.Time `json:"time"`
+	Min   float64   `json:"min"`
+	Max   float64   `json:"max"`
+	Mean  float64   `json:"mean"`
+	Count int       `json:"count"`
+}
+
+// WriteJSON writes the points of series as {"series", "points": [{"time",
+// "min", "max", "mean", "count"}]}.
+func WriteJSON(w io.Writer, series string, points []Point) error {
+	export := struct {
+		Series string      `json:"series"`
+		Points []jsonPoint `json:"points"`
+	}{series, make([]jsonPoint, len(points))}
+	for i, p := range points {
+		export.Points[i] = jsonPoint{Time: p.Time.UTC(), Min: p.Min, Max: p.Max, Mean: p.Mean(), Count: p.Count}
+	}
+	return json.NewEncoder(w).Encode(export)
+}
+
+// ParseTime parses an RFC 3339 time, now, or a duration from now such as
+// -24h.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if s == "now" {
+		return now, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
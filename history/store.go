@@ -0,0 +1,376 @@
+// Package history stores the sensor readings in append-only segment files,
+// downsampled into coarser tiers kept longer.
+package history
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"pi/event"
+	"pi/log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Buckets is the number of aggregates of a series in a downsampled
+// segment, eg. a day of 1m resolution.
+const Buckets = 1440
+
+// Tier is a downsampling of the readings.
+type Tier struct {
+	// Resolution is the time span of an aggregate, whole seconds.
+	Resolution time.Duration
+	// Retention is how long the aggregates are kept.
+	Retention time.Duration
+}
+
+// Options is the history section of the configuration.
+type Options struct {
+	// Path is the directory of the segments, empty disables the history.
+	Path string
+	// Segment is the time span of a raw segment file.
+	Segment time.Duration
+	// Retention is how long the raw samples are kept.
+	Retention time.Duration
+	// Downsample are the tiers by increasing resolution.
+	Downsample []Tier
+}
+
+// NewOptions reads the history section of the configuration.
+func NewOptions(v *viper.Viper) (*Options, error) {
+	o := &Options{
+		Segment:   time.Hour,
+		Retention: 48 * time.Hour,
+		Downsample: []Tier{
+			{Resolution: time.Minute, Retention: 30 * 24 * time.Hour},
+			{Resolution: time.Hour, Retention: 365 * 24 * time.Hour},
+		},
+	}
+	if err := v.UnmarshalKey("history", o); err != nil {
+		return nil, err
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *Options) validate() error {
+	if o.Segment <= 0 || o.Retention <= 0 {
+		return fmt.Errorf("history segment %v and retention %v must be positive", o.Segment, o.Retention)
+	}
+	prev := time.Duration(0)
+	for _, t := range o.Downsample {
+		if t.Resolution <= prev || t.Resolution%time.Second != 0 {
+			return fmt.Errorf("history resolution %v must be whole seconds, above %v", t.Resolution, prev)
+		}
+		if t.Retention <= 0 {
+			return fmt.Errorf("history retention %v of %v must be positive", t.Retention, t.Resolution)
+		}
+		prev = t.Resolution
+	}
+	return nil
+}
+
+// Store records the readings of series named device/name. Raw samples are
+// appended to the segment of their time, aggregates when their bucket is
+// over or the store closed. Segments past the retention of their tier are
+// deleted when a new one is started.
+type Store struct {
+	mu     sync.Mutex
+	tiers  []*tier
+	series map[string]bool
+	now    func() time.Time
+}
+
+// tier is the segments of a resolution, 0 for the raw samples.
+type tier struct {
+	dir        string
+	resolution time.Duration
+	retention  time.Duration
+	span       time.Duration
+	file       *os.File
+	start      time.Time
+	buckets    map[string]*Point
+}
+
+// Open opens or creates the store in o.Path.
+func Open(o *Options) (*Store, error) {
+	return open(o, time.Now)
+}
+
+func open(o *Options, now func() time.Time) (*Store, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	s := &Store{series: make(map[string]bool), now: now}
+	s.tiers = append(s.tiers, &tier{dir: filepath.Join(o.Path, "raw"), retention: o.Retention, span: o.Segment})
+	for _, t := range o.Downsample {
+		s.tiers = append(s.tiers, &tier{
+			dir:        filepath.Join(o.Path, fmt.Sprintf("%ds", t.Resolution/time.Second)),
+			resolution: t.Resolution,
+			retention:  t.Retention,
+			span:       t.Resolution * Buckets,
+			buckets:    make(map[string]*Point),
+		})
+	}
+	for _, t := range s.tiers {
+		if err := os.MkdirAll(t.dir, 0755); err != nil {
+			return nil, err
+		}
+		if err := t.expire(s.now()); err != nil {
+			return nil, err
+		}
+	}
+	// The longest kept tier knows the series.
+	last := s.tiers[len(s.tiers)-1]
+	starts, err := last.segments()
+	if err != nil {
+		return nil, err
+	}
+	for _, start := range starts {
+		last.read(start, func(series string, p Point) {
+			s.series[series] = true
+		})
+	}
+	return s, nil
+}
+
+// Append records the sample v of series at t. Samples past the retention
+// are dropped.
+func (s *Store) Append(series string, t time.Time, v float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.series[series] = true
+	for _, tr := range s.tiers {
+		if t.Before(now.Add(-tr.retention)) {
+			continue
+		}
+		if tr.resolution == 0 {
+			if err := tr.write(now, t, series+"\t"+formatFloat(v)); err != nil {
+				return err
+			}
+			continue
+		}
+		bucket := t.Truncate(tr.resolution)
+		if b := tr.buckets[series]; b != nil {
+			if b.Time.Equal(bucket) {
+				b.add(Point{Time: bucket, Min: v, Max: v, Sum: v, Count: 1})
+				continue
+			}
+			if err := tr.flush(now, series, b); err != nil {
+				return err
+			}
+		}
+		tr.buckets[series] = &Point{Time: bucket, Min: v, Max: v, Sum: v, Count: 1}
+	}
+	return nil
+}
+
+// Series returns the names of the recorded series, sorted.
+func (s *Store) Series() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.series))
+	for name := range s.series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close writes the open aggregates and closes the segments.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	var first error
+	for _, tr := range s.tiers {
+		for series, b := range tr.buckets {
+			if err := tr.flush(now, series, b); err != nil && first == nil {
+				first = err
+			}
+			delete(tr.buckets, series)
+		}
+		if tr.file != nil {
+			if err := tr.file.Close(); err != nil && first == nil {
+				first = err
+			}
+			tr.file = nil
+		}
+	}
+	return first
+}
+
+// Run records the readings published on bus until ctx is done, then closes
+// the store.
+func (s *Store) Run(ctx context.Context, bus *event.Bus) error {
+	events, cancel := bus.Subscribe(64)
+	defer cancel()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Close(); err != nil {
+				log.Default().Error("history close error: ", err)
+			}
+			return ctx.Err()
+		case e := <-events:
+			if e.Unit == "" {
+				continue
+			}
+			if err := s.Append(e.Device+"/"+e.Name, e.Time, e.Value); err != nil {
+				log.Default().Warnf("history %s/%s error: %v", e.Device, e.Name, err)
+			}
+		}
+	}
+}
+
+// flush appends the aggregate b of series.
+func (t *tier) flush(now time.Time, series string, b *Point) error {
+	return t.write(now, b.Time, strings.Join([]string{
+		series, formatFloat(b.Min), formatFloat(b.Max), formatFloat(b.Sum), strconv.Itoa(b.Count),
+	}, "\t"))
+}
+
+// write appends the record of at to the segment of its time, switching
+// segments as needed.
+func (t *tier) write(now, at time.Time, record string) error {
+	start := at.Truncate(t.span)
+	if t.file == nil || !start.Equal(t.start) {
+		if t.file != nil {
+			t.file.Close()
+			t.file = nil
+		}
+		if start.After(t.start) {
+			if err := t.expire(now); err != nil {
+				return err
+			}
+		}
+		f, err := os.OpenFile(t.path(start), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		if err = terminate(f); err != nil {
+			f.Close()
+			return err
+		}
+		t.file, t.start = f, start
+	}
+	_, err := fmt.Fprintf(t.file, "%d\t%s\n", at.UnixNano()/int64(time.Millisecond), record)
+	return err
+}
+
+// terminate ends the segment f with a newline when a power loss cut its
+// last line, so the next record starts on a line of its own.
+func terminate(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err = f.ReadAt(last, info.Size()-1); err != nil {
+		return err
+	}
+	if last[0] != '\n' {
+		_, err = f.Write([]byte{'\n'})
+	}
+	return err
+}
+
+// expire deletes the segments ending before the retention.
+func (t *tier) expire(now time.Time) error {
+	starts, err := t.segments()
+	if err != nil {
+		return err
+	}
+	for _, start := range starts {
+		if start.Add(t.span).Before(now.Add(-t.retention)) && !start.Equal(t.start) {
+			if err := os.Remove(t.path(start)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *tier) path(start time.Time) string {
+	return filepath.Join(t.dir, strconv.FormatInt(start.Unix(), 10)+".seg")
+}
+
+// segments returns the start of the segment files, sorted.
+func (t *tier) segments() ([]time.Time, error) {
+	files, err := ioutil.ReadDir(t.dir)
+	if err != nil {
+		return nil, err
+	}
+	var starts []time.Time
+	for _, f := range files {
+		name := f.Name()
+		if !strings.HasSuffix(name, ".seg") {
+			continue
+		}
+		sec, err := strconv.ParseInt(strings.TrimSuffix(name, ".seg"), 10, 64)
+		if err != nil {
+			continue
+		}
+		starts = append(starts, time.Unix(sec, 0))
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	return starts, nil
+}
+
+// read calls fn with the records of the segment at start. A line cut by a
+// power loss is skipped.
+func (t *tier) read(start time.Time, fn func(series string, p Point)) error {
+	data, err := ioutil.ReadFile(t.path(start))
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if series, p, ok := parseRecord(line); ok {
+			fn(series, p)
+		}
+	}
+	return nil
+}
+
+// parseRecord parses a raw "ms series value" or an aggregate "ms series min
+// max sum count" line.
+func parseRecord(line string) (string, Point, bool) {
+	f := strings.Split(line, "\t")
+	if len(f) != 3 && len(f) != 6 {
+		return "", Point{}, false
+	}
+	ms, err := strconv.ParseInt(f[0], 10, 64)
+	if err != nil {
+		return "", Point{}, false
+	}
+	var n [4]float64
+	for i, s := range f[2:] {
+		if n[i], err = strconv.ParseFloat(s, 64); err != nil {
+			return "", Point{}, false
+		}
+	}
+	p := Point{Time: time.Unix(0, ms*int64(time.Millisecond))}
+	if len(f) == 3 {
+		p.Min, p.Max, p.Sum, p.Count = n[0], n[0], n[0], 1
+	} else {
+		p.Min, p.Max, p.Sum, p.Count = n[0], n[1], n[2], int(n[3])
+		if p.Count <= 0 {
+			return "", Point{}, false
+		}
+	}
+	return f[1], p, true
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,181 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"pi/event"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// openAt opens a store in dir with the clock now.
+func openAt(t *testing.T, dir string, now *time.Time) *Store {
+	t.Helper()
+	s, err := open(&Options{
+		Path:       dir,
+		Segment:    time.Hour,
+		Retention:  2 * time.Hour,
+		Downsample: []Tier{{Resolution: time.Minute, Retention: 24 * time.Hour}},
+	}, func() time.Time { return *now })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	base := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	now := base
+	s := openAt(t, dir, &now)
+	// 5 minutes of samples every 10s, 0 to 29.
+	for i := 0; i < 30; i++ {
+		now = base.Add(time.Duration(i) * 10 * time.Second)
+		if err := s.Append("temp/temperature", now, float64(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	raw, err := s.Query("temp/temperature", base, base.Add(5*time.Minute), 0)
+	if err != nil || len(raw) != 30 || raw[29].Mean() != 29 {
+		t.Fatalf("raw %d points, %v", len(raw), err)
+	}
+	minutes, err := s.Query("temp/temperature", base, base.Add(5*time.Minute), time.Minute)
+	if err != nil || len(minutes) != 5 {
+		t.Fatalf("minutes %v, %v", minutes, err)
+	}
+	if p := minutes[1]; p.Count != 6 || p.Min != 6 || p.Max != 11 || p.Mean() != 8.5 {
+		t.Errorf("second minute %+v", p)
+	}
+	total, err := s.Aggregate("temp/temperature", base, base.Add(time.Hour))
+	if err != nil || total.Count != 30 || total.Min != 0 || total.Max != 29 || total.Mean() != 14.5 {
+		t.Errorf("aggregate %+v, %v", total, err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A power loss cut the last line of the raw segment.
+	f, err := os.OpenFile(filepath.Join(dir, "raw", "1792411200.seg"), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("1792411500000\ttemp/tem")
+	f.Close()
+
+	// After a restart 4 hours later the raw samples are gone from the
+	// query range, the minutes remain.
+	now = base.Add(4 * time.Hour)
+	s = openAt(t, dir, &now)
+	defer s.Close()
+	if series := s.Series(); len(series) != 1 || series[0] != "temp/temperature" {
+		t.Errorf("series %v", series)
+	}
+	old, err := s.Query("temp/temperature", base, base.Add(5*time.Minute), 0)
+	if err != nil || len(old) != 5 || old[4].Count != 6 {
+		t.Errorf("after restart %v, %v", old, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "raw", "1792411200.seg")); !os.IsNotExist(err) {
+		t.Errorf("expired segment: %v", err)
+	}
+
+	var csv, js bytes.Buffer
+	if err := WriteCSV(&csv, "temp/temperature", old[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if want := "time,series,min,max,mean,count\n2026-10-19T12:00:00Z,temp/temperature,0,5,2.5,6\n"; csv.String() != want {
+		t.Errorf("csv %q", csv.String())
+	}
+	if err := WriteJSON(&js, "temp/temperature", old[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(js.String(), `"points":[{"time":"2026-10-19T12:00:00Z","min":0,"max":5,"mean":2.5,"count":6}]`) {
+		t.Errorf("json %s", js.String())
+	}
+}
+
+func TestPartialLine(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	s := openAt(t, dir, &now)
+	if err := s.Append("temp/temperature", now, 20); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, "raw", "1792411200.seg"), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("1792411210000\ttemp/tem")
+	f.Close()
+
+	// The first record after the restart is kept.
+	now = now.Add(time.Minute)
+	s = openAt(t, dir, &now)
+	defer s.Close()
+	if err := s.Append("temp/temperature", now, 21); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := s.Query("temp/temperature", now.Add(-time.Hour), now.Add(time.Second), 0)
+	if err != nil || len(raw) != 2 || raw[1].Mean() != 21 {
+		t.Errorf("raw %v, %v", raw, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	now := time.Now()
+	s := openAt(t, dir, &now)
+	bus := event.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx, bus) }()
+	// The subscription races the first events.
+	deadline := time.Now().Add(5 * time.Second)
+	for len(s.Series()) == 0 && time.Now().Before(deadline) {
+		bus.Publish(event.Event{Device: "temp", Name: "temperature", Value: 21.5, Unit: "°C", Time: now})
+		bus.Publish(event.Event{Device: "led1", Name: "state", Value: 1, Time: now})
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+	if series := s.Series(); len(series) != 1 || series[0] != "temp/temperature" {
+		t.Errorf("series %v", series)
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	v := viper.New()
+	v.Set("history.path", "/var/lib/Pioneer600")
+	o, err := NewOptions(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Segment != time.Hour || len(o.Downsample) != 2 {
+		t.Errorf("options %+v", o)
+	}
+	v.Set("history.downsample", []map[string]interface{}{{"resolution": "1h", "retention": "1h"}, {"resolution": "1m", "retention": "1h"}})
+	if _, err = NewOptions(v); err == nil {
+		t.Error("decreasing resolutions accepted")
+	}
+}
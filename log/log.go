@@ -72,12 +72,30 @@ func NewLogger(o *Options) (*zap.SugaredLogger, error) {
 	zap.ReplaceGlobals(logger)
 
 	defaultLogger = logger.Sugar()
+	defaultLevel = level
 	return logger.Sugar(), err
 }
 
+var defaultLevel = zap.NewAtomicLevel()
+
+// SetLevel changes the level of the default logger while it runs.
+func SetLevel(text string) error {
+	return defaultLevel.UnmarshalText([]byte(text))
+}
+
+// Level returns the level of the default logger.
+func Level() string {
+	return defaultLevel.String()
+}
+
 var defaultLogger *zap.SugaredLogger
 
-// GetDefault
+// GetDefault, a no-op logger until NewLogger is called (eg. in tests).
 func Default() *zap.SugaredLogger {
+	if defaultLogger == nil {
+		return nopLogger
+	}
 	return defaultLogger
-}
\ No newline at end of file
+}
+
+var nopLogger = zap.NewNop().Sugar()
\ No newline at end of file
@@ -0,0 +1,281 @@
+// Package mqtt bridges the daemon to an MQTT broker: readings and states
+// are published, commands are subscribed to.
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"pi/control"
+	"pi/dev"
+	"pi/event"
+	"pi/log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/spf13/viper"
+)
+
+// Payloads of the status and state topics.
+const (
+	Online  = "online"
+	Offline = "offline"
+	On      = "ON"
+	Off     = "OFF"
+)
+
+// Options is the mqtt section of the configuration.
+type Options struct {
+	// Broker is the broker URL, eg. tcp://localhost:1883, empty disables
+	// the bridge.
+	Broker   string
+	ClientID string
+	Username string
+	Password string
+	// Prefix starts the topics, pioneer600 when empty.
+	Prefix string
+	// Interval is the publishing period of the readings.
+	Interval time.Duration
+	QoS      byte
+	// Topics replace the topic of a reading or state by device/name, eg.
+	// temp/temperature: home/kitchen/temperature.
+	Topics map[string]string
+	// Reconnect is the longest wait between reconnections.
+	Reconnect time.Duration
+	// Discovery is the Home Assistant discovery prefix, empty disables
+	// the discovery.
+	Discovery string
+}
+
+// NewOptions reads the mqtt section of the configuration.
+func NewOptions(v *viper.Viper) (*Options, error) {
+	o := &Options{
+		ClientID:  "pioneer600",
+		Prefix:    "pioneer600",
+		Interval:  30 * time.Second,
+		QoS:       1,
+		Reconnect: time.Minute,
+		Discovery: "homeassistant",
+	}
+	if err := v.UnmarshalKey("mqtt", o); err != nil {
+		return nil, err
+	}
+	if o.QoS > 2 {
+		return nil, fmt.Errorf("mqtt qos %d out of 0-2", o.QoS)
+	}
+	if o.Interval <= 0 {
+		return nil, fmt.Errorf("mqtt interval %v must be positive", o.Interval)
+	}
+	return o, nil
+}
+
+// Bridge publishes the events of the daemon and runs the commands
+// received on the broker:
+//
+//	{prefix}/status                online or offline, retained, the will
+//	{prefix}/{device}/{name}       latest readings every interval
+//	{prefix}/{device}/{name}       LED and joystick states, ON or OFF,
+//	                               retained, on change
+//	{prefix}/{device}/text         last OLED text, retained
+//	{prefix}/{device}/state/set    LED ON, OFF or TOGGLE
+//	{prefix}/{device}/tone/set     buzzer {"hz", "ms"}
+//	{prefix}/{device}/melody/set   buzzer {"bpm", "notes": [{"hz", "beats"}]}
+//	{prefix}/{device}/text/set     OLED text
+//
+// Readings are the events with a unit, states the ones without. The
+// entities of the devices set by SetDevices are announced on the Home
+// Assistant discovery topics.
+type Bridge struct {
+	opts Options
+	ctl  *control.Controller
+	bus  *event.Bus
+
+	mu      sync.Mutex
+	client  paho.Client
+	texts   map[string]string
+	devices []dev.DeviceConfig
+}
+
+// NewBridge creates a new Bridge of the devices of ctl and the events of
+// bus.
+func NewBridge(o *Options, ctl *control.Controller, bus *event.Bus) *Bridge {
+	return &Bridge{opts: *o, ctl: ctl, bus: bus, texts: make(map[string]string)}
+}
+
+// StatusTopic returns the topic of the online status.
+func (b *Bridge) StatusTopic() string {
+	return b.opts.Prefix + "/status"
+}
+
+// Topic returns the topic of the event name of device.
+func (b *Bridge) Topic(device, name string) string {
+	if t, ok := b.opts.Topics[device+"/"+name]; ok {
+		return t
+	}
+	return b.opts.Prefix + "/" + device + "/" + name
+}
+
+// Run connects to the broker, retrying until it answers, and bridges until
+// ctx is done. The status is then set offline.
+func (b *Bridge) Run(ctx context.Context) error {
+	logger := log.Default()
+	o := paho.NewClientOptions().
+		AddBroker(b.opts.Broker).
+		SetClientID(b.opts.ClientID).
+		SetUsername(b.opts.Username).
+		SetPassword(b.opts.Password).
+		SetCleanSession(true).
+		SetWill(b.StatusTopic(), Offline, b.opts.QoS, true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(b.opts.Reconnect).
+		SetMaxReconnectInterval(b.opts.Reconnect).
+		SetOnConnectHandler(b.connected).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			logger.Warnf("mqtt connection to %s lost: %v", b.opts.Broker, err)
+		})
+	client := paho.NewClient(o)
+	b.mu.Lock()
+	b.client = client
+	b.mu.Unlock()
+	// With ConnectRetry the token completes once connected.
+	client.Connect()
+
+	events, cancel := b.bus.Subscribe(64)
+	defer cancel()
+	t := time.NewTicker(b.opts.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if client.IsConnectionOpen() {
+				client.Publish(b.StatusTopic(), b.opts.QoS, true, Offline).WaitTimeout(time.Second)
+			}
+			client.Disconnect(250)
+			return ctx.Err()
+		case e := <-events:
+			if e.Unit == "" {
+				b.publishState(e)
+			}
+		case <-t.C:
+			for _, e := range b.bus.Snapshot() {
+				if e.Unit != "" {
+					b.publish(b.Topic(e.Device, e.Name), formatValue(e.Value), false)
+				}
+			}
+		}
+	}
+}
+
+// connected subscribes to the commands and publishes the discovery
+// configs, the status and the current states, the broker may have
+// restarted without them.
+func (b *Bridge) connected(client paho.Client) {
+	logger := log.Default()
+	logger.Info("mqtt connected to ", b.opts.Broker)
+	topic := b.opts.Prefix + "/+/+/set"
+	if t := client.Subscribe(topic, b.opts.QoS, b.command); t.Wait() && t.Error() != nil {
+		logger.Errorf("mqtt subscribe %s error: %v", topic, t.Error())
+	}
+	b.mu.Lock()
+	devices := b.devices
+	b.mu.Unlock()
+	if b.opts.Discovery != "" {
+		b.announce(devices)
+	}
+	b.publish(b.StatusTopic(), Online, true)
+	for _, e := range b.bus.Snapshot() {
+		if e.Unit == "" {
+			b.publishState(e)
+		}
+	}
+	b.mu.Lock()
+	texts := make(map[string]string, len(b.texts))
+	for device, text := range b.texts {
+		texts[device] = text
+	}
+	b.mu.Unlock()
+	for device, text := range texts {
+		b.publish(b.Topic(device, "text"), text, true)
+	}
+}
+
+// publish sends payload on topic if connected, messages are not queued
+// while the broker is away.
+func (b *Bridge) publish(topic, payload string, retained bool) {
+	b.mu.Lock()
+	client := b.client
+	b.mu.Unlock()
+	if client != nil && client.IsConnectionOpen() {
+		client.Publish(topic, b.opts.QoS, retained, payload)
+	}
+}
+
+func (b *Bridge) publishState(e event.Event) {
+	payload := Off
+	if e.Value != 0 {
+		payload = On
+	}
+	b.publish(b.Topic(e.Device, e.Name), payload, true)
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+type toneCommand struct {
+	Hz float64 `json:"hz"`
+	Ms int     `json:"ms"`
+}
+
+type melodyCommand struct {
+	BPM   float64 `json:"bpm"`
+	Notes []struct {
+		Hz    float64 `json:"hz"`
+		Beats float64 `json:"beats"`
+	} `json:"notes"`
+}
+
+// command runs a message of a {prefix}/{device}/{command}/set topic.
+func (b *Bridge) command(_ paho.Client, m paho.Message) {
+	parts := strings.Split(strings.TrimPrefix(m.Topic(), b.opts.Prefix+"/"), "/")
+	if len(parts) != 3 {
+		return
+	}
+	device, command, payload := parts[0], parts[1], m.Payload()
+	var err error
+	switch command {
+	case "state":
+		_, err = b.ctl.Switch(device, strings.ToLower(strings.TrimSpace(string(payload))), control.DefaultHold)
+	case "tone":
+		var c toneCommand
+		if err = json.Unmarshal(payload, &c); err == nil {
+			_, err = b.ctl.Play(device, []control.Note{{Hz: c.Hz, Duration: time.Duration(c.Ms) * time.Millisecond}})
+		}
+	case "melody":
+		var c melodyCommand
+		if err = json.Unmarshal(payload, &c); err == nil {
+			notes := make([]control.Note, len(c.Notes))
+			for i, n := range c.Notes {
+				notes[i] = control.Note{Hz: n.Hz, Duration: control.Beats(c.BPM, n.Beats)}
+			}
+			_, err = b.ctl.Play(device, notes)
+		}
+	case "text":
+		text := string(payload)
+		if err = b.ctl.ShowText(device, dev.PosTopLeft, text, control.DefaultHold); err == nil {
+			b.mu.Lock()
+			b.texts[device] = text
+			b.mu.Unlock()
+			b.publish(b.Topic(device, "text"), text, true)
+		}
+	default:
+		err = fmt.Errorf("unknown command %s", command)
+	}
+	if err != nil {
+		log.Default().Warnf("mqtt %s error: %v", m.Topic(), err)
+	}
+}
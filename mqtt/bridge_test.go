@@ -0,0 +1,220 @@
+package mqtt
+
+import (
+	"context"
+	"image"
+	"io/ioutil"
+	"pi/control"
+	"pi/dev"
+	"pi/event"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/spf13/viper"
+)
+
+// fakeDevice is a present dev.Device.
+type fakeDevice struct{ name string }
+
+func (d *fakeDevice) Name() string                   { return d.name }
+func (d *fakeDevice) Init(ctx context.Context) error { return nil }
+func (d *fakeDevice) Close() error                   { return nil }
+func (d *fakeDevice) Health() dev.Health             { return dev.Health{State: dev.HealthPresent} }
+
+type fakeLED struct {
+	fakeDevice
+	mu     sync.Mutex
+	status int
+}
+
+func (l *fakeLED) set(status int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.status = status
+	return nil
+}
+
+func (l *fakeLED) On() error     { return l.set(1) }
+func (l *fakeLED) Off() error    { return l.set(0) }
+func (l *fakeLED) Toggle() error { return l.set(l.Status() ^ 1) }
+func (l *fakeLED) Status() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.status
+}
+
+// fakeBuzzer sends the frequencies it plays to notes.
+type fakeBuzzer struct {
+	fakeDevice
+	notes chan float64
+}
+
+func (b *fakeBuzzer) Play(ctx context.Context, hz float64, d time.Duration) error {
+	b.notes <- hz
+	return nil
+}
+
+// observer collects the messages of a client subscribed to #.
+type observer struct {
+	client   paho.Client
+	messages chan paho.Message
+}
+
+func newObserver(t *testing.T, url string) *observer {
+	o := &observer{messages: make(chan paho.Message, 64)}
+	opts := paho.NewClientOptions().AddBroker(url).SetClientID("observer")
+	o.client = paho.NewClient(opts)
+	if tok := o.client.Connect(); tok.Wait() && tok.Error() != nil {
+		t.Fatal(tok.Error())
+	}
+	t.Cleanup(func() { o.client.Disconnect(0) })
+	if tok := o.client.Subscribe("#", 0, func(_ paho.Client, m paho.Message) {
+		o.messages <- m
+	}); tok.Wait() && tok.Error() != nil {
+		t.Fatal(tok.Error())
+	}
+	return o
+}
+
+// expect waits for payload on topic, skipping the other messages.
+func (o *observer) expect(t *testing.T, topic, payload string) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case m := <-o.messages:
+			if m.Topic() == topic && string(m.Payload()) == payload {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no %s on %s", payload, topic)
+		}
+	}
+}
+
+func (o *observer) send(t *testing.T, topic, payload string) {
+	if tok := o.client.Publish(topic, 1, false, payload); tok.Wait() && tok.Error() != nil {
+		t.Fatal(tok.Error())
+	}
+}
+
+func TestBridge(t *testing.T) {
+	b := newBroker(t)
+	led := &fakeLED{fakeDevice: fakeDevice{"led@BCM26"}}
+	buzzer := &fakeBuzzer{fakeDevice: fakeDevice{"pcf8574@/dev/i2c-1:0x20"}, notes: make(chan float64, 4)}
+	e, err := dev.NewEmulator(&dev.EmulatorOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.SetOutput(ioutil.Discard)
+	oled := dev.NewMirror(e)
+	health := dev.NewHealthRegistry()
+	health.Add("led1", led)
+	health.Add("buzzer", buzzer)
+	health.Add("oled", oled)
+	bus := event.New()
+	bus.Publish(event.Event{Device: "joystick", Name: "press", Value: 0})
+
+	o := newObserver(t, b.URL())
+	bridge := NewBridge(&Options{
+		Broker:    b.URL(),
+		ClientID:  "pioneer600",
+		Prefix:    "pioneer600",
+		Interval:  20 * time.Millisecond,
+		QoS:       1,
+		Topics:    map[string]string{"temp/temperature": "home/kitchen/temperature"},
+		Reconnect: 50 * time.Millisecond,
+	}, control.New(health, bus, nil), bus)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- bridge.Run(ctx) }()
+
+	o.expect(t, "pioneer600/status", Online)
+	o.expect(t, "pioneer600/joystick/press", Off)
+	bus.Publish(event.Event{Device: "temp", Name: "temperature", Value: 21.5, Unit: "°C"})
+	o.expect(t, "home/kitchen/temperature", "21.5")
+	bus.Publish(event.Event{Device: "rtc", Name: "drift", Value: -0.25, Unit: "s"})
+	o.expect(t, "pioneer600/rtc/drift", "-0.25")
+
+	o.send(t, "pioneer600/led1/state/set", "ON")
+	o.expect(t, "pioneer600/led1/state", On)
+	if led.Status() != 1 || b.retainedPayload("pioneer600/led1/state") != On {
+		t.Errorf("led %d, retained %q", led.Status(), b.retainedPayload("pioneer600/led1/state"))
+	}
+	o.send(t, "pioneer600/buzzer/tone/set", `{"hz": 440, "ms": 100}`)
+	select {
+	case hz := <-buzzer.notes:
+		if hz != 440 {
+			t.Errorf("tone %v Hz", hz)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("tone not played")
+	}
+	o.send(t, "pioneer600/oled/text/set", "hello")
+	o.expect(t, "pioneer600/oled/text", "hello")
+	if frame := oled.Snapshot(); frame.Bounds() == (image.Rectangle{}) || !lit(frame) {
+		t.Error("text not drawn")
+	}
+	// Text over control.MaxText is refused, not retained.
+	o.send(t, "pioneer600/oled/text/set", strings.Repeat("x", control.MaxText+1))
+	o.send(t, "pioneer600/oled/text/set", "bye")
+	for published := ""; published != "bye"; {
+		select {
+		case m := <-o.messages:
+			if m.Topic() != "pioneer600/oled/text" {
+				continue
+			}
+			if published = string(m.Payload()); published != "bye" {
+				t.Fatalf("text %q published", published)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("no bye on pioneer600/oled/text")
+		}
+	}
+
+	// A network failure sends the will, the bridge comes back online.
+	b.drop("pioneer600")
+	o.expect(t, "pioneer600/status", Offline)
+	o.expect(t, "pioneer600/status", Online)
+	o.expect(t, "pioneer600/oled/text", "bye")
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run() = %v", err)
+	}
+	if s := b.retainedPayload("pioneer600/status"); s != Offline {
+		t.Errorf("status after shutdown %q", s)
+	}
+}
+
+func lit(frame image.Image) bool {
+	r := frame.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if v, _, _, _ := frame.At(x, y).RGBA(); v > 0x8000 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestNewOptions(t *testing.T) {
+	v := viper.New()
+	v.Set("mqtt.broker", "tcp://localhost:1883")
+	v.Set("mqtt.interval", "10s")
+	o, err := NewOptions(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Interval != 10*time.Second || o.Prefix != "pioneer600" || o.QoS != 1 {
+		t.Errorf("options %+v", o)
+	}
+	v.Set("mqtt.qos", 3)
+	if _, err = NewOptions(v); err == nil {
+		t.Error("qos 3 accepted")
+	}
+}
@@ -0,0 +1,207 @@
+package mqtt
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+)
+
+// broker is an in-process MQTT 3.1.1 broker with retained messages and
+// wills, delivering at QoS 0.
+type broker struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	conns    map[*brokerConn]struct{}
+	retained map[string]*packets.PublishPacket
+}
+
+type brokerConn struct {
+	net.Conn
+	id   string
+	mu   sync.Mutex
+	subs []string
+	will *packets.PublishPacket
+}
+
+func newBroker(t *testing.T) *broker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &broker{
+		ln:       ln,
+		conns:    make(map[*brokerConn]struct{}),
+		retained: make(map[string]*packets.PublishPacket),
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(&brokerConn{Conn: conn})
+		}
+	}()
+	t.Cleanup(b.close)
+	return b
+}
+
+// URL returns the broker URL for the clients.
+func (b *broker) URL() string {
+	return "tcp://" + b.ln.Addr().String()
+}
+
+func (b *broker) close() {
+	b.ln.Close()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for c := range b.conns {
+		c.Close()
+	}
+}
+
+// drop closes the connection of client id without a DISCONNECT, as a
+// network failure.
+func (b *broker) drop(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for c := range b.conns {
+		if c.id == id {
+			c.Close()
+		}
+	}
+}
+
+// retainedPayload returns the retained message of topic.
+func (b *broker) retainedPayload(topic string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if p, ok := b.retained[topic]; ok {
+		return string(p.Payload)
+	}
+	return ""
+}
+
+func (c *brokerConn) write(p packets.ControlPacket) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return p.Write(c.Conn)
+}
+
+func (b *broker) serve(c *brokerConn) {
+	defer c.Close()
+	p, err := packets.ReadPacket(c)
+	if err != nil {
+		return
+	}
+	connect, ok := p.(*packets.ConnectPacket)
+	if !ok {
+		return
+	}
+	c.id = connect.ClientIdentifier
+	if connect.WillFlag {
+		will := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+		will.TopicName, will.Payload, will.Retain = connect.WillTopic, connect.WillMessage, connect.WillRetain
+		c.will = will
+	}
+	b.mu.Lock()
+	b.conns[c] = struct{}{}
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.conns, c)
+		b.mu.Unlock()
+		if c.will != nil {
+			b.route(c.will)
+		}
+	}()
+	if err = c.write(packets.NewControlPacket(packets.Connack)); err != nil {
+		return
+	}
+	for {
+		p, err := packets.ReadPacket(c)
+		if err != nil {
+			return
+		}
+		switch p := p.(type) {
+		case *packets.PublishPacket:
+			if p.Qos > 0 {
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				c.write(ack)
+			}
+			b.route(p)
+		case *packets.SubscribePacket:
+			ack := packets.NewControlPacket(packets.Suback).(*packets.SubackPacket)
+			ack.MessageID = p.MessageID
+			ack.ReturnCodes = make([]byte, len(p.Topics))
+			b.mu.Lock()
+			c.subs = append(c.subs, p.Topics...)
+			var retained []*packets.PublishPacket
+			for topic, m := range b.retained {
+				for _, filter := range p.Topics {
+					if match(filter, topic) {
+						retained = append(retained, m)
+						break
+					}
+				}
+			}
+			b.mu.Unlock()
+			c.write(ack)
+			for _, m := range retained {
+				c.write(m)
+			}
+		case *packets.PingreqPacket:
+			c.write(packets.NewControlPacket(packets.Pingresp))
+		case *packets.DisconnectPacket:
+			c.will = nil
+			return
+		}
+	}
+}
+
+// route stores p when retained and sends it to the subscribers.
+func (b *broker) route(p *packets.PublishPacket) {
+	m := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+	m.TopicName, m.Payload = p.TopicName, p.Payload
+	b.mu.Lock()
+	if p.Retain {
+		if len(p.Payload) == 0 {
+			delete(b.retained, p.TopicName)
+		} else {
+			r := *m
+			r.Retain = true
+			b.retained[p.TopicName] = &r
+		}
+	}
+	var subscribers []*brokerConn
+	for c := range b.conns {
+		for _, filter := range c.subs {
+			if match(filter, p.TopicName) {
+				subscribers = append(subscribers, c)
+				break
+			}
+		}
+	}
+	b.mu.Unlock()
+	for _, c := range subscribers {
+		c.write(m)
+	}
+}
+
+// match reports whether topic matches filter with + and # wildcards.
+func match(filter, topic string) bool {
+	f, t := strings.Split(filter, "/"), strings.Split(topic, "/")
+	for i, level := range f {
+		if level == "#" {
+			return true
+		}
+		if i >= len(t) || (level != "+" && level != t[i]) {
+			return false
+		}
+	}
+	return len(f) == len(t)
+}
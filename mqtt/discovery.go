@@ -0,0 +1,162 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"pi/dev"
+	"regexp"
+)
+
+// discoveryDevice groups the entities of the board in Home Assistant.
+type discoveryDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+}
+
+// discoveryConfig is the payload of a Home Assistant discovery topic.
+type discoveryConfig struct {
+	Name                string          `json:"name"`
+	UniqueID            string          `json:"unique_id"`
+	StateTopic          string          `json:"state_topic,omitempty"`
+	CommandTopic        string          `json:"command_topic,omitempty"`
+	AvailabilityTopic   string          `json:"availability_topic"`
+	PayloadAvailable    string          `json:"payload_available"`
+	PayloadNotAvailable string          `json:"payload_not_available"`
+	PayloadOn           string          `json:"payload_on,omitempty"`
+	PayloadOff          string          `json:"payload_off,omitempty"`
+	PayloadPress        string          `json:"payload_press,omitempty"`
+	DeviceClass         string          `json:"device_class,omitempty"`
+	StateClass          string          `json:"state_class,omitempty"`
+	UnitOfMeasurement   string          `json:"unit_of_measurement,omitempty"`
+	EntityCategory      string          `json:"entity_category,omitempty"`
+	Icon                string          `json:"icon,omitempty"`
+	Max                 int             `json:"max,omitempty"`
+	Device              discoveryDevice `json:"device"`
+}
+
+// entity is a discovery config and its topic.
+type entity struct {
+	topic  string
+	config discoveryConfig
+}
+
+// Beep is the tone/set payload of the buzzer button.
+const Beep = `{"hz":880,"ms":200}`
+
+var unsafeID = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// entities returns the Home Assistant entities of the devices:
+//
+//	ds18b20, bmp180  temperature and pressure sensors
+//	ds3231           drift sensor, diagnostic
+//	led, pcf8574-led switches
+//	buzzer           button playing Beep
+//	ssd1306          text
+//	joystick         binary sensors of the buttons
+func (b *Bridge) entities(configs []dev.DeviceConfig) []entity {
+	node := unsafeID.ReplaceAllString(b.opts.ClientID, "_")
+	board := discoveryDevice{
+		Identifiers:  []string{node},
+		Name:         b.opts.ClientID,
+		Manufacturer: "Waveshare",
+		Model:        "Pioneer600",
+	}
+	var entities []entity
+	add := func(component, device, name string, c discoveryConfig) {
+		id := node + "_" + unsafeID.ReplaceAllString(device+"_"+name, "_")
+		c.Name = device + " " + name
+		c.UniqueID = id
+		c.AvailabilityTopic = b.StatusTopic()
+		c.PayloadAvailable, c.PayloadNotAvailable = Online, Offline
+		c.Device = board
+		entities = append(entities, entity{
+			topic:  b.opts.Discovery + "/" + component + "/" + node + "/" + id + "/config",
+			config: c,
+		})
+	}
+	sensor := func(cfg dev.DeviceConfig, name, class, unit, category string) {
+		add("sensor", cfg.Name, name, discoveryConfig{
+			StateTopic:        b.Topic(cfg.Name, name),
+			DeviceClass:       class,
+			StateClass:        "measurement",
+			UnitOfMeasurement: unit,
+			EntityCategory:    category,
+		})
+	}
+	for _, cfg := range configs {
+		switch cfg.Type {
+		case dev.TypeDS18B20:
+			sensor(cfg, "temperature", "temperature", "°C", "")
+		case dev.TypeBMP180:
+			sensor(cfg, "temperature", "temperature", "°C", "")
+			sensor(cfg, "pressure", "pressure", "Pa", "")
+		case dev.TypeDS3231:
+			sensor(cfg, "drift", "duration", "s", "diagnostic")
+		case dev.TypeLED, dev.TypePCF8574LED:
+			add("switch", cfg.Name, "state", discoveryConfig{
+				StateTopic:   b.Topic(cfg.Name, "state"),
+				CommandTopic: b.opts.Prefix + "/" + cfg.Name + "/state/set",
+				PayloadOn:    On,
+				PayloadOff:   Off,
+				Icon:         "mdi:led-on",
+			})
+		case dev.TypeBuzzer:
+			add("button", cfg.Name, "beep", discoveryConfig{
+				CommandTopic: b.opts.Prefix + "/" + cfg.Name + "/tone/set",
+				PayloadPress: Beep,
+				Icon:         "mdi:bullhorn",
+			})
+		case dev.TypeSSD1306:
+			add("text", cfg.Name, "text", discoveryConfig{
+				StateTopic:   b.Topic(cfg.Name, "text"),
+				CommandTopic: b.opts.Prefix + "/" + cfg.Name + "/text/set",
+				Max:          64,
+				Icon:         "mdi:monitor",
+			})
+		case dev.TypeJoystick:
+			for _, name := range []string{"up", "down", "left", "right", "press"} {
+				add("binary_sensor", cfg.Name, name, discoveryConfig{
+					StateTopic: b.Topic(cfg.Name, name),
+					PayloadOn:  On,
+					PayloadOff: Off,
+					Icon:       "mdi:gamepad",
+				})
+			}
+		}
+	}
+	return entities
+}
+
+// SetDevices sets the devices announced to Home Assistant, the entities of
+// the devices no longer there are removed.
+func (b *Bridge) SetDevices(configs []dev.DeviceConfig) {
+	b.mu.Lock()
+	old := b.devices
+	b.devices = configs
+	b.mu.Unlock()
+	if b.opts.Discovery == "" {
+		return
+	}
+	current := b.announce(configs)
+	for _, e := range b.entities(old) {
+		if !current[e.topic] {
+			b.publish(e.topic, "", true)
+		}
+	}
+}
+
+// announce publishes the retained discovery configs of the devices and
+// returns their topics.
+func (b *Bridge) announce(configs []dev.DeviceConfig) map[string]bool {
+	topics := make(map[string]bool)
+	for _, e := range b.entities(configs) {
+		payload, err := json.Marshal(e.config)
+		if err != nil {
+			continue
+		}
+		b.publish(e.topic, string(payload), true)
+		topics[e.topic] = true
+	}
+	return topics
+}
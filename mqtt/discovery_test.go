@@ -0,0 +1,89 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"pi/control"
+	"pi/dev"
+	"pi/event"
+	"testing"
+	"time"
+)
+
+func TestDiscovery(t *testing.T) {
+	b := newBroker(t)
+	bus := event.New()
+	bridge := NewBridge(&Options{
+		Broker:    b.URL(),
+		ClientID:  "pioneer600",
+		Prefix:    "pioneer600",
+		Interval:  time.Second,
+		QoS:       1,
+		Topics:    map[string]string{"temp/temperature": "home/kitchen/temperature"},
+		Reconnect: 50 * time.Millisecond,
+		Discovery: "homeassistant",
+	}, control.New(dev.NewHealthRegistry(), bus, nil), bus)
+	bridge.SetDevices([]dev.DeviceConfig{
+		{Name: "led1", Type: dev.TypeLED},
+		{Name: "led2", Type: dev.TypePCF8574LED},
+		{Name: "buzzer", Type: dev.TypeBuzzer},
+		{Name: "temp", Type: dev.TypeDS18B20},
+		{Name: "oled", Type: dev.TypeSSD1306},
+		{Name: "joystick", Type: dev.TypeJoystick},
+	})
+
+	o := newObserver(t, b.URL())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bridge.Run(ctx)
+	o.expect(t, "pioneer600/status", Online)
+
+	config := func(topic string) (c discoveryConfig, ok bool) {
+		payload := b.retainedPayload(topic)
+		if payload == "" {
+			return c, false
+		}
+		if err := json.Unmarshal([]byte(payload), &c); err != nil {
+			t.Fatalf("%s: %v", topic, err)
+		}
+		return c, true
+	}
+	for topic, want := range map[string]discoveryConfig{
+		"homeassistant/sensor/pioneer600/pioneer600_temp_temperature/config": {
+			StateTopic: "home/kitchen/temperature", DeviceClass: "temperature", UnitOfMeasurement: "°C",
+		},
+		"homeassistant/switch/pioneer600/pioneer600_led2_state/config": {
+			StateTopic: "pioneer600/led2/state", CommandTopic: "pioneer600/led2/state/set", PayloadOn: On,
+		},
+		"homeassistant/button/pioneer600/pioneer600_buzzer_beep/config": {
+			CommandTopic: "pioneer600/buzzer/tone/set", PayloadPress: Beep,
+		},
+		"homeassistant/text/pioneer600/pioneer600_oled_text/config": {
+			StateTopic: "pioneer600/oled/text", CommandTopic: "pioneer600/oled/text/set",
+		},
+		"homeassistant/binary_sensor/pioneer600/pioneer600_joystick_press/config": {
+			StateTopic: "pioneer600/joystick/press", PayloadOn: On,
+		},
+	} {
+		c, ok := config(topic)
+		if !ok {
+			t.Errorf("no %s", topic)
+			continue
+		}
+		if c.StateTopic != want.StateTopic || c.CommandTopic != want.CommandTopic ||
+			c.DeviceClass != want.DeviceClass || c.UnitOfMeasurement != want.UnitOfMeasurement ||
+			c.PayloadOn != want.PayloadOn || c.PayloadPress != want.PayloadPress {
+			t.Errorf("%s = %+v", topic, c)
+		}
+		if c.AvailabilityTopic != "pioneer600/status" || c.Device.Model != "Pioneer600" || len(c.Device.Identifiers) != 1 {
+			t.Errorf("%s availability %q, device %+v", topic, c.AvailabilityTopic, c.Device)
+		}
+	}
+
+	// A device removed by a reload removes its entities.
+	bridge.SetDevices([]dev.DeviceConfig{{Name: "led1", Type: dev.TypeLED}})
+	o.expect(t, "homeassistant/sensor/pioneer600/pioneer600_temp_temperature/config", "")
+	if _, ok := config("homeassistant/switch/pioneer600/pioneer600_led1_state/config"); !ok {
+		t.Error("led1 removed")
+	}
+}
@@ -0,0 +1,5 @@
+// Package pb is the code generated from pioneer600.proto, with protoc-gen-go
+// and protoc-gen-go-grpc.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative pioneer600.proto
@@ -0,0 +1,1764 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        (unknown)
+// source: pioneer600.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Position int32
+
+const (
+	Position_TOP_LEFT      Position = 0
+	Position_TOP_CENTER    Position = 1
+	Position_TOP_RIGHT     Position = 2
+	Position_BOTTOM_LEFT   Position = 3
+	Position_BOTTOM_CENTER Position = 4
+	Position_BOTTOM_RIGHT  Position = 5
+)
+
+// Enum value maps for Position.
+var (
+	Position_name = map[int32]string{
+		0: "TOP_LEFT",
+		1: "TOP_CENTER",
+		2: "TOP_RIGHT",
+		3: "BOTTOM_LEFT",
+		4: "BOTTOM_CENTER",
+		5: "BOTTOM_RIGHT",
+	}
+	Position_value = map[string]int32{
+		"TOP_LEFT":      0,
+		"TOP_CENTER":    1,
+		"TOP_RIGHT":     2,
+		"BOTTOM_LEFT":   3,
+		"BOTTOM_CENTER": 4,
+		"BOTTOM_RIGHT":  5,
+	}
+)
+
+func (x Position) Enum() *Position {
+	p := new(Position)
+	*p = x
+	return p
+}
+
+func (x Position) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Position) Descriptor() protoreflect.EnumDescriptor {
+	return file_pioneer600_proto_enumTypes[0].Descriptor()
+}
+
+func (Position) Type() protoreflect.EnumType {
+	return &file_pioneer600_proto_enumTypes[0]
+}
+
+func (x Position) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Position.Descriptor instead.
+func (Position) EnumDescriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{0}
+}
+
+type SetStateRequest_State int32
+
+const (
+	SetStateRequest_STATE_UNSPECIFIED SetStateRequest_State = 0
+	SetStateRequest_ON                SetStateRequest_State = 1
+	SetStateRequest_OFF               SetStateRequest_State = 2
+	SetStateRequest_TOGGLE            SetStateRequest_State = 3
+)
+
+// Enum value maps for SetStateRequest_State.
+var (
+	SetStateRequest_State_name = map[int32]string{
+		0: "STATE_UNSPECIFIED",
+		1: "ON",
+		2: "OFF",
+		3: "TOGGLE",
+	}
+	SetStateRequest_State_value = map[string]int32{
+		"STATE_UNSPECIFIED": 0,
+		"ON":                1,
+		"OFF":               2,
+		"TOGGLE":            3,
+	}
+)
+
+func (x SetStateRequest_State) Enum() *SetStateRequest_State {
+	p := new(SetStateRequest_State)
+	*p = x
+	return p
+}
+
+func (x SetStateRequest_State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SetStateRequest_State) Descriptor() protoreflect.EnumDescriptor {
+	return file_pioneer600_proto_enumTypes[1].Descriptor()
+}
+
+func (SetStateRequest_State) Type() protoreflect.EnumType {
+	return &file_pioneer600_proto_enumTypes[1]
+}
+
+func (x SetStateRequest_State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SetStateRequest_State.Descriptor instead.
+func (SetStateRequest_State) EnumDescriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{5, 0}
+}
+
+type ShowImageRequest_Dither int32
+
+const (
+	ShowImageRequest_THRESHOLD       ShowImageRequest_Dither = 0
+	ShowImageRequest_FLOYD_STEINBERG ShowImageRequest_Dither = 1
+	ShowImageRequest_BAYER           ShowImageRequest_Dither = 2
+)
+
+// Enum value maps for ShowImageRequest_Dither.
+var (
+	ShowImageRequest_Dither_name = map[int32]string{
+		0: "THRESHOLD",
+		1: "FLOYD_STEINBERG",
+		2: "BAYER",
+	}
+	ShowImageRequest_Dither_value = map[string]int32{
+		"THRESHOLD":       0,
+		"FLOYD_STEINBERG": 1,
+		"BAYER":           2,
+	}
+)
+
+func (x ShowImageRequest_Dither) Enum() *ShowImageRequest_Dither {
+	p := new(ShowImageRequest_Dither)
+	*p = x
+	return p
+}
+
+func (x ShowImageRequest_Dither) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ShowImageRequest_Dither) Descriptor() protoreflect.EnumDescriptor {
+	return file_pioneer600_proto_enumTypes[2].Descriptor()
+}
+
+func (ShowImageRequest_Dither) Type() protoreflect.EnumType {
+	return &file_pioneer600_proto_enumTypes[2]
+}
+
+func (x ShowImageRequest_Dither) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ShowImageRequest_Dither.Descriptor instead.
+func (ShowImageRequest_Dither) EnumDescriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{15, 0}
+}
+
+type ShowImageRequest_Scale int32
+
+const (
+	ShowImageRequest_FIT     ShowImageRequest_Scale = 0
+	ShowImageRequest_FILL    ShowImageRequest_Scale = 1
+	ShowImageRequest_CROP    ShowImageRequest_Scale = 2
+	ShowImageRequest_STRETCH ShowImageRequest_Scale = 3
+)
+
+// Enum value maps for ShowImageRequest_Scale.
+var (
+	ShowImageRequest_Scale_name = map[int32]string{
+		0: "FIT",
+		1: "FILL",
+		2: "CROP",
+		3: "STRETCH",
+	}
+	ShowImageRequest_Scale_value = map[string]int32{
+		"FIT":     0,
+		"FILL":    1,
+		"CROP":    2,
+		"STRETCH": 3,
+	}
+)
+
+func (x ShowImageRequest_Scale) Enum() *ShowImageRequest_Scale {
+	p := new(ShowImageRequest_Scale)
+	*p = x
+	return p
+}
+
+func (x ShowImageRequest_Scale) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ShowImageRequest_Scale) Descriptor() protoreflect.EnumDescriptor {
+	return file_pioneer600_proto_enumTypes[3].Descriptor()
+}
+
+func (ShowImageRequest_Scale) Type() protoreflect.EnumType {
+	return &file_pioneer600_proto_enumTypes[3]
+}
+
+func (x ShowImageRequest_Scale) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ShowImageRequest_Scale.Descriptor instead.
+func (ShowImageRequest_Scale) EnumDescriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{15, 1}
+}
+
+// Event is a reading or a state of a device.
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Device string  `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	Name   string  `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Value  float64 `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	// unit is empty for a state, 1 or 0.
+	Unit string                 `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+	Time *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Event) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *Event) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Event) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Event) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Event) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+type Device struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name is the name in the devices section.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// driver is empty when the device could not be created.
+	Driver string `protobuf:"bytes,2,opt,name=driver,proto3" json:"driver,omitempty"`
+	// state is unknown, present, degraded or failed.
+	State string `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	// error is the last error, empty once the device answers again.
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// since is the time of the last state change.
+	Since *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=since,proto3" json:"since,omitempty"`
+	// events are the latest events, on GetDevice only.
+	Events []*Event `protobuf:"bytes,6,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Device) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Device) GetDriver() string {
+	if x != nil {
+		return x.Driver
+	}
+	return ""
+}
+
+func (x *Device) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Device) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Device) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *Device) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type ListDevicesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListDevicesRequest) Reset() {
+	*x = ListDevicesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesRequest) ProtoMessage() {}
+
+func (x *ListDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesRequest.ProtoReflect.Descriptor instead.
+func (*ListDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{2}
+}
+
+type ListDevicesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Devices []*Device `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+}
+
+func (x *ListDevicesResponse) Reset() {
+	*x = ListDevicesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesResponse) ProtoMessage() {}
+
+func (x *ListDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesResponse.ProtoReflect.Descriptor instead.
+func (*ListDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListDevicesResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+type GetDeviceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetDeviceRequest) Reset() {
+	*x = GetDeviceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceRequest) ProtoMessage() {}
+
+func (x *GetDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceRequest.ProtoReflect.Descriptor instead.
+func (*GetDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetDeviceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SetStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string                `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	State SetStateRequest_State `protobuf:"varint,2,opt,name=state,proto3,enum=pioneer600.v1.SetStateRequest_State" json:"state,omitempty"`
+	// hold is how long the daemon leaves the LED alone, 1m when unset.
+	Hold *durationpb.Duration `protobuf:"bytes,3,opt,name=hold,proto3" json:"hold,omitempty"`
+}
+
+func (x *SetStateRequest) Reset() {
+	*x = SetStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetStateRequest) ProtoMessage() {}
+
+func (x *SetStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetStateRequest.ProtoReflect.Descriptor instead.
+func (*SetStateRequest) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SetStateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetStateRequest) GetState() SetStateRequest_State {
+	if x != nil {
+		return x.State
+	}
+	return SetStateRequest_STATE_UNSPECIFIED
+}
+
+func (x *SetStateRequest) GetHold() *durationpb.Duration {
+	if x != nil {
+		return x.Hold
+	}
+	return nil
+}
+
+type SetStateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	On bool `protobuf:"varint,1,opt,name=on,proto3" json:"on,omitempty"`
+}
+
+func (x *SetStateResponse) Reset() {
+	*x = SetStateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetStateResponse) ProtoMessage() {}
+
+func (x *SetStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetStateResponse.ProtoReflect.Descriptor instead.
+func (*SetStateResponse) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetStateResponse) GetOn() bool {
+	if x != nil {
+		return x.On
+	}
+	return false
+}
+
+type PlayToneRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Hz       float64              `protobuf:"fixed64,2,opt,name=hz,proto3" json:"hz,omitempty"`
+	Duration *durationpb.Duration `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+}
+
+func (x *PlayToneRequest) Reset() {
+	*x = PlayToneRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayToneRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayToneRequest) ProtoMessage() {}
+
+func (x *PlayToneRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayToneRequest.ProtoReflect.Descriptor instead.
+func (*PlayToneRequest) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PlayToneRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PlayToneRequest) GetHz() float64 {
+	if x != nil {
+		return x.Hz
+	}
+	return 0
+}
+
+func (x *PlayToneRequest) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+type Note struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// hz is 0 for a rest.
+	Hz    float64 `protobuf:"fixed64,1,opt,name=hz,proto3" json:"hz,omitempty"`
+	Beats float64 `protobuf:"fixed64,2,opt,name=beats,proto3" json:"beats,omitempty"`
+}
+
+func (x *Note) Reset() {
+	*x = Note{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Note) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Note) ProtoMessage() {}
+
+func (x *Note) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Note.ProtoReflect.Descriptor instead.
+func (*Note) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Note) GetHz() float64 {
+	if x != nil {
+		return x.Hz
+	}
+	return 0
+}
+
+func (x *Note) GetBeats() float64 {
+	if x != nil {
+		return x.Beats
+	}
+	return 0
+}
+
+type PlayMelodyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string  `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Notes []*Note `protobuf:"bytes,2,rep,name=notes,proto3" json:"notes,omitempty"`
+	// bpm is 96 when 0.
+	Bpm float64 `protobuf:"fixed64,3,opt,name=bpm,proto3" json:"bpm,omitempty"`
+}
+
+func (x *PlayMelodyRequest) Reset() {
+	*x = PlayMelodyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayMelodyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayMelodyRequest) ProtoMessage() {}
+
+func (x *PlayMelodyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayMelodyRequest.ProtoReflect.Descriptor instead.
+func (*PlayMelodyRequest) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PlayMelodyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PlayMelodyRequest) GetNotes() []*Note {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+func (x *PlayMelodyRequest) GetBpm() float64 {
+	if x != nil {
+		return x.Bpm
+	}
+	return 0
+}
+
+type PlayResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Duration *durationpb.Duration `protobuf:"bytes,1,opt,name=duration,proto3" json:"duration,omitempty"`
+}
+
+func (x *PlayResponse) Reset() {
+	*x = PlayResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayResponse) ProtoMessage() {}
+
+func (x *PlayResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayResponse.ProtoReflect.Descriptor instead.
+func (*PlayResponse) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PlayResponse) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+type StopSoundRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *StopSoundRequest) Reset() {
+	*x = StopSoundRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StopSoundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopSoundRequest) ProtoMessage() {}
+
+func (x *StopSoundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopSoundRequest.ProtoReflect.Descriptor instead.
+func (*StopSoundRequest) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *StopSoundRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type StopSoundResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *StopSoundResponse) Reset() {
+	*x = StopSoundResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StopSoundResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopSoundResponse) ProtoMessage() {}
+
+func (x *StopSoundResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopSoundResponse.ProtoReflect.Descriptor instead.
+func (*StopSoundResponse) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{12}
+}
+
+type ShowTextRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Text     string               `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Position Position             `protobuf:"varint,3,opt,name=position,proto3,enum=pioneer600.v1.Position" json:"position,omitempty"`
+	Hold     *durationpb.Duration `protobuf:"bytes,4,opt,name=hold,proto3" json:"hold,omitempty"`
+}
+
+func (x *ShowTextRequest) Reset() {
+	*x = ShowTextRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShowTextRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShowTextRequest) ProtoMessage() {}
+
+func (x *ShowTextRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShowTextRequest.ProtoReflect.Descriptor instead.
+func (*ShowTextRequest) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ShowTextRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ShowTextRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ShowTextRequest) GetPosition() Position {
+	if x != nil {
+		return x.Position
+	}
+	return Position_TOP_LEFT
+}
+
+func (x *ShowTextRequest) GetHold() *durationpb.Duration {
+	if x != nil {
+		return x.Hold
+	}
+	return nil
+}
+
+type ShowTextResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ShowTextResponse) Reset() {
+	*x = ShowTextResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShowTextResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShowTextResponse) ProtoMessage() {}
+
+func (x *ShowTextResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShowTextResponse.ProtoReflect.Descriptor instead.
+func (*ShowTextResponse) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{14}
+}
+
+type ShowImageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// image is at most 1MiB.
+	Image  []byte                  `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	Dither ShowImageRequest_Dither `protobuf:"varint,3,opt,name=dither,proto3,enum=pioneer600.v1.ShowImageRequest_Dither" json:"dither,omitempty"`
+	Scale  ShowImageRequest_Scale  `protobuf:"varint,4,opt,name=scale,proto3,enum=pioneer600.v1.ShowImageRequest_Scale" json:"scale,omitempty"`
+	// threshold is the cutoff of THRESHOLD, 1-255, 128 when unset.
+	Threshold *uint32              `protobuf:"varint,5,opt,name=threshold,proto3,oneof" json:"threshold,omitempty"`
+	Invert    bool                 `protobuf:"varint,6,opt,name=invert,proto3" json:"invert,omitempty"`
+	Hold      *durationpb.Duration `protobuf:"bytes,7,opt,name=hold,proto3" json:"hold,omitempty"`
+}
+
+func (x *ShowImageRequest) Reset() {
+	*x = ShowImageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShowImageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShowImageRequest) ProtoMessage() {}
+
+func (x *ShowImageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShowImageRequest.ProtoReflect.Descriptor instead.
+func (*ShowImageRequest) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ShowImageRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ShowImageRequest) GetImage() []byte {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *ShowImageRequest) GetDither() ShowImageRequest_Dither {
+	if x != nil {
+		return x.Dither
+	}
+	return ShowImageRequest_THRESHOLD
+}
+
+func (x *ShowImageRequest) GetScale() ShowImageRequest_Scale {
+	if x != nil {
+		return x.Scale
+	}
+	return ShowImageRequest_FIT
+}
+
+func (x *ShowImageRequest) GetThreshold() uint32 {
+	if x != nil && x.Threshold != nil {
+		return *x.Threshold
+	}
+	return 0
+}
+
+func (x *ShowImageRequest) GetInvert() bool {
+	if x != nil {
+		return x.Invert
+	}
+	return false
+}
+
+func (x *ShowImageRequest) GetHold() *durationpb.Duration {
+	if x != nil {
+		return x.Hold
+	}
+	return nil
+}
+
+type ShowImageResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ShowImageResponse) Reset() {
+	*x = ShowImageResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShowImageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShowImageResponse) ProtoMessage() {}
+
+func (x *ShowImageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShowImageResponse.ProtoReflect.Descriptor instead.
+func (*ShowImageResponse) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{16}
+}
+
+type StreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// devices are the names to send, all when empty.
+	Devices []string `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+}
+
+func (x *StreamRequest) Reset() {
+	*x = StreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pioneer600_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRequest) ProtoMessage() {}
+
+func (x *StreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pioneer600_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRequest.ProtoReflect.Descriptor instead.
+func (*StreamRequest) Descriptor() ([]byte, []int) {
+	return file_pioneer600_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *StreamRequest) GetDevices() []string {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+var File_pioneer600_proto protoreflect.FileDescriptor
+
+var file_pioneer600_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x0d, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76,
+	0x31, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0x8d, 0x01, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e,
+	0x69, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69,
+	0x6d, 0x65, 0x22, 0xc0, 0x01, 0x0a, 0x06, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65,
+	0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x14, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x46, 0x0a, 0x13, 0x4c,
+	0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30,
+	0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x07, 0x64, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x22, 0x26, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xcd, 0x01, 0x0a, 0x0f,
+	0x53, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x3a, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x24, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x2d, 0x0a, 0x04, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x68, 0x6f, 0x6c, 0x64, 0x22, 0x3b,
+	0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x54, 0x41, 0x54, 0x45,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x06,
+	0x0a, 0x02, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x4f, 0x46, 0x46, 0x10, 0x02, 0x12,
+	0x0a, 0x0a, 0x06, 0x54, 0x4f, 0x47, 0x47, 0x4c, 0x45, 0x10, 0x03, 0x22, 0x22, 0x0a, 0x10, 0x53,
+	0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x0e, 0x0a, 0x02, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x02, 0x6f, 0x6e, 0x22,
+	0x6c, 0x0a, 0x0f, 0x50, 0x6c, 0x61, 0x79, 0x54, 0x6f, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x68, 0x7a, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x02, 0x68, 0x7a, 0x12, 0x35, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x2c, 0x0a,
+	0x04, 0x4e, 0x6f, 0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x68, 0x7a, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x02, 0x68, 0x7a, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x65, 0x61, 0x74, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x62, 0x65, 0x61, 0x74, 0x73, 0x22, 0x64, 0x0a, 0x11, 0x50,
+	0x6c, 0x61, 0x79, 0x4d, 0x65, 0x6c, 0x6f, 0x64, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30,
+	0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x74, 0x65, 0x52, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x12,
+	0x10, 0x0a, 0x03, 0x62, 0x70, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x62, 0x70,
+	0x6d, 0x22, 0x45, 0x0a, 0x0c, 0x50, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x35, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x26, 0x0a, 0x10, 0x53, 0x74, 0x6f, 0x70,
+	0x53, 0x6f, 0x75, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x22, 0x13, 0x0a, 0x11, 0x53, 0x74, 0x6f, 0x70, 0x53, 0x6f, 0x75, 0x6e, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x9d, 0x01, 0x0a, 0x0f, 0x53, 0x68, 0x6f, 0x77, 0x54, 0x65,
+	0x78, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78,
+	0x74, 0x12, 0x33, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2d, 0x0a, 0x04, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x04, 0x68, 0x6f, 0x6c, 0x64, 0x22, 0x12, 0x0a, 0x10, 0x53, 0x68, 0x6f, 0x77, 0x54, 0x65, 0x78,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x9d, 0x03, 0x0a, 0x10, 0x53, 0x68,
+	0x6f, 0x77, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x3e, 0x0a, 0x06, 0x64, 0x69, 0x74, 0x68,
+	0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x26, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65,
+	0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x6f, 0x77, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x44, 0x69, 0x74, 0x68, 0x65, 0x72,
+	0x52, 0x06, 0x64, 0x69, 0x74, 0x68, 0x65, 0x72, 0x12, 0x3b, 0x0a, 0x05, 0x73, 0x63, 0x61, 0x6c,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x25, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65,
+	0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x6f, 0x77, 0x49, 0x6d, 0x61, 0x67,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x52, 0x05,
+	0x73, 0x63, 0x61, 0x6c, 0x65, 0x12, 0x21, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f,
+	0x6c, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x09, 0x74, 0x68, 0x72, 0x65,
+	0x73, 0x68, 0x6f, 0x6c, 0x64, 0x88, 0x01, 0x01, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x6e, 0x76, 0x65,
+	0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x69, 0x6e, 0x76, 0x65, 0x72, 0x74,
+	0x12, 0x2d, 0x0a, 0x04, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x68, 0x6f, 0x6c, 0x64, 0x22,
+	0x37, 0x0a, 0x06, 0x44, 0x69, 0x74, 0x68, 0x65, 0x72, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x48, 0x52,
+	0x45, 0x53, 0x48, 0x4f, 0x4c, 0x44, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x4c, 0x4f, 0x59,
+	0x44, 0x5f, 0x53, 0x54, 0x45, 0x49, 0x4e, 0x42, 0x45, 0x52, 0x47, 0x10, 0x01, 0x12, 0x09, 0x0a,
+	0x05, 0x42, 0x41, 0x59, 0x45, 0x52, 0x10, 0x02, 0x22, 0x31, 0x0a, 0x05, 0x53, 0x63, 0x61, 0x6c,
+	0x65, 0x12, 0x07, 0x0a, 0x03, 0x46, 0x49, 0x54, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x46, 0x49,
+	0x4c, 0x4c, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x43, 0x52, 0x4f, 0x50, 0x10, 0x02, 0x12, 0x0b,
+	0x0a, 0x07, 0x53, 0x54, 0x52, 0x45, 0x54, 0x43, 0x48, 0x10, 0x03, 0x42, 0x0c, 0x0a, 0x0a, 0x5f,
+	0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x22, 0x13, 0x0a, 0x11, 0x53, 0x68, 0x6f,
+	0x77, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x29,
+	0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2a, 0x6d, 0x0a, 0x08, 0x50, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x4f, 0x50, 0x5f, 0x4c, 0x45, 0x46,
+	0x54, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x54, 0x4f, 0x50, 0x5f, 0x43, 0x45, 0x4e, 0x54, 0x45,
+	0x52, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x4f, 0x50, 0x5f, 0x52, 0x49, 0x47, 0x48, 0x54,
+	0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x42, 0x4f, 0x54, 0x54, 0x4f, 0x4d, 0x5f, 0x4c, 0x45, 0x46,
+	0x54, 0x10, 0x03, 0x12, 0x11, 0x0a, 0x0d, 0x42, 0x4f, 0x54, 0x54, 0x4f, 0x4d, 0x5f, 0x43, 0x45,
+	0x4e, 0x54, 0x45, 0x52, 0x10, 0x04, 0x12, 0x10, 0x0a, 0x0c, 0x42, 0x4f, 0x54, 0x54, 0x4f, 0x4d,
+	0x5f, 0x52, 0x49, 0x47, 0x48, 0x54, 0x10, 0x05, 0x32, 0x82, 0x06, 0x0a, 0x07, 0x44, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x12, 0x54, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72,
+	0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x09, 0x47, 0x65,
+	0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1f, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65,
+	0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65,
+	0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x4b, 0x0a, 0x08, 0x53, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x2e, 0x70, 0x69,
+	0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x69,
+	0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x08,
+	0x50, 0x6c, 0x61, 0x79, 0x54, 0x6f, 0x6e, 0x65, 0x12, 0x1e, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65,
+	0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x54, 0x6f, 0x6e,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65,
+	0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4b, 0x0a, 0x0a, 0x50, 0x6c, 0x61, 0x79, 0x4d, 0x65, 0x6c,
+	0x6f, 0x64, 0x79, 0x12, 0x20, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x4d, 0x65, 0x6c, 0x6f, 0x64, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36,
+	0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x4e, 0x0a, 0x09, 0x53, 0x74, 0x6f, 0x70, 0x53, 0x6f, 0x75, 0x6e, 0x64, 0x12,
+	0x1f, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x74, 0x6f, 0x70, 0x53, 0x6f, 0x75, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x20, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x6f, 0x70, 0x53, 0x6f, 0x75, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x4b, 0x0a, 0x08, 0x53, 0x68, 0x6f, 0x77, 0x54, 0x65, 0x78, 0x74, 0x12, 0x1e,
+	0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x68, 0x6f, 0x77, 0x54, 0x65, 0x78, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x68, 0x6f, 0x77, 0x54, 0x65, 0x78, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4e, 0x0a, 0x09, 0x53, 0x68, 0x6f, 0x77, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x1f, 0x2e, 0x70,
+	0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x6f,
+	0x77, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e,
+	0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68,
+	0x6f, 0x77, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x46, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67,
+	0x73, 0x12, 0x1c, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x14, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x44, 0x0a, 0x0c, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1c, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65,
+	0x72, 0x36, 0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x36,
+	0x30, 0x30, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x0b, 0x5a,
+	0x09, 0x70, 0x69, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_pioneer600_proto_rawDescOnce sync.Once
+	file_pioneer600_proto_rawDescData = file_pioneer600_proto_rawDesc
+)
+
+func file_pioneer600_proto_rawDescGZIP() []byte {
+	file_pioneer600_proto_rawDescOnce.Do(func() {
+		file_pioneer600_proto_rawDescData = protoimpl.X.CompressGZIP(file_pioneer600_proto_rawDescData)
+	})
+	return file_pioneer600_proto_rawDescData
+}
+
+var file_pioneer600_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_pioneer600_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_pioneer600_proto_goTypes = []interface{}{
+	(Position)(0),                 // 0: pioneer600.v1.Position
+	(SetStateRequest_State)(0),    // 1: pioneer600.v1.SetStateRequest.State
+	(ShowImageRequest_Dither)(0),  // 2: pioneer600.v1.ShowImageRequest.Dither
+	(ShowImageRequest_Scale)(0),   // 3: pioneer600.v1.ShowImageRequest.Scale
+	(*Event)(nil),                 // 4: pioneer600.v1.Event
+	(*Device)(nil),                // 5: pioneer600.v1.Device
+	(*ListDevicesRequest)(nil),    // 6: pioneer600.v1.ListDevicesRequest
+	(*ListDevicesResponse)(nil),   // 7: pioneer600.v1.ListDevicesResponse
+	(*GetDeviceRequest)(nil),      // 8: pioneer600.v1.GetDeviceRequest
+	(*SetStateRequest)(nil),       // 9: pioneer600.v1.SetStateRequest
+	(*SetStateResponse)(nil),      // 10: pioneer600.v1.SetStateResponse
+	(*PlayToneRequest)(nil),       // 11: pioneer600.v1.PlayToneRequest
+	(*Note)(nil),                  // 12: pioneer600.v1.Note
+	(*PlayMelodyRequest)(nil),     // 13: pioneer600.v1.PlayMelodyRequest
+	(*PlayResponse)(nil),          // 14: pioneer600.v1.PlayResponse
+	(*StopSoundRequest)(nil),      // 15: pioneer600.v1.StopSoundRequest
+	(*StopSoundResponse)(nil),     // 16: pioneer600.v1.StopSoundResponse
+	(*ShowTextRequest)(nil),       // 17: pioneer600.v1.ShowTextRequest
+	(*ShowTextResponse)(nil),      // 18: pioneer600.v1.ShowTextResponse
+	(*ShowImageRequest)(nil),      // 19: pioneer600.v1.ShowImageRequest
+	(*ShowImageResponse)(nil),     // 20: pioneer600.v1.ShowImageResponse
+	(*StreamRequest)(nil),         // 21: pioneer600.v1.StreamRequest
+	(*timestamppb.Timestamp)(nil), // 22: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 23: google.protobuf.Duration
+}
+var file_pioneer600_proto_depIdxs = []int32{
+	22, // 0: pioneer600.v1.Event.time:type_name -> google.protobuf.Timestamp
+	22, // 1: pioneer600.v1.Device.since:type_name -> google.protobuf.Timestamp
+	4,  // 2: pioneer600.v1.Device.events:type_name -> pioneer600.v1.Event
+	5,  // 3: pioneer600.v1.ListDevicesResponse.devices:type_name -> pioneer600.v1.Device
+	1,  // 4: pioneer600.v1.SetStateRequest.state:type_name -> pioneer600.v1.SetStateRequest.State
+	23, // 5: pioneer600.v1.SetStateRequest.hold:type_name -> google.protobuf.Duration
+	23, // 6: pioneer600.v1.PlayToneRequest.duration:type_name -> google.protobuf.Duration
+	12, // 7: pioneer600.v1.PlayMelodyRequest.notes:type_name -> pioneer600.v1.Note
+	23, // 8: pioneer600.v1.PlayResponse.duration:type_name -> google.protobuf.Duration
+	0,  // 9: pioneer600.v1.ShowTextRequest.position:type_name -> pioneer600.v1.Position
+	23, // 10: pioneer600.v1.ShowTextRequest.hold:type_name -> google.protobuf.Duration
+	2,  // 11: pioneer600.v1.ShowImageRequest.dither:type_name -> pioneer600.v1.ShowImageRequest.Dither
+	3,  // 12: pioneer600.v1.ShowImageRequest.scale:type_name -> pioneer600.v1.ShowImageRequest.Scale
+	23, // 13: pioneer600.v1.ShowImageRequest.hold:type_name -> google.protobuf.Duration
+	6,  // 14: pioneer600.v1.Devices.ListDevices:input_type -> pioneer600.v1.ListDevicesRequest
+	8,  // 15: pioneer600.v1.Devices.GetDevice:input_type -> pioneer600.v1.GetDeviceRequest
+	9,  // 16: pioneer600.v1.Devices.SetState:input_type -> pioneer600.v1.SetStateRequest
+	11, // 17: pioneer600.v1.Devices.PlayTone:input_type -> pioneer600.v1.PlayToneRequest
+	13, // 18: pioneer600.v1.Devices.PlayMelody:input_type -> pioneer600.v1.PlayMelodyRequest
+	15, // 19: pioneer600.v1.Devices.StopSound:input_type -> pioneer600.v1.StopSoundRequest
+	17, // 20: pioneer600.v1.Devices.ShowText:input_type -> pioneer600.v1.ShowTextRequest
+	19, // 21: pioneer600.v1.Devices.ShowImage:input_type -> pioneer600.v1.ShowImageRequest
+	21, // 22: pioneer600.v1.Devices.StreamReadings:input_type -> pioneer600.v1.StreamRequest
+	21, // 23: pioneer600.v1.Devices.StreamEvents:input_type -> pioneer600.v1.StreamRequest
+	7,  // 24: pioneer600.v1.Devices.ListDevices:output_type -> pioneer600.v1.ListDevicesResponse
+	5,  // 25: pioneer600.v1.Devices.GetDevice:output_type -> pioneer600.v1.Device
+	10, // 26: pioneer600.v1.Devices.SetState:output_type -> pioneer600.v1.SetStateResponse
+	14, // 27: pioneer600.v1.Devices.PlayTone:output_type -> pioneer600.v1.PlayResponse
+	14, // 28: pioneer600.v1.Devices.PlayMelody:output_type -> pioneer600.v1.PlayResponse
+	16, // 29: pioneer600.v1.Devices.StopSound:output_type -> pioneer600.v1.StopSoundResponse
+	18, // 30: pioneer600.v1.Devices.ShowText:output_type -> pioneer600.v1.ShowTextResponse
+	20, // 31: pioneer600.v1.Devices.ShowImage:output_type -> pioneer600.v1.ShowImageResponse
+	4,  // 32: pioneer600.v1.Devices.StreamReadings:output_type -> pioneer600.v1.Event
+	4,  // 33: pioneer600.v1.Devices.StreamEvents:output_type -> pioneer600.v1.Event
+	24, // [24:34] is the sub-list for method output_type
+	14, // [14:24] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_pioneer600_proto_init() }
+func file_pioneer600_proto_init() {
+	if File_pioneer600_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pioneer600_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Device); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDevicesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDevicesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDeviceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetStateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayToneRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Note); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayMelodyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopSoundRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopSoundResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShowTextRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShowTextResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShowImageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShowImageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pioneer600_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pioneer600_proto_msgTypes[15].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pioneer600_proto_rawDesc,
+			NumEnums:      4,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pioneer600_proto_goTypes,
+		DependencyIndexes: file_pioneer600_proto_depIdxs,
+		EnumInfos:         file_pioneer600_proto_enumTypes,
+		MessageInfos:      file_pioneer600_proto_msgTypes,
+	}.Build()
+	File_pioneer600_proto = out.File
+	file_pioneer600_proto_rawDesc = nil
+	file_pioneer600_proto_goTypes = nil
+	file_pioneer600_proto_depIdxs = nil
+}
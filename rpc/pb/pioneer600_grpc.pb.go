@@ -0,0 +1,502 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// DevicesClient is the client API for Devices service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DevicesClient interface {
+	// ListDevices returns the health of the configured devices.
+	ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*ListDevicesResponse, error)
+	// GetDevice returns the health and the latest events of a device.
+	GetDevice(ctx context.Context, in *GetDeviceRequest, opts ...grpc.CallOption) (*Device, error)
+	// SetState switches a LED.
+	SetState(ctx context.Context, in *SetStateRequest, opts ...grpc.CallOption) (*SetStateResponse, error)
+	// PlayTone plays a tone on a buzzer in the background.
+	PlayTone(ctx context.Context, in *PlayToneRequest, opts ...grpc.CallOption) (*PlayResponse, error)
+	// PlayMelody plays notes on a buzzer in the background.
+	PlayMelody(ctx context.Context, in *PlayMelodyRequest, opts ...grpc.CallOption) (*PlayResponse, error)
+	// StopSound stops the buzzer.
+	StopSound(ctx context.Context, in *StopSoundRequest, opts ...grpc.CallOption) (*StopSoundResponse, error)
+	// ShowText draws text on the OLED.
+	ShowText(ctx context.Context, in *ShowTextRequest, opts ...grpc.CallOption) (*ShowTextResponse, error)
+	// ShowImage draws a PNG, JPEG or GIF image on the OLED.
+	ShowImage(ctx context.Context, in *ShowImageRequest, opts ...grpc.CallOption) (*ShowImageResponse, error)
+	// StreamReadings sends the latest readings, then each one as published.
+	StreamReadings(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (Devices_StreamReadingsClient, error)
+	// StreamEvents sends the state changes as they happen: joystick buttons,
+	// LEDs and alerts, without the readings.
+	StreamEvents(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (Devices_StreamEventsClient, error)
+}
+
+type devicesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDevicesClient(cc grpc.ClientConnInterface) DevicesClient {
+	return &devicesClient{cc}
+}
+
+func (c *devicesClient) ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*ListDevicesResponse, error) {
+	out := new(ListDevicesResponse)
+	err := c.cc.Invoke(ctx, "/pioneer600.v1.Devices/ListDevices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devicesClient) GetDevice(ctx context.Context, in *GetDeviceRequest, opts ...grpc.CallOption) (*Device, error) {
+	out := new(Device)
+	err := c.cc.Invoke(ctx, "/pioneer600.v1.Devices/GetDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devicesClient) SetState(ctx context.Context, in *SetStateRequest, opts ...grpc.CallOption) (*SetStateResponse, error) {
+	out := new(SetStateResponse)
+	err := c.cc.Invoke(ctx, "/pioneer600.v1.Devices/SetState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devicesClient) PlayTone(ctx context.Context, in *PlayToneRequest, opts ...grpc.CallOption) (*PlayResponse, error) {
+	out := new(PlayResponse)
+	err := c.cc.Invoke(ctx, "/pioneer600.v1.Devices/PlayTone", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devicesClient) PlayMelody(ctx context.Context, in *PlayMelodyRequest, opts ...grpc.CallOption) (*PlayResponse, error) {
+	out := new(PlayResponse)
+	err := c.cc.Invoke(ctx, "/pioneer600.v1.Devices/PlayMelody", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devicesClient) StopSound(ctx context.Context, in *StopSoundRequest, opts ...grpc.CallOption) (*StopSoundResponse, error) {
+	out := new(StopSoundResponse)
+	err := c.cc.Invoke(ctx, "/pioneer600.v1.Devices/StopSound", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devicesClient) ShowText(ctx context.Context, in *ShowTextRequest, opts ...grpc.CallOption) (*ShowTextResponse, error) {
+	out := new(ShowTextResponse)
+	err := c.cc.Invoke(ctx, "/pioneer600.v1.Devices/ShowText", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devicesClient) ShowImage(ctx context.Context, in *ShowImageRequest, opts ...grpc.CallOption) (*ShowImageResponse, error) {
+	out := new(ShowImageResponse)
+	err := c.cc.Invoke(ctx, "/pioneer600.v1.Devices/ShowImage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devicesClient) StreamReadings(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (Devices_StreamReadingsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Devices_ServiceDesc.Streams[0], "/pioneer600.v1.Devices/StreamReadings", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &devicesStreamReadingsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Devices_StreamReadingsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type devicesStreamReadingsClient struct {
+	grpc.ClientStream
+}
+
+func (x *devicesStreamReadingsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *devicesClient) StreamEvents(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (Devices_StreamEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Devices_ServiceDesc.Streams[1], "/pioneer600.v1.Devices/StreamEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &devicesStreamEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Devices_StreamEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type devicesStreamEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *devicesStreamEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DevicesServer is the server API for Devices service.
+// All implementations must embed UnimplementedDevicesServer
+// for forward compatibility
+type DevicesServer interface {
+	// ListDevices returns the health of the configured devices.
+	ListDevices(context.Context, *ListDevicesRequest) (*ListDevicesResponse, error)
+	// GetDevice returns the health and the latest events of a device.
+	GetDevice(context.Context, *GetDeviceRequest) (*Device, error)
+	// SetState switches a LED.
+	SetState(context.Context, *SetStateRequest) (*SetStateResponse, error)
+	// PlayTone plays a tone on a buzzer in the background.
+	PlayTone(context.Context, *PlayToneRequest) (*PlayResponse, error)
+	// PlayMelody plays notes on a buzzer in the background.
+	PlayMelody(context.Context, *PlayMelodyRequest) (*PlayResponse, error)
+	// StopSound stops the buzzer.
+	StopSound(context.Context, *StopSoundRequest) (*StopSoundResponse, error)
+	// ShowText draws text on the OLED.
+	ShowText(context.Context, *ShowTextRequest) (*ShowTextResponse, error)
+	// ShowImage draws a PNG, JPEG or GIF image on the OLED.
+	ShowImage(context.Context, *ShowImageRequest) (*ShowImageResponse, error)
+	// StreamReadings sends the latest readings, then each one as published.
+	StreamReadings(*StreamRequest, Devices_StreamReadingsServer) error
+	// StreamEvents sends the state changes as they happen: joystick buttons,
+	// LEDs and alerts, without the readings.
+	StreamEvents(*StreamRequest, Devices_StreamEventsServer) error
+	mustEmbedUnimplementedDevicesServer()
+}
+
+// UnimplementedDevicesServer must be embedded to have forward compatible implementations.
+type UnimplementedDevicesServer struct {
+}
+
+func (UnimplementedDevicesServer) ListDevices(context.Context, *ListDevicesRequest) (*ListDevicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDevices not implemented")
+}
+func (UnimplementedDevicesServer) GetDevice(context.Context, *GetDeviceRequest) (*Device, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDevice not implemented")
+}
+func (UnimplementedDevicesServer) SetState(context.Context, *SetStateRequest) (*SetStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetState not implemented")
+}
+func (UnimplementedDevicesServer) PlayTone(context.Context, *PlayToneRequest) (*PlayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlayTone not implemented")
+}
+func (UnimplementedDevicesServer) PlayMelody(context.Context, *PlayMelodyRequest) (*PlayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlayMelody not implemented")
+}
+func (UnimplementedDevicesServer) StopSound(context.Context, *StopSoundRequest) (*StopSoundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopSound not implemented")
+}
+func (UnimplementedDevicesServer) ShowText(context.Context, *ShowTextRequest) (*ShowTextResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShowText not implemented")
+}
+func (UnimplementedDevicesServer) ShowImage(context.Context, *ShowImageRequest) (*ShowImageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShowImage not implemented")
+}
+func (UnimplementedDevicesServer) StreamReadings(*StreamRequest, Devices_StreamReadingsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamReadings not implemented")
+}
+func (UnimplementedDevicesServer) StreamEvents(*StreamRequest, Devices_StreamEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedDevicesServer) mustEmbedUnimplementedDevicesServer() {}
+
+// UnsafeDevicesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DevicesServer will
+// result in compilation errors.
+type UnsafeDevicesServer interface {
+	mustEmbedUnimplementedDevicesServer()
+}
+
+func RegisterDevicesServer(s grpc.ServiceRegistrar, srv DevicesServer) {
+	s.RegisterService(&Devices_ServiceDesc, srv)
+}
+
+func _Devices_ListDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevicesServer).ListDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pioneer600.v1.Devices/ListDevices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevicesServer).ListDevices(ctx, req.(*ListDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Devices_GetDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevicesServer).GetDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pioneer600.v1.Devices/GetDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevicesServer).GetDevice(ctx, req.(*GetDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Devices_SetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevicesServer).SetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pioneer600.v1.Devices/SetState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevicesServer).SetState(ctx, req.(*SetStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Devices_PlayTone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayToneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevicesServer).PlayTone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pioneer600.v1.Devices/PlayTone",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevicesServer).PlayTone(ctx, req.(*PlayToneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Devices_PlayMelody_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayMelodyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevicesServer).PlayMelody(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pioneer600.v1.Devices/PlayMelody",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevicesServer).PlayMelody(ctx, req.(*PlayMelodyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Devices_StopSound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopSoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevicesServer).StopSound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pioneer600.v1.Devices/StopSound",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevicesServer).StopSound(ctx, req.(*StopSoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Devices_ShowText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShowTextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevicesServer).ShowText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pioneer600.v1.Devices/ShowText",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevicesServer).ShowText(ctx, req.(*ShowTextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Devices_ShowImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShowImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevicesServer).ShowImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pioneer600.v1.Devices/ShowImage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevicesServer).ShowImage(ctx, req.(*ShowImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Devices_StreamReadings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DevicesServer).StreamReadings(m, &devicesStreamReadingsServer{stream})
+}
+
+type Devices_StreamReadingsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type devicesStreamReadingsServer struct {
+	grpc.ServerStream
+}
+
+func (x *devicesStreamReadingsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Devices_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DevicesServer).StreamEvents(m, &devicesStreamEventsServer{stream})
+}
+
+type Devices_StreamEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type devicesStreamEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *devicesStreamEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Devices_ServiceDesc is the grpc.ServiceDesc for Devices service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Devices_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pioneer600.v1.Devices",
+	HandlerType: (*DevicesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListDevices",
+			Handler:    _Devices_ListDevices_Handler,
+		},
+		{
+			MethodName: "GetDevice",
+			Handler:    _Devices_GetDevice_Handler,
+		},
+		{
+			MethodName: "SetState",
+			Handler:    _Devices_SetState_Handler,
+		},
+		{
+			MethodName: "PlayTone",
+			Handler:    _Devices_PlayTone_Handler,
+		},
+		{
+			MethodName: "PlayMelody",
+			Handler:    _Devices_PlayMelody_Handler,
+		},
+		{
+			MethodName: "StopSound",
+			Handler:    _Devices_StopSound_Handler,
+		},
+		{
+			MethodName: "ShowText",
+			Handler:    _Devices_ShowText_Handler,
+		},
+		{
+			MethodName: "ShowImage",
+			Handler:    _Devices_ShowImage_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamReadings",
+			Handler:       _Devices_StreamReadings_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _Devices_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pioneer600.proto",
+}
@@ -0,0 +1,336 @@
+// Package rpc serves the devices of a control.Controller over gRPC, with
+// the events of the bus streamed as they are published.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"pi/control"
+	"pi/dev"
+	"pi/event"
+	"pi/log"
+	"pi/rpc/pb"
+	"sort"
+	"time"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	// The image formats of ShowImage.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+const (
+	// maxImage is the largest image of ShowImage.
+	maxImage = 1 << 20
+	// maxHold is the longest hold of a command.
+	maxHold = time.Hour
+)
+
+// Options is the grpc section of the configuration.
+type Options struct {
+	// Listen is the address of the server, empty disables it.
+	Listen string
+}
+
+// NewOptions reads the grpc section of the configuration.
+func NewOptions(v *viper.Viper) (*Options, error) {
+	o := new(Options)
+	if err := v.UnmarshalKey("grpc", o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+var positions = map[pb.Position]dev.SSD1306Pos{
+	pb.Position_TOP_LEFT:      dev.PosTopLeft,
+	pb.Position_TOP_CENTER:    dev.PosTopCenter,
+	pb.Position_TOP_RIGHT:     dev.PosTopRight,
+	pb.Position_BOTTOM_LEFT:   dev.PosBottomLeft,
+	pb.Position_BOTTOM_CENTER: dev.PosBottomCenter,
+	pb.Position_BOTTOM_RIGHT:  dev.PosBottomRight,
+}
+
+var dithers = map[pb.ShowImageRequest_Dither]dev.DitherMode{
+	pb.ShowImageRequest_THRESHOLD:       dev.DitherThreshold,
+	pb.ShowImageRequest_FLOYD_STEINBERG: dev.DitherFloydSteinberg,
+	pb.ShowImageRequest_BAYER:           dev.DitherBayer,
+}
+
+var scales = map[pb.ShowImageRequest_Scale]dev.ScaleMode{
+	pb.ShowImageRequest_FIT:     dev.ScaleFit,
+	pb.ShowImageRequest_FILL:    dev.ScaleFill,
+	pb.ShowImageRequest_CROP:    dev.ScaleCrop,
+	pb.ShowImageRequest_STRETCH: dev.ScaleStretch,
+}
+
+// Server is the gRPC server of the Devices service, with reflection.
+type Server struct {
+	pb.UnimplementedDevicesServer
+	opts Options
+	ctl  *control.Controller
+	bus  *event.Bus
+	grpc *grpc.Server
+}
+
+// NewServer creates a new Server of the devices of ctl and the events of
+// bus.
+func NewServer(o *Options, ctl *control.Controller, bus *event.Bus) *Server {
+	s := &Server{opts: *o, ctl: ctl, bus: bus, grpc: grpc.NewServer()}
+	pb.RegisterDevicesServer(s.grpc, s)
+	reflection.Register(s.grpc)
+	return s
+}
+
+// Run serves on opts.Listen until ctx is done.
+func (s *Server) Run(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.opts.Listen)
+	if err != nil {
+		return err
+	}
+	log.Default().Info("grpc server listening on ", s.opts.Listen)
+	return s.Serve(ctx, lis)
+}
+
+// Serve serves on lis until ctx is done, then closes the streams.
+func (s *Server) Serve(ctx context.Context, lis net.Listener) error {
+	errc := make(chan error, 1)
+	go func() { errc <- s.grpc.Serve(lis) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	s.grpc.Stop()
+	return nil
+}
+
+// statusOf returns the status of a control error.
+func statusOf(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, control.ErrUnknown):
+		code = codes.NotFound
+	case errors.Is(err, control.ErrUnsupported):
+		code = codes.FailedPrecondition
+	case errors.Is(err, control.ErrFailed):
+		code = codes.Unavailable
+	case errors.Is(err, control.ErrBusy):
+		code = codes.Aborted
+	case errors.Is(err, control.ErrInvalid):
+		code = codes.InvalidArgument
+	}
+	return status.Error(code, err.Error())
+}
+
+// holdOf returns d as a hold, control.DefaultHold when nil.
+func holdOf(d *durationpb.Duration) (time.Duration, error) {
+	if d == nil {
+		return control.DefaultHold, nil
+	}
+	hold := d.AsDuration()
+	if hold < 0 || hold > maxHold {
+		return 0, status.Errorf(codes.InvalidArgument, "hold %v not in [0, %v]", hold, maxHold)
+	}
+	return hold, nil
+}
+
+func eventOf(e event.Event) *pb.Event {
+	return &pb.Event{Device: e.Device, Name: e.Name, Value: e.Value, Unit: e.Unit, Time: timestamppb.New(e.Time)}
+}
+
+func deviceOf(h dev.DeviceHealth) *pb.Device {
+	return &pb.Device{Name: h.Name, Driver: h.Driver, State: h.State, Error: h.Error, Since: timestamppb.New(h.Since)}
+}
+
+// ListDevices implements pb.DevicesServer.
+func (s *Server) ListDevices(ctx context.Context, req *pb.ListDevicesRequest) (*pb.ListDevicesResponse, error) {
+	resp := &pb.ListDevicesResponse{}
+	for _, h := range s.ctl.Health().Report() {
+		resp.Devices = append(resp.Devices, deviceOf(h))
+	}
+	return resp, nil
+}
+
+// GetDevice implements pb.DevicesServer.
+func (s *Server) GetDevice(ctx context.Context, req *pb.GetDeviceRequest) (*pb.Device, error) {
+	for _, h := range s.ctl.Health().Report() {
+		if h.Name != req.Name {
+			continue
+		}
+		d := deviceOf(h)
+		for _, e := range s.ctl.Readings(req.Name) {
+			d.Events = append(d.Events, eventOf(e))
+		}
+		sort.Slice(d.Events, func(i, j int) bool { return d.Events[i].Name < d.Events[j].Name })
+		return d, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "unknown device %s", req.Name)
+}
+
+// SetState implements pb.DevicesServer.
+func (s *Server) SetState(ctx context.Context, req *pb.SetStateRequest) (*pb.SetStateResponse, error) {
+	states := map[pb.SetStateRequest_State]string{
+		pb.SetStateRequest_ON:     "on",
+		pb.SetStateRequest_OFF:    "off",
+		pb.SetStateRequest_TOGGLE: "toggle",
+	}
+	state, ok := states[req.State]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "state must be ON, OFF or TOGGLE")
+	}
+	hold, err := holdOf(req.Hold)
+	if err != nil {
+		return nil, err
+	}
+	on, err := s.ctl.Switch(req.Name, state, hold)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return &pb.SetStateResponse{On: on}, nil
+}
+
+// PlayTone implements pb.DevicesServer.
+func (s *Server) PlayTone(ctx context.Context, req *pb.PlayToneRequest) (*pb.PlayResponse, error) {
+	return s.play(req.Name, []control.Note{{Hz: req.Hz, Duration: req.Duration.AsDuration()}})
+}
+
+// PlayMelody implements pb.DevicesServer.
+func (s *Server) PlayMelody(ctx context.Context, req *pb.PlayMelodyRequest) (*pb.PlayResponse, error) {
+	notes := make([]control.Note, len(req.Notes))
+	for i, n := range req.Notes {
+		notes[i] = control.Note{Hz: n.Hz, Duration: control.Beats(req.Bpm, n.Beats)}
+	}
+	return s.play(req.Name, notes)
+}
+
+func (s *Server) play(name string, notes []control.Note) (*pb.PlayResponse, error) {
+	total, err := s.ctl.Play(name, notes)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return &pb.PlayResponse{Duration: durationpb.New(total)}, nil
+}
+
+// StopSound implements pb.DevicesServer.
+func (s *Server) StopSound(ctx context.Context, req *pb.StopSoundRequest) (*pb.StopSoundResponse, error) {
+	if _, err := s.ctl.Device(req.Name); err != nil {
+		return nil, statusOf(err)
+	}
+	s.ctl.Stop(req.Name)
+	return &pb.StopSoundResponse{}, nil
+}
+
+// ShowText implements pb.DevicesServer.
+func (s *Server) ShowText(ctx context.Context, req *pb.ShowTextRequest) (*pb.ShowTextResponse, error) {
+	pos, ok := positions[req.Position]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown position %v", req.Position)
+	}
+	hold, err := holdOf(req.Hold)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ctl.ShowText(req.Name, pos, req.Text, hold); err != nil {
+		return nil, statusOf(err)
+	}
+	return &pb.ShowTextResponse{}, nil
+}
+
+// ShowImage implements pb.DevicesServer.
+func (s *Server) ShowImage(ctx context.Context, req *pb.ShowImageRequest) (*pb.ShowImageResponse, error) {
+	if len(req.Image) > maxImage {
+		return nil, status.Error(codes.InvalidArgument, "image over 1MiB")
+	}
+	if req.Threshold != nil && (*req.Threshold < 1 || *req.Threshold > 255) {
+		return nil, status.Errorf(codes.InvalidArgument, "threshold %d not in [1, 255]", *req.Threshold)
+	}
+	opts := dev.DefaultConvertOptions
+	var ok bool
+	if opts.Dither, ok = dithers[req.Dither]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown dither %v", req.Dither)
+	}
+	if opts.Scale, ok = scales[req.Scale]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown scale %v", req.Scale)
+	}
+	if req.Threshold != nil {
+		opts.Threshold = uint8(*req.Threshold)
+	}
+	opts.Invert = req.Invert
+	hold, err := holdOf(req.Hold)
+	if err != nil {
+		return nil, err
+	}
+	img, err := dev.DecodeImage(req.Image)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "decode image: "+err.Error())
+	}
+	if err := s.ctl.ShowImage(req.Name, img, &opts, hold); err != nil {
+		return nil, statusOf(err)
+	}
+	return &pb.ShowImageResponse{}, nil
+}
+
+// StreamReadings implements pb.DevicesServer.
+func (s *Server) StreamReadings(req *pb.StreamRequest, stream pb.Devices_StreamReadingsServer) error {
+	return s.stream(req, stream, true)
+}
+
+// StreamEvents implements pb.DevicesServer.
+func (s *Server) StreamEvents(req *pb.StreamRequest, stream pb.Devices_StreamEventsServer) error {
+	return s.stream(req, stream, false)
+}
+
+// stream sends the readings, or the states, of the devices of req until
+// the client leaves. The latest readings are sent first.
+func (s *Server) stream(req *pb.StreamRequest, stream interface {
+	Send(*pb.Event) error
+	Context() context.Context
+}, readings bool) error {
+	devices := make(map[string]bool, len(req.Devices))
+	for _, name := range req.Devices {
+		devices[name] = true
+	}
+	match := func(e event.Event) bool {
+		return (e.Unit != "") == readings && (len(devices) == 0 || devices[e.Device])
+	}
+	events, cancel := s.bus.Subscribe(64)
+	defer cancel()
+	if readings {
+		latest := s.bus.Snapshot()
+		sort.Slice(latest, func(i, j int) bool {
+			if latest[i].Device != latest[j].Device {
+				return latest[i].Device < latest[j].Device
+			}
+			return latest[i].Name < latest[j].Name
+		})
+		for _, e := range latest {
+			if match(e) {
+				if err := stream.Send(eventOf(e)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e := <-events:
+			if !match(e) {
+				continue
+			}
+			if err := stream.Send(eventOf(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package rpc
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"net"
+	"pi/control"
+	"pi/dev"
+	"pi/event"
+	"pi/rpc/pb"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// hugePNG returns the header of a 100000x100000 PNG.
+func hugePNG() []byte {
+	b := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x00\x00\x00\x00\x00\x00\x08\x00\x00\x00\x00\x00\x00\x00\x00")
+	binary.BigEndian.PutUint32(b[16:], 100000)
+	binary.BigEndian.PutUint32(b[20:], 100000)
+	binary.BigEndian.PutUint32(b[29:], crc32.ChecksumIEEE(b[12:29]))
+	return b
+}
+
+// fakeLED is a LED.
+type fakeLED struct {
+	mu     sync.Mutex
+	status int
+}
+
+func (l *fakeLED) Name() string                   { return "led2" }
+func (l *fakeLED) Init(ctx context.Context) error { return nil }
+func (l *fakeLED) Close() error                   { return nil }
+func (l *fakeLED) Health() dev.Health             { return dev.Health{State: dev.HealthPresent} }
+
+func (l *fakeLED) set(status int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.status = status
+	return nil
+}
+
+func (l *fakeLED) On() error     { return l.set(1) }
+func (l *fakeLED) Off() error    { return l.set(0) }
+func (l *fakeLED) Toggle() error { return l.set(l.Status() ^ 1) }
+func (l *fakeLED) Status() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.status
+}
+
+// dial serves a Server of led2 and bus on a bufconn listener.
+func dial(t *testing.T, bus *event.Bus) (*grpc.ClientConn, func()) {
+	t.Helper()
+	health := dev.NewHealthRegistry()
+	health.Add("led2", &fakeLED{})
+	s := NewServer(&Options{}, control.New(health, bus, nil), bus)
+	lis := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Serve(ctx, lis) }()
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, func() {
+		conn.Close()
+		cancel()
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestUnary(t *testing.T) {
+	conn, stop := dial(t, event.New())
+	defer stop()
+	c := pb.NewDevicesClient(conn)
+	ctx := context.Background()
+
+	list, err := c.ListDevices(ctx, &pb.ListDevicesRequest{})
+	if err != nil || len(list.Devices) != 1 || list.Devices[0].Name != "led2" || list.Devices[0].State != dev.HealthPresent {
+		t.Fatalf("devices %v, %v", list, err)
+	}
+	resp, err := c.SetState(ctx, &pb.SetStateRequest{Name: "led2", State: pb.SetStateRequest_TOGGLE})
+	if err != nil || !resp.On {
+		t.Errorf("toggle %v, %v", resp, err)
+	}
+	d, err := c.GetDevice(ctx, &pb.GetDeviceRequest{Name: "led2"})
+	if err != nil || len(d.Events) != 1 || d.Events[0].Name != "state" || d.Events[0].Value != 1 {
+		t.Errorf("led2 %v, %v", d, err)
+	}
+
+	for _, call := range []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"unknown device", func() error {
+			_, err := c.GetDevice(ctx, &pb.GetDeviceRequest{Name: "humidity"})
+			return err
+		}(), codes.NotFound},
+		{"no state", func() error {
+			_, err := c.SetState(ctx, &pb.SetStateRequest{Name: "led2"})
+			return err
+		}(), codes.InvalidArgument},
+		{"melody on a LED", func() error {
+			_, err := c.PlayMelody(ctx, &pb.PlayMelodyRequest{Name: "led2", Notes: []*pb.Note{{Hz: 440, Beats: 1}}})
+			return err
+		}(), codes.FailedPrecondition},
+		{"text too long", func() error {
+			_, err := c.ShowText(ctx, &pb.ShowTextRequest{Name: "oled", Text: strings.Repeat("x", control.MaxText+1)})
+			return err
+		}(), codes.InvalidArgument},
+		{"threshold 0", func() error {
+			threshold := uint32(0)
+			_, err := c.ShowImage(ctx, &pb.ShowImageRequest{Name: "oled", Threshold: &threshold})
+			return err
+		}(), codes.InvalidArgument},
+		{"image too large", func() error {
+			_, err := c.ShowImage(ctx, &pb.ShowImageRequest{Name: "oled", Image: hugePNG()})
+			return err
+		}(), codes.InvalidArgument},
+		{"image not decoded", func() error {
+			_, err := c.ShowImage(ctx, &pb.ShowImageRequest{Name: "led2", Image: []byte("GIF89a")})
+			return err
+		}(), codes.InvalidArgument},
+	} {
+		if got := status.Code(call.err); got != call.code {
+			t.Errorf("%s: %v, want %v", call.name, call.err, call.code)
+		}
+	}
+}
+
+func TestStreams(t *testing.T) {
+	bus := event.New()
+	bus.Publish(event.Event{Device: "temp", Name: "temperature", Value: 21.5, Unit: "°C", Time: time.Now()})
+	conn, stop := dial(t, bus)
+	defer stop()
+	c := pb.NewDevicesClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	readings, err := c.StreamReadings(ctx, &pb.StreamRequest{Devices: []string{"temp"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The latest reading comes first, once subscribed.
+	if e, err := readings.Recv(); err != nil || e.Value != 21.5 || e.Unit != "°C" {
+		t.Fatalf("first reading %v, %v", e, err)
+	}
+	bus.Publish(event.Event{Device: "joystick", Name: "up", Value: 1})
+	bus.Publish(event.Event{Device: "pressure", Name: "pressure", Value: 101325, Unit: "Pa"})
+	bus.Publish(event.Event{Device: "temp", Name: "temperature", Value: 22, Unit: "°C"})
+	if e, err := readings.Recv(); err != nil || e.Device != "temp" || e.Value != 22 {
+		t.Errorf("next reading %v, %v", e, err)
+	}
+
+	events, err := c.StreamEvents(ctx, &pb.StreamRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := make(chan *pb.Event, 64)
+	go func() {
+		for {
+			e, err := events.Recv()
+			if err != nil {
+				close(received)
+				return
+			}
+			received <- e
+		}
+	}()
+	// The subscription races the first events.
+	for {
+		bus.Publish(event.Event{Device: "temp", Name: "temperature", Value: 23, Unit: "°C"})
+		bus.Publish(event.Event{Device: "joystick", Name: "press", Value: 1})
+		select {
+		case e := <-received:
+			if e == nil || e.Device != "joystick" || e.Name != "press" || e.Unit != "" {
+				t.Fatalf("event %v", e)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestReflection(t *testing.T) {
+	conn, stop := dial(t, event.New())
+	defer stop()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	info, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := info.Send(&rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_ListServices{}}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := info.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		found = found || s.Name == "pioneer600.v1.Devices"
+	}
+	if !found {
+		t.Errorf("services %v", resp.GetListServicesResponse())
+	}
+}
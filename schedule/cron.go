@@ -0,0 +1,169 @@
+// Package schedule runs the actions of jobs on cron expressions, on the
+// DS3231 time while the system clock is not synchronized.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// descriptors are the cron expressions with a name.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field is the range and names of a cron field.
+type field struct {
+	name     string
+	min, max int
+	names    []string // from min
+}
+
+var fields = [5]field{
+	{name: "minute", max: 59},
+	{name: "hour", max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	// 7 is Sunday too.
+	{name: "day of week", max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// Cron is a parsed cron expression, the bits of each field are set for
+// its values.
+type Cron struct {
+	spec                          string
+	minute, hour, dom, month, dow uint64
+	// anyDay is a day of month or of week of *, the other one is then
+	// matched alone.
+	anyDay bool
+}
+
+// Parse parses the 5 fields "minute hour day-of-month month day-of-week"
+// of a cron expression, or a descriptor such as @daily. A field is * or a
+// list of values and ranges, with an optional step, eg. "*/15" or
+// "mon-fri". The job runs when the day of month or the day of week
+// matches, if both are not *.
+func Parse(spec string) (*Cron, error) {
+	expr := strings.TrimSpace(spec)
+	if d, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = d
+	}
+	f := strings.Fields(expr)
+	if len(f) != len(fields) {
+		return nil, fmt.Errorf("cron %q: want 5 fields or a descriptor", spec)
+	}
+	var bits [5]uint64
+	for i := range f {
+		b, err := fields[i].parse(f[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %v", spec, err)
+		}
+		bits[i] = b
+	}
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &Cron{
+		spec:   spec,
+		minute: bits[0], hour: bits[1], dom: bits[2], month: bits[3], dow: bits[4],
+		anyDay: strings.HasPrefix(f[2], "*") || strings.HasPrefix(f[4], "*"),
+	}, nil
+}
+
+// parse returns the bits of the values of the list s.
+func (f field) parse(s string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(s, ",") {
+		step := 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s step %q is not a positive number", f.name, part[i+1:])
+			}
+			part, step = part[:i], n
+		}
+		from, to := f.min, f.max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if from, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			to = from
+			if len(bounds) == 2 {
+				if to, err = f.value(bounds[1]); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				to = f.max
+			}
+			if to < from {
+				return 0, fmt.Errorf("%s range %q is decreasing", f.name, part)
+			}
+		}
+		for v := from; v <= to; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a number or a name of f.
+func (f field) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s %q not in [%d, %d]", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// String returns the expression c was parsed from.
+func (c *Cron) String() string {
+	return c.spec
+}
+
+// Next returns the first time of c after t, in the location of t. It is
+// zero when there is none in the next 5 years, eg. on February 30.
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.day(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// day reports whether the day of t matches.
+func (c *Cron) day(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
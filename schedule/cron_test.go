@@ -0,0 +1,55 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCron(t *testing.T) {
+	// A Monday.
+	from := time.Date(2026, 10, 19, 12, 34, 56, 0, time.UTC)
+	for _, c := range []struct {
+		spec string
+		want string
+	}{
+		{"* * * * *", "2026-10-19 12:35"},
+		{"*/15 * * * *", "2026-10-19 12:45"},
+		{"30 6 * * mon-fri", "2026-10-20 06:30"},
+		{"0 9 * * sat,7", "2026-10-24 09:00"},
+		{"0 0 1 jan *", "2027-01-01 00:00"},
+		{"0 12 13 * 5", "2026-10-23 12:00"}, // the 13th or a Friday
+		{"5-10/5 */6 * * *", "2026-10-19 18:05"},
+		{"@hourly", "2026-10-19 13:00"},
+		{"@Weekly", "2026-10-25 00:00"},
+		{"0 0 29 2 *", "2028-02-29 00:00"},
+	} {
+		cron, err := Parse(c.spec)
+		if err != nil {
+			t.Errorf("%s: %v", c.spec, err)
+			continue
+		}
+		if got := cron.Next(from).Format("2006-01-02 15:04"); got != c.want {
+			t.Errorf("%s: next %s, want %s", c.spec, got, c.want)
+		}
+	}
+	if cron, _ := Parse("0 0 30 2 *"); !cron.Next(from).IsZero() {
+		t.Error("February 30 has a next run")
+	}
+
+	for _, c := range []struct {
+		spec string
+		want string
+	}{
+		{"* * * *", "5 fields"},
+		{"60 * * * *", "minute \"60\" not in [0, 59]"},
+		{"* * 0 * *", "day of month"},
+		{"* * * * 1-foo", "day of week"},
+		{"*/0 * * * *", "step"},
+		{"10-5 * * * *", "decreasing"},
+	} {
+		if _, err := Parse(c.spec); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: %v, want %s", c.spec, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package schedule
+
+import (
+	"fmt"
+	"pi/control"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Action types.
+const (
+	// ActionMelody plays Melody on a buzzer at BPM.
+	ActionMelody = "melody"
+	// ActionPattern switches an LED on for each 1 and off for each 0 of
+	// Pattern, a Step each.
+	ActionPattern = "pattern"
+	// ActionText shows Text on the OLED, {device/name} is replaced by the
+	// reading.
+	ActionText = "text"
+	// ActionSnapshot logs the readings of Device, all when empty, and
+	// appends them as a JSON line to Path if set.
+	ActionSnapshot = "snapshot"
+)
+
+// Missed run policies.
+const (
+	// MissedSkip drops the runs missed while the daemon was stopped or
+	// the time unknown.
+	MissedSkip = "skip"
+	// MissedOnce runs the job once for all the missed runs.
+	MissedOnce = "once"
+)
+
+// DefaultStep is the Step of a pattern action without one.
+const DefaultStep = 250 * time.Millisecond
+
+// Action is run on the device of the controller when a job runs.
+type Action struct {
+	Type    string
+	Device  string
+	Melody  string
+	BPM     float64
+	Pattern string
+	Step    time.Duration
+	Text    string
+	Path    string
+
+	notes []control.Note
+}
+
+// Job is an entry of the jobs of the schedule section, eg.
+//
+//	name: wake-up
+//	cron: "30 6 * * mon-fri"
+//	missed: once
+//	actions:
+//	  - {type: melody, device: buzzer, melody: "C5 E5 G5:2", bpm: 120}
+//	  - {type: pattern, device: led1, pattern: "1010101"}
+//	  - {type: text, device: oled, text: "Good morning {temp/temperature} C"}
+//	  - {type: snapshot, path: /var/lib/Pioneer600/snapshots.jsonl}
+type Job struct {
+	Name string
+	Cron string
+	// Missed is MissedSkip or MissedOnce, skip when empty.
+	Missed  string
+	Actions []Action
+
+	cron *Cron
+}
+
+// Options is the schedule section of the configuration.
+type Options struct {
+	// State is the file of the last runs, needed to find the runs missed
+	// during a power loss.
+	State string
+	Jobs  []Job
+}
+
+// NewOptions reads and checks the schedule section of the configuration.
+func NewOptions(v *viper.Viper) (*Options, error) {
+	o := &Options{}
+	if err := v.UnmarshalKey("schedule", o); err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for i := range o.Jobs {
+		j := &o.Jobs[i]
+		if err := j.check(); err != nil {
+			return nil, fmt.Errorf("schedule.jobs[%d]: %v", i, err)
+		}
+		if names[j.Name] {
+			return nil, fmt.Errorf("schedule.jobs[%d]: duplicate name %s", i, j.Name)
+		}
+		names[j.Name] = true
+	}
+	return o, nil
+}
+
+// check validates j and parses its cron expression and melodies.
+func (j *Job) check() error {
+	if j.Name == "" || j.Cron == "" {
+		return fmt.Errorf("name and cron are required")
+	}
+	c, err := Parse(j.Cron)
+	if err != nil {
+		return fmt.Errorf("%s: %v", j.Name, err)
+	}
+	j.cron = c
+	switch j.Missed {
+	case "":
+		j.Missed = MissedSkip
+	case MissedSkip, MissedOnce:
+	default:
+		return fmt.Errorf("%s: missed %q is not %s or %s", j.Name, j.Missed, MissedSkip, MissedOnce)
+	}
+	for k := range j.Actions {
+		a := &j.Actions[k]
+		if a.Device == "" && a.Type != ActionSnapshot {
+			return fmt.Errorf("%s: actions[%d]: device is required", j.Name, k)
+		}
+		switch a.Type {
+		case ActionMelody:
+			if a.notes, err = control.ParseMelody(a.Melody, a.BPM); err != nil {
+				return fmt.Errorf("%s: actions[%d]: %v", j.Name, k, err)
+			}
+		case ActionPattern:
+			if a.Pattern == "" || strings.Trim(a.Pattern, "01") != "" {
+				return fmt.Errorf("%s: actions[%d]: pattern %q is not 0s and 1s", j.Name, k, a.Pattern)
+			}
+			if a.Step < 0 {
+				return fmt.Errorf("%s: actions[%d]: step %v is negative", j.Name, k, a.Step)
+			}
+		case ActionText, ActionSnapshot:
+		default:
+			return fmt.Errorf("%s: actions[%d]: unknown type %q", j.Name, k, a.Type)
+		}
+	}
+	return nil
+}
+
+// Next returns the first run of j after t, zero without one.
+func (j *Job) Next(t time.Time) time.Time {
+	return j.cron.Next(t)
+}
+
+// Run is a time a job runs.
+type Run struct {
+	Time time.Time
+	Job  *Job
+}
+
+// Upcoming returns the first n runs of jobs after t, by time.
+func Upcoming(jobs []Job, t time.Time, n int) []Run {
+	var runs []Run
+	for i := range jobs {
+		j := &jobs[i]
+		next := t
+		for k := 0; k < n; k++ {
+			if next = j.Next(next); next.IsZero() {
+				break
+			}
+			runs = append(runs, Run{Time: next, Job: j})
+		}
+	}
+	sort.SliceStable(runs, func(a, b int) bool { return runs[a].Time.Before(runs[b].Time) })
+	if len(runs) > n {
+		runs = runs[:n]
+	}
+	return runs
+}
@@ -0,0 +1,282 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"pi/control"
+	"pi/dev"
+	"pi/event"
+	"pi/log"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Late is how long after its time a run is missed rather than delayed.
+const Late = time.Minute
+
+// tick is how often the time is checked, it may jump when the clock is set.
+const tick = time.Second
+
+// placeholder is a {device/name} in the text of an action.
+var placeholder = regexp.MustCompile(`\{([^{}/]+)/([^{}/]+)\}`)
+
+// Scheduler runs the actions of the jobs when due. Without a known time
+// nothing runs, the runs missed meanwhile or during a power loss follow
+// the policy of their job.
+type Scheduler struct {
+	ctl   *control.Controller
+	bus   *event.Bus
+	now   func() (time.Time, bool)
+	state string
+
+	mu   sync.Mutex
+	jobs []*job
+	last map[string]time.Time
+}
+
+// job is the next run of a Job.
+type job struct {
+	Job
+	next time.Time
+}
+
+// New creates a new Scheduler running the actions with ctl, on the time of
+// now. The last runs are kept in the file state, if set.
+func New(ctl *control.Controller, bus *event.Bus, now func() (time.Time, bool), state string) *Scheduler {
+	s := &Scheduler{ctl: ctl, bus: bus, now: now, state: state, last: make(map[string]time.Time)}
+	if state != "" {
+		last, err := LoadState(state)
+		if err != nil {
+			log.Default().Error("schedule state error: ", err)
+		} else {
+			s.last = last
+		}
+	}
+	return s
+}
+
+// LoadState reads the last runs by job name of the file path, empty when
+// it does not exist.
+func LoadState(path string) (map[string]time.Time, error) {
+	last := make(map[string]time.Time)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return last, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &last); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return last, nil
+}
+
+// save writes the last runs, replacing the file so that a power loss
+// leaves the previous one.
+func (s *Scheduler) save() {
+	if s.state == "" {
+		return
+	}
+	data, err := json.Marshal(s.last)
+	if err == nil {
+		tmp := s.state + ".tmp"
+		if err = ioutil.WriteFile(tmp, data, 0644); err == nil {
+			err = os.Rename(tmp, s.state)
+		}
+	}
+	if err != nil {
+		log.Default().Error("schedule state error: ", err)
+	}
+}
+
+// SetJobs replaces the jobs, unchanged ones keep their next run.
+func (s *Scheduler) SetJobs(jobs []Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old := make(map[string]*job, len(s.jobs))
+	for _, j := range s.jobs {
+		old[j.Name] = j
+	}
+	s.jobs = make([]*job, 0, len(jobs))
+	for _, j := range jobs {
+		if o, ok := old[j.Name]; ok && reflect.DeepEqual(o.Job, j) {
+			s.jobs = append(s.jobs, o)
+			continue
+		}
+		s.jobs = append(s.jobs, &job{Job: j})
+	}
+}
+
+// Next returns the next run of the jobs by name, zero while the time is
+// unknown.
+func (s *Scheduler) Next() map[string]time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := make(map[string]time.Time, len(s.jobs))
+	for _, j := range s.jobs {
+		next[j.Name] = j.next
+	}
+	return next
+}
+
+// Run runs the jobs until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		s.check()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// check runs the due jobs. The first run of a job is planned after its
+// last one, so those missed while stopped are due at once.
+func (s *Scheduler) check() {
+	now, ok := s.now()
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := false
+	for _, j := range s.jobs {
+		if j.next.IsZero() {
+			from := now
+			if last, ok := s.last[j.Name]; ok && last.Before(now) {
+				from = last
+			}
+			if j.next = j.Next(from); j.next.IsZero() {
+				continue
+			}
+		}
+		if now.Before(j.next) {
+			continue
+		}
+		due := j.next
+		j.next = j.Next(now)
+		s.last[j.Name], changed = now, true
+		if now.Sub(due) >= Late && j.Missed != MissedOnce {
+			log.Default().Infof("schedule %s skipped the run of %s", j.Name, due.Format(time.RFC3339))
+			continue
+		}
+		log.Default().Infof("schedule %s run of %s", j.Name, due.Format(time.RFC3339))
+		go s.run(j.Actions, now)
+	}
+	if changed {
+		s.save()
+	}
+}
+
+// run runs actions, the errors are logged.
+func (s *Scheduler) run(actions []Action, now time.Time) {
+	for _, a := range actions {
+		var err error
+		switch a.Type {
+		case ActionMelody:
+			_, err = s.ctl.Play(a.Device, a.notes)
+		case ActionPattern:
+			err = s.pattern(a)
+		case ActionText:
+			err = s.ctl.ShowText(a.Device, dev.PosTopLeft, s.expand(a.Text), control.DefaultHold)
+		case ActionSnapshot:
+			err = s.snapshot(a, now)
+		}
+		if err != nil {
+			log.Default().Warnf("schedule action %s %s error: %v", a.Type, a.Device, err)
+		}
+	}
+}
+
+// pattern switches the LED of a in the background, holding it from its
+// worker meanwhile.
+func (s *Scheduler) pattern(a Action) error {
+	step := a.Step
+	if step == 0 {
+		step = DefaultStep
+	}
+	hold := time.Duration(len(a.Pattern))*step + control.DefaultHold
+	set := func(c byte) error {
+		state := "off"
+		if c == '1' {
+			state = "on"
+		}
+		_, err := s.ctl.Switch(a.Device, state, hold)
+		return err
+	}
+	if err := set(a.Pattern[0]); err != nil {
+		return err
+	}
+	go func() {
+		for i := 1; i < len(a.Pattern); i++ {
+			time.Sleep(step)
+			if err := set(a.Pattern[i]); err != nil {
+				log.Default().Warnf("schedule pattern %s error: %v", a.Device, err)
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// expand replaces the {device/name} of text by the latest readings, ? when
+// unknown.
+func (s *Scheduler) expand(text string) string {
+	return placeholder.ReplaceAllStringFunc(text, func(m string) string {
+		f := placeholder.FindStringSubmatch(m)
+		if e, ok := s.ctl.Readings(f[1])[f[2]]; ok {
+			return fmt.Sprintf("%.1f", e.Value)
+		}
+		return "?"
+	})
+}
+
+// snapshot logs the readings of a and appends them to its Path.
+func (s *Scheduler) snapshot(a Action, now time.Time) error {
+	readings := []event.Event{}
+	for _, e := range s.bus.Snapshot() {
+		if e.Unit != "" && (a.Device == "" || e.Device == a.Device) {
+			readings = append(readings, e)
+		}
+	}
+	sort.Slice(readings, func(i, j int) bool {
+		if readings[i].Device != readings[j].Device {
+			return readings[i].Device < readings[j].Device
+		}
+		return readings[i].Name < readings[j].Name
+	})
+	values := make([]string, len(readings))
+	for i, e := range readings {
+		values[i] = fmt.Sprintf("%s/%s %g %s", e.Device, e.Name, e.Value, e.Unit)
+	}
+	log.Default().Info("snapshot: ", strings.Join(values, ", "))
+	if a.Path == "" {
+		return nil
+	}
+	line, err := json.Marshal(struct {
+		Time     time.Time     `json:"time"`
+		Readings []event.Event `json:"readings"`
+	}{now, readings})
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(a.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
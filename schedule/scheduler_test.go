@@ -0,0 +1,164 @@
+package schedule
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"pi/control"
+	"pi/dev"
+	"pi/event"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// fakeLED counts its switches.
+type fakeLED struct {
+	mu       sync.Mutex
+	status   int
+	switches int
+}
+
+func (l *fakeLED) Name() string                   { return "led1" }
+func (l *fakeLED) Init(ctx context.Context) error { return nil }
+func (l *fakeLED) Close() error                   { return nil }
+func (l *fakeLED) Health() dev.Health             { return dev.Health{State: dev.HealthPresent} }
+
+func (l *fakeLED) set(status int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.status, l.switches = status, l.switches+1
+	return nil
+}
+
+func (l *fakeLED) On() error     { return l.set(1) }
+func (l *fakeLED) Off() error    { return l.set(0) }
+func (l *fakeLED) Toggle() error { return l.set(l.Status() ^ 1) }
+func (l *fakeLED) Status() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.status
+}
+
+func (l *fakeLED) Switches() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.switches
+}
+
+// wait waits up to 5s for cond.
+func wait(cond func() bool) bool {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	return cond()
+}
+
+func TestScheduler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "schedule")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	state := filepath.Join(dir, "state.json")
+	snapshots := filepath.Join(dir, "snapshots.jsonl")
+	// The power was lost at 6:00 and is back at 7:00.
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	if err := ioutil.WriteFile(state, []byte(`{"wake":"2026-10-19T06:00:00Z","quiet":"2026-10-19T06:00:00Z"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	v := viper.New()
+	v.Set("schedule.jobs", []map[string]interface{}{
+		{"name": "wake", "cron": "30 6 * * *", "missed": "once", "actions": []map[string]interface{}{
+			{"type": "pattern", "device": "led1", "pattern": "1"},
+		}},
+		{"name": "quiet", "cron": "30 6 * * *", "actions": []map[string]interface{}{
+			{"type": "pattern", "device": "led1", "pattern": "0"},
+		}},
+		{"name": "log", "cron": "* * * * *", "actions": []map[string]interface{}{
+			{"type": "snapshot", "device": "temp", "path": snapshots},
+		}},
+	})
+	o, err := NewOptions(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	led := &fakeLED{}
+	health := dev.NewHealthRegistry()
+	health.Add("led1", led)
+	bus := event.New()
+	bus.Publish(event.Event{Device: "temp", Name: "temperature", Value: 21.5, Unit: "°C", Time: day})
+	var now time.Time
+	s := New(control.New(health, bus, nil), bus, func() (time.Time, bool) { return now, !now.IsZero() }, state)
+	s.SetJobs(o.Jobs)
+	s.check()
+	if next := s.Next(); !next["wake"].IsZero() {
+		t.Errorf("planned without a time: %v", next)
+	}
+
+	now = day.Add(7*time.Hour + 30*time.Second)
+	s.check()
+	if !wait(func() bool { return led.Status() == 1 }) || led.Switches() != 1 {
+		t.Errorf("led1 status %d after %d switches, want only the missed wake", led.Status(), led.Switches())
+	}
+	next := s.Next()
+	if !next["wake"].Equal(day.Add(30*time.Hour+30*time.Minute)) || !next["log"].Equal(day.Add(7*time.Hour+time.Minute)) {
+		t.Errorf("next %v", next)
+	}
+	last, err := LoadState(state)
+	if err != nil || !last["quiet"].Equal(now) {
+		t.Errorf("state %v, %v", last, err)
+	}
+
+	now = now.Add(time.Minute)
+	s.check()
+	if !wait(func() bool {
+		data, _ := ioutil.ReadFile(snapshots)
+		return strings.Contains(string(data), `"readings":[{"device":"temp","name":"temperature","value":21.5`)
+	}) {
+		t.Error("snapshot not written")
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	v := viper.New()
+	for _, c := range []struct {
+		job  map[string]interface{}
+		want string
+	}{
+		{map[string]interface{}{"name": "n", "cron": "* * *"}, "5 fields"},
+		{map[string]interface{}{"name": "n", "cron": "@daily", "missed": "all"}, "missed"},
+		{map[string]interface{}{"name": "n", "cron": "@daily", "actions": []map[string]interface{}{{"type": "melody", "device": "buzzer", "melody": "C4 X4"}}}, "unknown name X"},
+		{map[string]interface{}{"name": "n", "cron": "@daily", "actions": []map[string]interface{}{{"type": "melody", "device": "buzzer", "melody": "C4 C7"}}}, "over 1000 Hz"},
+		{map[string]interface{}{"name": "n", "cron": "@daily", "actions": []map[string]interface{}{{"type": "pattern", "device": "led1", "pattern": "on"}}}, "0s and 1s"},
+		{map[string]interface{}{"name": "n", "cron": "@daily", "actions": []map[string]interface{}{{"type": "text", "text": "hi"}}}, "device is required"},
+	} {
+		v.Set("schedule.jobs", []map[string]interface{}{c.job})
+		if _, err := NewOptions(v); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%v: %v, want %s", c.job, err, c.want)
+		}
+	}
+
+	v.Set("schedule.jobs", []map[string]interface{}{
+		{"name": "hourly", "cron": "@hourly"},
+		{"name": "quarter", "cron": "*/15 * * * *"},
+	})
+	o, err := NewOptions(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runs := Upcoming(o.Jobs, time.Date(2026, 10, 19, 12, 50, 0, 0, time.UTC), 4)
+	var got []string
+	for _, r := range runs {
+		got = append(got, r.Time.Format("15:04")+" "+r.Job.Name)
+	}
+	if want := "13:00 hourly,13:00 quarter,13:15 quarter,13:30 quarter"; strings.Join(got, ",") != want {
+		t.Errorf("upcoming %v, want %s", got, want)
+	}
+}
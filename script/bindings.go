@@ -0,0 +1,229 @@
+package script
+
+import (
+	"context"
+	"fmt"
+	"pi/control"
+	"pi/dev"
+	"pi/log"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// positions are the OLED text positions by name.
+var positions = map[string]dev.SSD1306Pos{
+	"top-center":    dev.PosTopCenter,
+	"top-left":      dev.PosTopLeft,
+	"top-right":     dev.PosTopRight,
+	"bottom-left":   dev.PosBottomLeft,
+	"bottom-right":  dev.PosBottomRight,
+	"bottom-center": dev.PosBottomCenter,
+}
+
+// port is the expander of a pcf8574-led device.
+type port interface {
+	Read() (byte, error)
+	Write(port byte) error
+}
+
+// bind sets the globals of the script name:
+//
+//	led.on(name) led.off(name) led.toggle(name) led.status(name)
+//	pcf8574.read(name) pcf8574.write(name, byte)
+//	buzzer.tone(name, hz, seconds) buzzer.play(name, melody[, bpm]) buzzer.stop(name)
+//	ds18b20.read(name)
+//	ds3231.now(name) ds3231.time(name)
+//	oled.text(name, text[, position]) oled.clear(name)
+//	sleep(seconds) now() log(...) print(...)
+//
+// The device names are those of the configuration, the errors are raised.
+func (r *Runner) bind(L *lua.LState, name string) {
+	module := func(global string, funcs map[string]lua.LGFunction) {
+		L.SetGlobal(global, L.SetFuncs(L.NewTable(), funcs))
+	}
+	module("led", map[string]lua.LGFunction{
+		"on":     r.switchLED("on"),
+		"off":    r.switchLED("off"),
+		"toggle": r.switchLED("toggle"),
+		"status": func(L *lua.LState) int {
+			d, err := r.ctl.Device(L.CheckString(1))
+			check(L, err)
+			s, ok := d.(control.Switch)
+			if !ok {
+				L.RaiseError("%s is not a LED", L.CheckString(1))
+			}
+			L.Push(lua.LBool(s.Status() == 1))
+			return 1
+		},
+	})
+	module("pcf8574", map[string]lua.LGFunction{
+		"read": func(L *lua.LState) int {
+			b, err := r.port(L).Read()
+			check(L, err)
+			L.Push(lua.LNumber(b))
+			return 1
+		},
+		"write": func(L *lua.LState) int {
+			p := r.port(L)
+			v := L.CheckInt(2)
+			if v < 0 || v > 0xff {
+				L.ArgError(2, "not a byte")
+			}
+			check(L, p.Write(byte(v)))
+			return 0
+		},
+	})
+	module("buzzer", map[string]lua.LGFunction{
+		"tone": func(L *lua.LState) int {
+			d := time.Duration(float64(L.CheckNumber(3)) * float64(time.Second))
+			r.play(L, []control.Note{{Hz: float64(L.CheckNumber(2)), Duration: d}})
+			return 0
+		},
+		"play": func(L *lua.LState) int {
+			notes, err := control.ParseMelody(L.CheckString(2), float64(L.OptNumber(3, 0)))
+			check(L, err)
+			r.play(L, notes)
+			return 0
+		},
+		"stop": func(L *lua.LState) int {
+			r.ctl.Stop(L.CheckString(1))
+			return 0
+		},
+	})
+	module("ds18b20", map[string]lua.LGFunction{
+		"read": func(L *lua.LState) int {
+			d, err := r.ctl.Device(L.CheckString(1))
+			check(L, err)
+			s, ok := d.(*dev.DS18B20)
+			if !ok {
+				L.RaiseError("%s is not a DS18B20", L.CheckString(1))
+			}
+			check(L, s.FetchTemperate())
+			L.Push(lua.LNumber(s.Temperate()))
+			return 1
+		},
+	})
+	module("ds3231", map[string]lua.LGFunction{
+		"now": func(L *lua.LState) int {
+			t, err := r.rtc(L).Now()
+			check(L, err)
+			L.Push(lua.LNumber(t.Unix()))
+			return 1
+		},
+		"time": func(L *lua.LState) int {
+			t, err := r.rtc(L).Time()
+			check(L, err)
+			L.Push(lua.LString(t))
+			return 1
+		},
+	})
+	module("oled", map[string]lua.LGFunction{
+		"text": func(L *lua.LState) int {
+			pos, ok := positions[L.OptString(3, "top-left")]
+			if !ok {
+				L.ArgError(3, "unknown position")
+			}
+			check(L, r.ctl.ShowText(L.CheckString(1), pos, L.CheckString(2), control.DefaultHold))
+			return 0
+		},
+		"clear": func(L *lua.LState) int {
+			check(L, r.ctl.ShowText(L.CheckString(1), dev.PosTopLeft, "", control.DefaultHold))
+			return 0
+		},
+	})
+	L.SetGlobal("sleep", L.NewFunction(func(L *lua.LState) int {
+		wait(L, time.Duration(float64(L.CheckNumber(1))*float64(time.Second)))
+		return 0
+	}))
+	L.SetGlobal("now", L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LNumber(float64(time.Now().UnixNano()) / float64(time.Second)))
+		return 1
+	}))
+	L.SetGlobal("log", L.NewFunction(func(L *lua.LState) int {
+		log.Default().Infof("script %s: %s", name, args(L))
+		return 0
+	}))
+	L.SetGlobal("print", L.NewFunction(func(L *lua.LState) int {
+		fmt.Fprintln(r.Out, args(L))
+		return 0
+	}))
+}
+
+// switchLED returns the led function setting state.
+func (r *Runner) switchLED(state string) lua.LGFunction {
+	return func(L *lua.LState) int {
+		on, err := r.ctl.Switch(L.CheckString(1), state, control.DefaultHold)
+		check(L, err)
+		L.Push(lua.LBool(on))
+		return 1
+	}
+}
+
+// port returns the expander of the first argument.
+func (r *Runner) port(L *lua.LState) port {
+	d, err := r.ctl.Device(L.CheckString(1))
+	check(L, err)
+	p, ok := d.(port)
+	if !ok {
+		L.RaiseError("%s is not a PCF8574", L.CheckString(1))
+	}
+	return p
+}
+
+// rtc returns the DS3231 of the first argument.
+func (r *Runner) rtc(L *lua.LState) *dev.DS3231 {
+	d, err := r.ctl.Device(L.CheckString(1))
+	check(L, err)
+	rtc, ok := d.(*dev.DS3231)
+	if !ok {
+		L.RaiseError("%s is not a DS3231", L.CheckString(1))
+	}
+	return rtc
+}
+
+// play plays notes on the buzzer of the first argument until they end,
+// stopping them with the script.
+func (r *Runner) play(L *lua.LState, notes []control.Note) {
+	name := L.CheckString(1)
+	_, err := r.ctl.Play(name, notes)
+	check(L, err)
+	ctx := L.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := r.ctl.Wait(ctx, name); err != nil {
+		r.ctl.Stop(name)
+		L.RaiseError("%v", err)
+	}
+}
+
+// wait sleeps d or until the script is stopped.
+func wait(L *lua.LState, d time.Duration) {
+	ctx := L.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	select {
+	case <-ctx.Done():
+		L.RaiseError("%v", ctx.Err())
+	case <-time.After(d):
+	}
+}
+
+// check raises err, if any.
+func check(L *lua.LState, err error) {
+	if err != nil {
+		L.RaiseError("%v", err)
+	}
+}
+
+// args returns the arguments of L separated by tabs.
+func args(L *lua.LState) string {
+	s := make([]string, L.GetTop())
+	for i := range s {
+		s[i] = L.ToStringMeta(L.Get(i + 1)).String()
+	}
+	return strings.Join(s, "\t")
+}
@@ -0,0 +1,148 @@
+// Package script runs Lua scripts automating the board, with bindings to
+// the devices of a control.Controller. The scripts have no access to the
+// files, the operating system or other modules and are stopped at a time
+// limit.
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"pi/control"
+	"pi/log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Ext is the extension of the scripts.
+const Ext = ".lua"
+
+// ErrTimeout is a script stopped at its time limit.
+var ErrTimeout = errors.New("time limit exceeded")
+
+// Options is the script section of the configuration.
+type Options struct {
+	// Dir is the directory of the scripts.
+	Dir string
+	// Timeout is the longest a script runs.
+	Timeout time.Duration
+}
+
+// NewOptions reads the script section of the configuration.
+func NewOptions(v *viper.Viper) (*Options, error) {
+	o := &Options{Dir: "/etc/Pioneer600/scripts", Timeout: time.Minute}
+	if err := v.UnmarshalKey("script", o); err != nil {
+		return nil, err
+	}
+	if o.Timeout <= 0 {
+		return nil, fmt.Errorf("script timeout %v must be positive", o.Timeout)
+	}
+	return o, nil
+}
+
+// Scripts returns the names of the scripts of o.Dir, sorted.
+func (o *Options) Scripts() ([]string, error) {
+	files, err := ioutil.ReadDir(o.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), Ext) {
+			names = append(names, strings.TrimSuffix(f.Name(), Ext))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Path returns the file of the script name of o.Dir, name may have the
+// extension. A name with a separator is a file of its own.
+func (o *Options) Path(name string) string {
+	if strings.ContainsRune(name, filepath.Separator) {
+		return name
+	}
+	if !strings.HasSuffix(name, Ext) {
+		name += Ext
+	}
+	return filepath.Join(o.Dir, name)
+}
+
+// libs are the Lua libraries of the scripts, without io, os, package and
+// debug.
+var libs = []struct {
+	name string
+	open lua.LGFunction
+}{
+	{lua.BaseLibName, lua.OpenBase},
+	{lua.TabLibName, lua.OpenTable},
+	{lua.StringLibName, lua.OpenString},
+	{lua.MathLibName, lua.OpenMath},
+	{lua.CoroutineLibName, lua.OpenCoroutine},
+}
+
+// unsafe are the base functions reading files or loading code.
+var unsafe = []string{"dofile", "loadfile", "load", "loadstring", "module", "require"}
+
+// Runner runs scripts on the devices of a controller.
+type Runner struct {
+	ctl     *control.Controller
+	timeout time.Duration
+	// Out receives the print of the scripts.
+	Out io.Writer
+}
+
+// New creates a new Runner stopping the scripts after timeout.
+func New(ctl *control.Controller, timeout time.Duration) *Runner {
+	return &Runner{ctl: ctl, timeout: timeout, Out: os.Stdout}
+}
+
+// RunFile runs the script of path until it ends, fails, ctx is done or
+// the time limit.
+func (r *Runner) RunFile(ctx context.Context, path string) error {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return r.Run(ctx, strings.TrimSuffix(filepath.Base(path), Ext), string(src))
+}
+
+// Run runs the script src named name.
+func (r *Runner) Run(ctx context.Context, name, src string) error {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true, CallStackSize: 256, RegistryMaxSize: 1 << 20})
+	defer L.Close()
+	for _, lib := range libs {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	for _, fn := range unsafe {
+		L.SetGlobal(fn, lua.LNil)
+	}
+	r.bind(L, name)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	L.SetContext(ctx)
+	start := time.Now()
+	fn, err := L.Load(strings.NewReader(src), name+Ext)
+	if err == nil {
+		L.Push(fn)
+		err = L.PCall(0, 0, nil)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("script %s: %w after %v", name, ErrTimeout, r.timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("script %s: %v", name, err)
+	}
+	log.Default().Debugf("script %s done in %v", name, time.Since(start).Round(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,165 @@
+package script
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"pi/control"
+	"pi/dev"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// fakeLED is a LED on a PCF8574 port.
+type fakeLED struct {
+	mu   sync.Mutex
+	port byte
+}
+
+func (l *fakeLED) Name() string                   { return "led2" }
+func (l *fakeLED) Init(ctx context.Context) error { return nil }
+func (l *fakeLED) Close() error                   { return nil }
+func (l *fakeLED) Health() dev.Health             { return dev.Health{State: dev.HealthPresent} }
+
+func (l *fakeLED) Read() (byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.port, nil
+}
+
+func (l *fakeLED) Write(port byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.port = port
+	return nil
+}
+
+func (l *fakeLED) On() error  { return l.Write(0xef) }
+func (l *fakeLED) Off() error { return l.Write(0xff) }
+func (l *fakeLED) Toggle() error {
+	p, _ := l.Read()
+	return l.Write(p ^ 0x10)
+}
+func (l *fakeLED) Status() int {
+	if p, _ := l.Read(); p&0x10 == 0 {
+		return 1
+	}
+	return 0
+}
+
+// fakeBuzzer records the notes played.
+type fakeBuzzer struct {
+	mu    sync.Mutex
+	notes []float64
+}
+
+func (b *fakeBuzzer) Name() string                   { return "buzzer" }
+func (b *fakeBuzzer) Init(ctx context.Context) error { return nil }
+func (b *fakeBuzzer) Close() error                   { return nil }
+func (b *fakeBuzzer) Health() dev.Health             { return dev.Health{State: dev.HealthPresent} }
+
+func (b *fakeBuzzer) Play(ctx context.Context, hz float64, d time.Duration) error {
+	b.mu.Lock()
+	b.notes = append(b.notes, hz)
+	b.mu.Unlock()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+func newRunner(timeout time.Duration) (*Runner, *fakeLED, *fakeBuzzer, *bytes.Buffer) {
+	led, buzzer := &fakeLED{port: 0xff}, &fakeBuzzer{}
+	health := dev.NewHealthRegistry()
+	health.Add("led2", led)
+	health.Add("buzzer", buzzer)
+	r := New(control.New(health, nil, nil), timeout)
+	out := &bytes.Buffer{}
+	r.Out = out
+	return r, led, buzzer, out
+}
+
+func TestRun(t *testing.T) {
+	r, led, buzzer, out := newRunner(5 * time.Second)
+	err := r.Run(context.Background(), "test", `
+		led.on("led2")
+		print(led.status("led2"), pcf8574.read("led2"))
+		pcf8574.write("led2", 0xff)
+		buzzer.play("buzzer", "A4:0.1 R:0.1 A5:0.1", 600)
+		buzzer.tone("buzzer", 880, 0.01)
+		sleep(0.01)
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "true\t239\n" {
+		t.Errorf("print %q", out.String())
+	}
+	if led.Status() != 0 {
+		t.Error("led2 still on")
+	}
+	if len(buzzer.notes) != 4 || buzzer.notes[0] != 440 || buzzer.notes[3] != 880 {
+		t.Errorf("notes %v", buzzer.notes)
+	}
+
+	for src, want := range map[string]string{
+		`led.on("humidity")`:      "unknown device",
+		`ds3231.now("buzzer")`:    "buzzer is not a DS3231",
+		`oled.text("led2", "hi")`: "led2 is not a display",
+		`dofile("/etc/passwd")`:   "attempt to call a non-function",
+		`os.exit(1)`:              "attempt to index a non-table",
+	} {
+		if err := r.Run(context.Background(), "bad", src); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: %v, want %s", src, err, want)
+		}
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	r, _, _, _ := newRunner(50 * time.Millisecond)
+	for _, src := range []string{"while true do end", "sleep(10)", `buzzer.tone("buzzer", 440, 10)`} {
+		start := time.Now()
+		if err := r.Run(context.Background(), "loop", src); !errors.Is(err, ErrTimeout) {
+			t.Errorf("%s: %v, want %v", src, err, ErrTimeout)
+		}
+		if d := time.Since(start); d > 2*time.Second {
+			t.Errorf("%s stopped after %v", src, d)
+		}
+	}
+}
+
+func TestOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "script")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"blink.lua", "alarm.lua", "notes.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(`print("hi")`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	v := viper.New()
+	v.Set("script.dir", dir)
+	o, err := NewOptions(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, err := o.Scripts()
+	if err != nil || strings.Join(names, ",") != "alarm,blink" {
+		t.Errorf("scripts %v, %v", names, err)
+	}
+	r, _, _, out := newRunner(o.Timeout)
+	if err := r.RunFile(context.Background(), o.Path("blink")); err != nil || out.String() != "hi\n" {
+		t.Errorf("blink %q, %v", out.String(), err)
+	}
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"image"
+	"math"
+	"time"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// Clock is an analog clock face with hour, minute and optional second hands.
+type Clock struct {
+	box
+	seconds bool
+	t       time.Time
+}
+
+// NewClock creates a new Clock face inscribed in r.
+func NewClock(r image.Rectangle, seconds bool) *Clock {
+	return &Clock{box: newBox(r), seconds: seconds}
+}
+
+// SetTime updates the time, the widget is dirty only when a hand moves.
+func (w *Clock) SetTime(t time.Time) {
+	res := time.Minute
+	if w.seconds {
+		res = time.Second
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !t.Truncate(res).Equal(w.t.Truncate(res)) {
+		w.dirty = true
+	}
+	w.t = t
+}
+
+// Draw renders the dial, the twelve hour ticks and the hands.
+func (w *Clock) Draw(dst *image1bit.VerticalLSB) {
+	w.begin(dst)
+	defer w.end()
+	r := w.rect
+	radius := r.Dx()
+	if r.Dy() < radius {
+		radius = r.Dy()
+	}
+	radius = radius/2 - 1
+	cx, cy := r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2
+	circle(dst, cx, cy, radius)
+	for h := 0; h < 12; h++ {
+		x0, y0 := polar(cx, cy, float64(radius)*0.85, float64(h)/12)
+		x1, y1 := polar(cx, cy, float64(radius), float64(h)/12)
+		line(dst, x0, y0, x1, y1)
+	}
+	hour := (float64(w.t.Hour()%12) + float64(w.t.Minute())/60) / 12
+	minute := (float64(w.t.Minute()) + float64(w.t.Second())/60) / 60
+	x, y := polar(cx, cy, float64(radius)*0.5, hour)
+	line(dst, cx, cy, x, y)
+	x, y = polar(cx, cy, float64(radius)*0.75, minute)
+	line(dst, cx, cy, x, y)
+	if w.seconds {
+		x, y = polar(cx, cy, float64(radius)*0.9, float64(w.t.Second())/60)
+		line(dst, cx, cy, x, y)
+	}
+}
+
+// polar returns the point at length from the center, turn is the fraction of
+// a clockwise revolution starting at 12 o'clock.
+func polar(cx, cy int, length, turn float64) (int, int) {
+	a := turn * 2 * math.Pi
+	return cx + int(math.Round(length*math.Sin(a))), cy - int(math.Round(length*math.Cos(a)))
+}
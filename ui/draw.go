@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"image"
+	"pi/dev"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// fill sets every pixel of r to b.
+func fill(dst *image1bit.VerticalLSB, r image.Rectangle, b image1bit.Bit) {
+	r = r.Intersect(dst.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			dst.SetBit(x, y, b)
+		}
+	}
+}
+
+// invert flips every pixel of r.
+func invert(dst *image1bit.VerticalLSB, r image.Rectangle) {
+	r = r.Intersect(dst.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			dst.SetBit(x, y, !dst.BitAt(x, y))
+		}
+	}
+}
+
+// outline draws the one pixel border of r.
+func outline(dst *image1bit.VerticalLSB, r image.Rectangle) {
+	if r.Empty() {
+		return
+	}
+	line(dst, r.Min.X, r.Min.Y, r.Max.X-1, r.Min.Y)
+	line(dst, r.Min.X, r.Max.Y-1, r.Max.X-1, r.Max.Y-1)
+	line(dst, r.Min.X, r.Min.Y, r.Min.X, r.Max.Y-1)
+	line(dst, r.Max.X-1, r.Min.Y, r.Max.X-1, r.Max.Y-1)
+}
+
+// set lights (x, y) if it is inside dst.
+func set(dst *image1bit.VerticalLSB, x, y int) {
+	if (image.Point{X: x, Y: y}).In(dst.Rect) {
+		dst.SetBit(x, y, image1bit.On)
+	}
+}
+
+// line draws a Bresenham line between both end points included.
+func line(dst *image1bit.VerticalLSB, x0, y0, x1, y1 int) {
+	dx, sx := x1-x0, 1
+	if dx < 0 {
+		dx, sx = -dx, -1
+	}
+	dy, sy := y1-y0, 1
+	if dy < 0 {
+		dy, sy = -dy, -1
+	}
+	e := dx - dy
+	for {
+		set(dst, x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 > -dy {
+			e -= dy
+			x0 += sx
+		}
+		if e2 < dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// circle draws a midpoint circle of radius r around (cx, cy).
+func circle(dst *image1bit.VerticalLSB, cx, cy, r int) {
+	x, y, e := r, 0, 1-r
+	for x >= y {
+		for _, p := range [][2]int{{x, y}, {y, x}, {-y, x}, {-x, y}, {-x, -y}, {-y, -x}, {y, -x}, {x, -y}} {
+			set(dst, cx+p[0], cy+p[1])
+		}
+		y++
+		if e < 0 {
+			e += 2*y + 1
+		} else {
+			x--
+			e += 2*(y-x) + 1
+		}
+	}
+}
+
+// bitmap draws rows of a 1-bit sprite, MSB is the left most pixel.
+func bitmap(dst *image1bit.VerticalLSB, p image.Point, rows []uint16, width int) {
+	for y, row := range rows {
+		for x := 0; x < width; x++ {
+			if row&(1<<uint(15-x)) != 0 {
+				set(dst, p.X+x, p.Y+y)
+			}
+		}
+	}
+}
+
+// clip restricts drawing on a frame to a rectangle.
+type clip struct {
+	*image1bit.VerticalLSB
+	r image.Rectangle
+}
+
+// Bounds implements image.Image.
+func (c clip) Bounds() image.Rectangle {
+	return c.r.Intersect(c.VerticalLSB.Rect)
+}
+
+// text draws s clipped to r, p is the top left corner of the first glyph.
+func text(dst *image1bit.VerticalLSB, r image.Rectangle, p image.Point, s string) {
+	dev.DrawString(clip{VerticalLSB: dst, r: r}, p, s)
+}
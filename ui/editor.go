@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"pi/dev"
+	"time"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// Spinner edits a number with up/down, select confirms and left cancels.
+type Spinner struct {
+	Title string
+	Value float64
+	Min   float64
+	Max   float64
+	Step  float64
+	// Format is the fmt verb for the value, "%.0f" when empty.
+	Format string
+	// OnDone receives the value when the user confirms.
+	OnDone func(v float64)
+}
+
+// Draw renders the title and the value between arrows.
+func (s *Spinner) Draw(dst *image1bit.VerticalLSB) {
+	r := drawTitle(dst, s.Title)
+	format := s.Format
+	if format == "" {
+		format = "%.0f"
+	}
+	y := r.Min.Y + (r.Dy()-dev.FontHeight)/2
+	drawCentered(dst, r, y, []string{"< " + fmt.Sprintf(format, s.Value) + " >"})
+}
+
+// Handle changes the value by Step, clamped to [Min, Max].
+func (s *Spinner) Handle(nav *Navigator, ev Event) {
+	switch ev {
+	case EventUp, EventRight:
+		s.Value += s.Step
+	case EventDown:
+		s.Value -= s.Step
+	case EventSelect:
+		if s.OnDone != nil {
+			s.OnDone(s.Value)
+		}
+		nav.Pop()
+	case EventLeft:
+		nav.Pop()
+	}
+	if s.Value > s.Max {
+		s.Value = s.Max
+	} else if s.Value < s.Min {
+		s.Value = s.Min
+	}
+}
+
+// Toggle edits an on/off setting, up/down/right flip it.
+type Toggle struct {
+	Title  string
+	Value  bool
+	OnDone func(v bool)
+}
+
+// Draw renders both states with the current one inverted.
+func (t *Toggle) Draw(dst *image1bit.VerticalLSB) {
+	r := drawTitle(dst, t.Title)
+	y := r.Min.Y + (r.Dy()-dev.FontHeight)/2
+	cols := SplitH(image.Rect(r.Min.X, y, r.Max.X, y+dev.FontHeight), 1, 1)
+	for i, label := range []string{"OFF", "ON"} {
+		c := cols[i]
+		c.Min.X += (c.Dx() - dev.TextWidth(label) - 4) / 2
+		c.Max.X = c.Min.X + dev.TextWidth(label) + 4
+		text(dst, c, image.Point{X: c.Min.X + 2, Y: c.Min.Y}, label)
+		if (i == 1) == t.Value {
+			invert(dst, c)
+		}
+	}
+}
+
+// Handle flips or confirms the value.
+func (t *Toggle) Handle(nav *Navigator, ev Event) {
+	switch ev {
+	case EventUp, EventDown, EventRight:
+		t.Value = !t.Value
+	case EventSelect:
+		if t.OnDone != nil {
+			t.OnDone(t.Value)
+		}
+		nav.Pop()
+	case EventLeft:
+		nav.Pop()
+	}
+}
+
+// TimeSetter edits hours, minutes and seconds. Left/right move between the
+// fields (left on the hours cancels), up/down adjust, select confirms.
+type TimeSetter struct {
+	Title string
+	Value time.Time
+	// OnDone receives Value with the edited clock, the date is kept.
+	OnDone func(t time.Time)
+	field  int
+}
+
+// Draw renders hh:mm:ss with the edited field inverted.
+func (t *TimeSetter) Draw(dst *image1bit.VerticalLSB) {
+	r := drawTitle(dst, t.Title)
+	s := t.Value.Format("15:04:05")
+	y := r.Min.Y + (r.Dy()-dev.FontHeight)/2
+	x := r.Min.X + (r.Dx()-dev.TextWidth(s))/2
+	text(dst, r, image.Point{X: x, Y: y}, s)
+	fx := x + t.field*3*dev.FontWidth
+	invert(dst, image.Rect(fx, y, fx+2*dev.FontWidth, y+dev.FontHeight))
+}
+
+// Handle moves between or adjusts the fields, wrapping within each field.
+func (t *TimeSetter) Handle(nav *Navigator, ev Event) {
+	step := []time.Duration{time.Hour, time.Minute, time.Second}[t.field]
+	switch ev {
+	case EventUp:
+		t.Value = t.adjust(step)
+	case EventDown:
+		t.Value = t.adjust(-step)
+	case EventRight:
+		if t.field < 2 {
+			t.field++
+		}
+	case EventLeft:
+		if t.field == 0 {
+			nav.Pop()
+			return
+		}
+		t.field--
+	case EventSelect:
+		if t.OnDone != nil {
+			t.OnDone(t.Value)
+		}
+		nav.Pop()
+	}
+}
+
+// adjust adds d to the edited field without carrying into the others.
+func (t *TimeSetter) adjust(d time.Duration) time.Time {
+	h, m, s := t.Value.Clock()
+	switch t.field {
+	case 0:
+		h = (h + int(d/time.Hour) + 24) % 24
+	case 1:
+		m = (m + int(d/time.Minute) + 60) % 60
+	default:
+		s = (s + int(d/time.Second) + 60) % 60
+	}
+	y, mo, day := t.Value.Date()
+	return time.Date(y, mo, day, h, m, s, 0, t.Value.Location())
+}
+
+// Confirm asks a yes/no question, left/right choose and select answers.
+type Confirm struct {
+	Message string
+	// OnDone receives true when the user answers yes.
+	OnDone func(yes bool)
+	yes    bool
+}
+
+// Draw renders the wrapped message and the No/Yes buttons.
+func (c *Confirm) Draw(dst *image1bit.VerticalLSB) {
+	r := dst.Rect
+	lines := wrap(c.Message, r.Dx())
+	rows := (r.Dy() - dev.FontHeight - 2) / dev.FontHeight
+	if len(lines) > rows {
+		lines = lines[:rows]
+	}
+	drawCentered(dst, r, r.Min.Y, lines)
+	y := r.Max.Y - dev.FontHeight
+	cols := SplitH(image.Rect(r.Min.X, y, r.Max.X, r.Max.Y), 1, 1)
+	for i, label := range []string{"No", "Yes"} {
+		b := cols[i]
+		b.Min.X += (b.Dx() - dev.TextWidth(label) - 4) / 2
+		b.Max.X = b.Min.X + dev.TextWidth(label) + 4
+		text(dst, b, image.Point{X: b.Min.X + 2, Y: b.Min.Y}, label)
+		if (i == 1) == c.yes {
+			invert(dst, b)
+		}
+	}
+}
+
+// Handle chooses with left/right and answers on select.
+func (c *Confirm) Handle(nav *Navigator, ev Event) {
+	switch ev {
+	case EventLeft:
+		c.yes = false
+	case EventRight:
+		c.yes = true
+	case EventSelect:
+		nav.Pop()
+		if c.OnDone != nil {
+			c.OnDone(c.yes)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"pi/dev"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// Orientation of a Bar.
+type Orientation int
+
+const (
+	// Horizontal bars fill from left to right.
+	Horizontal Orientation = iota
+	// Vertical bars fill from bottom to top.
+	Vertical
+)
+
+// Readout shows a labelled numeric value with its unit, eg. "Temp 23.5C".
+type Readout struct {
+	box
+	label     string
+	unit      string
+	precision int
+	text      string
+}
+
+// NewReadout creates a new Readout with precision decimals.
+func NewReadout(r image.Rectangle, label, unit string, precision int) *Readout {
+	w := &Readout{box: newBox(r), label: label, unit: unit, precision: precision}
+	w.text = w.format("--")
+	return w
+}
+
+func (w *Readout) format(v string) string {
+	if w.label == "" {
+		return v + w.unit
+	}
+	return w.label + " " + v + w.unit
+}
+
+// SetValue updates the value, the widget is dirty only if the text changes.
+func (w *Readout) SetValue(v float64) {
+	s := w.format(fmt.Sprintf("%.*f", w.precision, v))
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if s != w.text {
+		w.text = s
+		w.dirty = true
+	}
+}
+
+// Draw renders the text vertically centered and clipped to the bounds.
+func (w *Readout) Draw(dst *image1bit.VerticalLSB) {
+	w.begin(dst)
+	defer w.end()
+	y := w.rect.Min.Y + (w.rect.Dy()-dev.FontHeight)/2
+	text(dst, w.rect, image.Point{X: w.rect.Min.X, Y: y}, w.text)
+}
+
+// Bar is a gauge filled in proportion to its value between Min and Max.
+type Bar struct {
+	box
+	orientation Orientation
+	min, max    float64
+	// filled is the number of pixels lit along the orientation.
+	filled int
+}
+
+// NewBar creates a new Bar gauge for values in [min, max].
+func NewBar(r image.Rectangle, o Orientation, min, max float64) *Bar {
+	return &Bar{box: newBox(r), orientation: o, min: min, max: max}
+}
+
+// length returns the interior size along the orientation.
+func (w *Bar) length() int {
+	if w.orientation == Vertical {
+		return w.rect.Dy() - 2
+	}
+	return w.rect.Dx() - 2
+}
+
+// SetValue updates the value, the widget is dirty only if a pixel changes.
+func (w *Bar) SetValue(v float64) {
+	filled := scale(v, w.min, w.max, w.length())
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if filled != w.filled {
+		w.filled = filled
+		w.dirty = true
+	}
+}
+
+// Draw renders the outline and the filled part.
+func (w *Bar) Draw(dst *image1bit.VerticalLSB) {
+	w.begin(dst)
+	defer w.end()
+	outline(dst, w.rect)
+	in := w.rect.Inset(1)
+	if w.orientation == Vertical {
+		in.Min.Y = in.Max.Y - w.filled
+	} else {
+		in.Max.X = in.Min.X + w.filled
+	}
+	fill(dst, in, image1bit.On)
+}
+
+// ProgressBar is a horizontal bar with the percentage printed over it.
+type ProgressBar struct {
+	box
+	percent int
+}
+
+// NewProgressBar creates a new ProgressBar, r should be at least 15 pixels
+// high to fit the text.
+func NewProgressBar(r image.Rectangle) *ProgressBar {
+	return &ProgressBar{box: newBox(r)}
+}
+
+// SetProgress updates the completion ratio in [0, 1].
+func (w *ProgressBar) SetProgress(ratio float64) {
+	percent := scale(ratio, 0, 1, 100)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if percent != w.percent {
+		w.percent = percent
+		w.dirty = true
+	}
+}
+
+// Draw renders the bar and inverts the text where it overlaps the fill.
+func (w *ProgressBar) Draw(dst *image1bit.VerticalLSB) {
+	w.begin(dst)
+	defer w.end()
+	outline(dst, w.rect)
+	in := w.rect.Inset(1)
+	s := fmt.Sprintf("%d%%", w.percent)
+	p := image.Point{
+		X: in.Min.X + (in.Dx()-dev.TextWidth(s))/2,
+		Y: in.Min.Y + (in.Dy()-dev.FontHeight)/2,
+	}
+	text(dst, in, p, s)
+	in.Max.X = in.Min.X + in.Dx()*w.percent/100
+	invert(dst, in)
+}
+
+// Sparkline plots the recent history of a value, one sample per column,
+// scaled to the range of the visible samples.
+type Sparkline struct {
+	box
+	samples []float64
+}
+
+// NewSparkline creates a new Sparkline keeping one sample per pixel column.
+func NewSparkline(r image.Rectangle) *Sparkline {
+	return &Sparkline{box: newBox(r)}
+}
+
+// Push appends a sample, dropping the oldest one when the line is full.
+func (w *Sparkline) Push(v float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.samples = append(w.samples, v)
+	if n := w.rect.Dx(); len(w.samples) > n {
+		w.samples = w.samples[len(w.samples)-n:]
+	}
+	w.dirty = true
+}
+
+// Samples returns a copy of the visible samples.
+func (w *Sparkline) Samples() []float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]float64(nil), w.samples...)
+}
+
+// Draw renders the history right aligned, newest sample on the right.
+func (w *Sparkline) Draw(dst *image1bit.VerticalLSB) {
+	w.begin(dst)
+	defer w.end()
+	if len(w.samples) == 0 {
+		return
+	}
+	lo, hi := w.samples[0], w.samples[0]
+	for _, v := range w.samples {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if hi == lo {
+		hi = lo + 1
+	}
+	h := w.rect.Dy() - 1
+	x0 := w.rect.Max.X - len(w.samples)
+	prev := -1
+	for i, v := range w.samples {
+		y := w.rect.Max.Y - 1 - scale(v, lo, hi, h)
+		if prev < 0 {
+			prev = y
+		}
+		line(dst, x0+i, prev, x0+i, y)
+		prev = y
+	}
+}
+
+// scale maps v from [min, max] to [0, n], clamping out of range values.
+func scale(v, min, max float64, n int) int {
+	if max <= min || math.IsNaN(v) {
+		return 0
+	}
+	r := (v - min) / (max - min)
+	if r < 0 {
+		r = 0
+	} else if r > 1 {
+		r = 1
+	}
+	return int(math.Round(r * float64(n)))
+}
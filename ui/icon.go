@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"image"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// IconSize is the width and height of the status icons.
+const IconSize = 12
+
+// Status icon names for Icon.SetIcon.
+const (
+	IconNone    = ""
+	IconOK      = "ok"
+	IconWarning = "warning"
+	IconError   = "error"
+	IconBell    = "bell"
+	IconLink    = "link"
+)
+
+// icons are 12x12 sprites, MSB first.
+var icons = map[string][]uint16{
+	IconOK: {
+		0x0000, 0x0010, 0x0030, 0x0060, 0x00C0, 0x8180,
+		0xC300, 0x6600, 0x3C00, 0x1800, 0x0000, 0x0000,
+	},
+	IconWarning: {
+		0x0600, 0x0600, 0x0F00, 0x0900, 0x1980, 0x1980,
+		0x3FC0, 0x39C0, 0x7FE0, 0x79E0, 0xFFF0, 0x0000,
+	},
+	IconError: {
+		0x0000, 0x6060, 0x70E0, 0x39C0, 0x1F80, 0x0F00,
+		0x0F00, 0x1F80, 0x39C0, 0x70E0, 0x6060, 0x0000,
+	},
+	IconBell: {
+		0x0600, 0x1F80, 0x3FC0, 0x3FC0, 0x3FC0, 0x3FC0,
+		0x3FC0, 0x7FE0, 0xFFF0, 0x0000, 0x0600, 0x0000,
+	},
+	IconLink: {
+		0x0000, 0x01E0, 0x0330, 0x0230, 0x0460, 0x6CC0,
+		0x9980, 0x9200, 0xC600, 0x7C00, 0x0000, 0x0000,
+	},
+}
+
+// Icon shows one of the status sprites.
+type Icon struct {
+	box
+	name string
+}
+
+// NewIcon creates a new Icon at p showing name.
+func NewIcon(p image.Point, name string) *Icon {
+	return &Icon{box: newBox(image.Rectangle{Min: p, Max: p.Add(image.Point{X: IconSize, Y: IconSize})}), name: name}
+}
+
+// SetIcon switches the sprite, IconNone blanks the widget.
+func (w *Icon) SetIcon(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if name != w.name {
+		w.name = name
+		w.dirty = true
+	}
+}
+
+// Draw renders the sprite.
+func (w *Icon) Draw(dst *image1bit.VerticalLSB) {
+	w.begin(dst)
+	defer w.end()
+	bitmap(dst, w.rect.Min, icons[w.name], IconSize)
+}
+
+// Battery shows a battery outline filled with the charge level.
+type Battery struct {
+	box
+	level    int
+	charging bool
+}
+
+// NewBattery creates a new Battery icon at p, it is 16x8 pixels.
+func NewBattery(p image.Point) *Battery {
+	return &Battery{box: newBox(image.Rectangle{Min: p, Max: p.Add(image.Point{X: 16, Y: 8})})}
+}
+
+// SetLevel updates the charge level in percent and the charging flag.
+func (w *Battery) SetLevel(percent int, charging bool) {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// 12 interior columns, redraw only when one changes.
+	if percent*12/100 != w.level*12/100 || charging != w.charging {
+		w.dirty = true
+	}
+	w.level = percent
+	w.charging = charging
+}
+
+// Draw renders the battery body, terminal and charge.
+func (w *Battery) Draw(dst *image1bit.VerticalLSB) {
+	w.begin(dst)
+	defer w.end()
+	r := w.rect
+	body := image.Rect(r.Min.X, r.Min.Y, r.Max.X-2, r.Max.Y)
+	outline(dst, body)
+	fill(dst, image.Rect(body.Max.X, r.Min.Y+2, r.Max.X, r.Max.Y-2), image1bit.On)
+	in := body.Inset(1)
+	in.Max.X = in.Min.X + in.Dx()*w.level/100
+	fill(dst, in, image1bit.On)
+	if w.charging {
+		// A lightning bolt cut out of the charge.
+		cx, cy := body.Min.X+body.Dx()/2, body.Min.Y+body.Dy()/2
+		invert(dst, image.Rect(cx-2, cy-2, cx, cy))
+		invert(dst, image.Rect(cx, cy, cx+2, cy+2))
+		invert(dst, image.Rect(cx-1, cy-1, cx+1, cy+1))
+	}
+}
+
+// WiFi shows signal strength as four bars.
+type WiFi struct {
+	box
+	bars int
+}
+
+// NewWiFi creates a new WiFi icon at p, it is 12x12 pixels.
+func NewWiFi(p image.Point) *WiFi {
+	return &WiFi{box: newBox(image.Rectangle{Min: p, Max: p.Add(image.Point{X: IconSize, Y: IconSize})})}
+}
+
+// SetBars updates the strength from 0 (disconnected) to 4.
+func (w *WiFi) SetBars(bars int) {
+	if bars < 0 {
+		bars = 0
+	} else if bars > 4 {
+		bars = 4
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if bars != w.bars {
+		w.bars = bars
+		w.dirty = true
+	}
+}
+
+// SetRSSI converts a signal level in dBm to bars.
+func (w *WiFi) SetRSSI(dbm int) {
+	switch {
+	case dbm >= -55:
+		w.SetBars(4)
+	case dbm >= -66:
+		w.SetBars(3)
+	case dbm >= -77:
+		w.SetBars(2)
+	case dbm >= -88:
+		w.SetBars(1)
+	default:
+		w.SetBars(0)
+	}
+}
+
+// Draw renders lit bars and dots for the missing ones, a cross when
+// disconnected.
+func (w *WiFi) Draw(dst *image1bit.VerticalLSB) {
+	w.begin(dst)
+	defer w.end()
+	r := w.rect
+	if w.bars == 0 {
+		line(dst, r.Min.X+2, r.Min.Y+2, r.Max.X-3, r.Max.Y-3)
+		line(dst, r.Min.X+2, r.Max.Y-3, r.Max.X-3, r.Min.Y+2)
+		return
+	}
+	for i := 0; i < 4; i++ {
+		x := r.Min.X + i*3
+		top := r.Max.Y - 3*(i+1)
+		if i < w.bars {
+			fill(dst, image.Rect(x, top, x+2, r.Max.Y), image1bit.On)
+		} else {
+			fill(dst, image.Rect(x, r.Max.Y-1, x+2, r.Max.Y), image1bit.On)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"pi/dev"
+	"time"
+)
+
+// Event is an abstract input, produced by the joystick, an IR remote or the
+// keyboard when running on the emulator.
+type Event int
+
+const (
+	EventNone Event = iota
+	EventUp
+	EventDown
+	EventLeft
+	EventRight
+	EventSelect
+)
+
+var eventNames = [...]string{"none", "up", "down", "left", "right", "select"}
+
+func (e Event) String() string {
+	if e < 0 || int(e) >= len(eventNames) {
+		return "unknown"
+	}
+	return eventNames[e]
+}
+
+// JoystickReader is implemented by dev.Joystick.
+type JoystickReader interface {
+	Read() (dev.JoystickState, error)
+}
+
+// JoystickEvents polls j every interval and emits an event for each newly
+// pressed button until ctx is done.
+func JoystickEvents(ctx context.Context, j JoystickReader, interval time.Duration) <-chan Event {
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		var prev dev.JoystickState
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+			s, err := j.Read()
+			if err != nil {
+				continue
+			}
+			for _, b := range []struct {
+				now, was bool
+				ev       Event
+			}{
+				{s.Up, prev.Up, EventUp},
+				{s.Down, prev.Down, EventDown},
+				{s.Left, prev.Left, EventLeft},
+				{s.Right, prev.Right, EventRight},
+				{s.Press, prev.Press, EventSelect},
+			} {
+				if b.now && !b.was {
+					select {
+					case ch <- b.ev:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+			prev = s
+		}
+	}()
+	return ch
+}
+
+// KeyboardEvents reads keys from r until EOF: arrows or w/a/s/d move, enter
+// or space select and q or backspace go back (left). The terminal should be
+// in cbreak mode so keys arrive without enter.
+func KeyboardEvents(r io.Reader) <-chan Event {
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		br := bufio.NewReader(r)
+		for {
+			b, err := br.ReadByte()
+			if err != nil {
+				return
+			}
+			ev := EventNone
+			switch b {
+			case 'w', 'k':
+				ev = EventUp
+			case 's', 'j':
+				ev = EventDown
+			case 'a', 'h', 'q', 0x7f, 0x08:
+				ev = EventLeft
+			case 'd', 'l':
+				ev = EventRight
+			case '\r', '\n', ' ':
+				ev = EventSelect
+			case 0x1b:
+				// ESC [ A..D arrow sequences.
+				if next, err := br.ReadByte(); err != nil || next != '[' {
+					continue
+				}
+				code, err := br.ReadByte()
+				if err != nil {
+					return
+				}
+				switch code {
+				case 'A':
+					ev = EventUp
+				case 'B':
+					ev = EventDown
+				case 'C':
+					ev = EventRight
+				case 'D':
+					ev = EventLeft
+				}
+			}
+			if ev != EventNone {
+				ch <- ev
+			}
+		}
+	}()
+	return ch
+}
+
+// Waker is implemented by dev.PowerManager.
+type Waker interface {
+	Wake() (bool, error)
+}
+
+// WakeOn forwards events and wakes w on each of them, the event waking a
+// sleeping panel is dropped so it does not act on an unseen page.
+func WakeOn(events <-chan Event, w Waker) <-chan Event {
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		for ev := range events {
+			if woke, _ := w.Wake(); woke {
+				continue
+			}
+			ch <- ev
+		}
+	}()
+	return ch
+}
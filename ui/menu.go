@@ -0,0 +1,245 @@
+package ui
+
+import (
+	"context"
+	"image"
+	"pi/dev"
+	"strings"
+	"sync"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// Page is a full screen view on the Navigator stack.
+type Page interface {
+	// Draw renders the page on a blank frame.
+	Draw(dst *image1bit.VerticalLSB)
+	// Handle processes an input event, pages navigate through nav.
+	Handle(nav *Navigator, ev Event)
+}
+
+// Navigator is a stack of pages, the top one is shown and receives the
+// input events. Left on a page usually goes back.
+type Navigator struct {
+	mu      sync.Mutex
+	display dev.Display
+	frame   *image1bit.VerticalLSB
+	stack   []Page
+}
+
+// NewNavigator creates a new Navigator showing root on d.
+func NewNavigator(d dev.Display, root Page) *Navigator {
+	return &Navigator{
+		display: d,
+		frame:   image1bit.NewVerticalLSB(d.Bounds()),
+		stack:   []Page{root},
+	}
+}
+
+// Push shows p on top of the current page. Push and Pop are meant to be
+// called from Page.Handle or before Run.
+func (n *Navigator) Push(p Page) {
+	n.stack = append(n.stack, p)
+}
+
+// Pop goes back to the previous page, the root page is never removed.
+func (n *Navigator) Pop() {
+	if len(n.stack) > 1 {
+		n.stack = n.stack[:len(n.stack)-1]
+	}
+}
+
+// Top returns the page currently shown.
+func (n *Navigator) Top() Page {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.stack[len(n.stack)-1]
+}
+
+// Depth returns the number of pages on the stack.
+func (n *Navigator) Depth() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.stack)
+}
+
+// Handle dispatches ev to the top page and redraws.
+func (n *Navigator) Handle(ev Event) error {
+	n.mu.Lock()
+	n.stack[len(n.stack)-1].Handle(n, ev)
+	n.mu.Unlock()
+	return n.Render()
+}
+
+// Render draws the top page on the display.
+func (n *Navigator) Render() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	fill(n.frame, n.frame.Rect, image1bit.Off)
+	n.stack[len(n.stack)-1].Draw(n.frame)
+	return n.display.DrawFrame(n.frame)
+}
+
+// Run renders the top page and handles events until ctx is done or events
+// is closed.
+func (n *Navigator) Run(ctx context.Context, events <-chan Event) error {
+	if err := n.Render(); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := n.Handle(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// titleHeight is the height of the title bar including the separator.
+const titleHeight = dev.FontHeight + 2
+
+// drawTitle renders the title bar and returns the remaining area.
+func drawTitle(dst *image1bit.VerticalLSB, title string) image.Rectangle {
+	r := dst.Rect
+	text(dst, r, image.Point{X: r.Min.X + (r.Dx()-dev.TextWidth(title))/2, Y: r.Min.Y}, title)
+	line(dst, r.Min.X, r.Min.Y+titleHeight-2, r.Max.X-1, r.Min.Y+titleHeight-2)
+	r.Min.Y += titleHeight
+	return r
+}
+
+// MenuItem is an entry of a ListMenu.
+type MenuItem struct {
+	Label string
+	// Action runs on select, it typically pushes a page.
+	Action func(nav *Navigator)
+}
+
+// ListMenu is a scrollable list of items with a title.
+type ListMenu struct {
+	Title    string
+	Items    []MenuItem
+	selected int
+	offset   int
+}
+
+// NewListMenu creates a new ListMenu.
+func NewListMenu(title string, items ...MenuItem) *ListMenu {
+	return &ListMenu{Title: title, Items: items}
+}
+
+// Selected returns the index of the highlighted item.
+func (m *ListMenu) Selected() int {
+	return m.selected
+}
+
+// Draw renders the visible items, the selected one inverted, and a scroll
+// bar when the list does not fit.
+func (m *ListMenu) Draw(dst *image1bit.VerticalLSB) {
+	r := drawTitle(dst, m.Title)
+	rows := r.Dy() / dev.FontHeight
+	if rows < 1 {
+		return
+	}
+	m.clamp()
+	if m.selected < m.offset {
+		m.offset = m.selected
+	} else if m.selected >= m.offset+rows {
+		m.offset = m.selected - rows + 1
+	}
+	width := r.Dx()
+	if len(m.Items) > rows {
+		width -= 3
+		track := image.Rect(r.Max.X-2, r.Min.Y, r.Max.X, r.Max.Y)
+		thumb := track
+		thumb.Min.Y = r.Min.Y + r.Dy()*m.offset/len(m.Items)
+		thumb.Max.Y = r.Min.Y + r.Dy()*(m.offset+rows)/len(m.Items)
+		fill(dst, thumb, image1bit.On)
+	}
+	for i := 0; i < rows && m.offset+i < len(m.Items); i++ {
+		row := image.Rect(r.Min.X, r.Min.Y+i*dev.FontHeight, r.Min.X+width, r.Min.Y+(i+1)*dev.FontHeight)
+		text(dst, row, image.Point{X: row.Min.X + 2, Y: row.Min.Y}, m.Items[m.offset+i].Label)
+		if m.offset+i == m.selected {
+			invert(dst, row)
+		}
+	}
+}
+
+// Handle moves the selection with up/down, runs the item on select or right
+// and goes back on left.
+func (m *ListMenu) Handle(nav *Navigator, ev Event) {
+	if ev == EventLeft {
+		nav.Pop()
+		return
+	}
+	if len(m.Items) == 0 {
+		return
+	}
+	m.clamp()
+	switch ev {
+	case EventUp:
+		if m.selected > 0 {
+			m.selected--
+		} else {
+			m.selected = len(m.Items) - 1
+		}
+	case EventDown:
+		if m.selected < len(m.Items)-1 {
+			m.selected++
+		} else {
+			m.selected = 0
+		}
+	case EventSelect, EventRight:
+		if m.Items[m.selected].Action != nil {
+			m.Items[m.selected].Action(nav)
+		}
+	}
+}
+
+// clamp keeps the selection within the items, which may have changed.
+func (m *ListMenu) clamp() {
+	if m.selected >= len(m.Items) {
+		m.selected = len(m.Items) - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+// wrap splits s into lines of at most width pixels on word boundaries.
+func wrap(s string, width int) []string {
+	max := width / dev.FontWidth
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		cur := ""
+		for _, word := range strings.Fields(para) {
+			switch {
+			case cur == "":
+				cur = word
+			case len([]rune(cur))+1+len([]rune(word)) <= max:
+				cur += " " + word
+			default:
+				lines = append(lines, cur)
+				cur = word
+			}
+		}
+		lines = append(lines, cur)
+	}
+	return lines
+}
+
+// drawCentered draws each line horizontally centered from y.
+func drawCentered(dst *image1bit.VerticalLSB, r image.Rectangle, y int, lines []string) {
+	for i, l := range lines {
+		p := image.Point{X: r.Min.X + (r.Dx()-dev.TextWidth(l))/2, Y: y + i*dev.FontHeight}
+		text(dst, r, p, l)
+	}
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNavigatorMenu(t *testing.T) {
+	d := &fakeDisplay{}
+	contrast := 0.0
+	toggled := false
+	confirmed := false
+	spinner := &Spinner{Title: "Contrast", Value: 200, Min: 0, Max: 255, Step: 50, OnDone: func(v float64) { contrast = v }}
+	toggle := &Toggle{Title: "LED1", OnDone: func(v bool) { toggled = v }}
+	root := NewListMenu("Menu",
+		MenuItem{Label: "Contrast", Action: func(n *Navigator) { n.Push(spinner) }},
+		MenuItem{Label: "LED1", Action: func(n *Navigator) { n.Push(toggle) }},
+		MenuItem{Label: "Reboot", Action: func(n *Navigator) {
+			n.Push(&Confirm{Message: "Reboot the board now?", OnDone: func(yes bool) { confirmed = yes }})
+		}},
+		MenuItem{Label: "About"},
+	)
+	nav := NewNavigator(d, root)
+
+	events := make(chan Event)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- nav.Run(ctx, events) }()
+	send := func(evs ...Event) {
+		for _, ev := range evs {
+			events <- ev
+		}
+	}
+
+	// Contrast: 200 + 50 clamps at 255.
+	send(EventSelect, EventUp, EventUp, EventSelect)
+	// LED1: flip on and confirm.
+	send(EventDown, EventSelect, EventRight, EventSelect)
+	// Reboot: answer yes.
+	send(EventDown, EventSelect, EventRight, EventSelect)
+	// Left on the root menu stays there.
+	send(EventLeft)
+	close(events)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if contrast != 255 || !toggled || !confirmed {
+		t.Fatalf("contrast=%v toggled=%v confirmed=%v", contrast, toggled, confirmed)
+	}
+	if nav.Depth() != 1 || root.Selected() != 2 {
+		t.Fatalf("depth=%d selected=%d", nav.Depth(), root.Selected())
+	}
+	if d.frames != 14 {
+		t.Fatalf("rendered %d frames, want 14", d.frames)
+	}
+}
+
+func TestListMenuScroll(t *testing.T) {
+	d := &fakeDisplay{}
+	var items []MenuItem
+	for _, l := range strings.Fields("a b c d e f") {
+		items = append(items, MenuItem{Label: l})
+	}
+	m := NewListMenu("Scroll", items...)
+	nav := NewNavigator(d, m)
+	for i := 0; i < 5; i++ {
+		nav.Handle(EventDown)
+	}
+	if m.Selected() != 5 || m.offset != 3 {
+		t.Fatalf("selected=%d offset=%d", m.Selected(), m.offset)
+	}
+	nav.Handle(EventDown)
+	if m.Selected() != 0 || m.offset != 0 {
+		t.Fatalf("wrap: selected=%d offset=%d", m.Selected(), m.offset)
+	}
+}
+
+func TestTimeSetter(t *testing.T) {
+	d := &fakeDisplay{}
+	var got time.Time
+	start := time.Date(2020, 6, 1, 23, 59, 0, 0, time.UTC)
+	ts := &TimeSetter{Title: "Time", Value: start, OnDone: func(v time.Time) { got = v }}
+	nav := NewNavigator(d, NewListMenu("Root"))
+	nav.Push(ts)
+	// hours 23 -> 0, minutes 59 -> 58, seconds 0 -> 59.
+	for _, ev := range []Event{EventUp, EventRight, EventDown, EventRight, EventDown, EventSelect} {
+		nav.Handle(ev)
+	}
+	want := time.Date(2020, 6, 1, 0, 58, 59, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if nav.Depth() != 1 {
+		t.Fatal("time setter not popped")
+	}
+}
+
+func TestKeyboardEvents(t *testing.T) {
+	var got []Event
+	for ev := range KeyboardEvents(strings.NewReader("wsad\n\x1b[A\x1b[Bxq")) {
+		got = append(got, ev)
+	}
+	want := []Event{EventUp, EventDown, EventLeft, EventRight, EventSelect, EventUp, EventDown, EventLeft}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestListMenuEmpty(t *testing.T) {
+	d := &fakeDisplay{}
+	m := NewListMenu("Empty")
+	nav := NewNavigator(d, m)
+	for _, ev := range []Event{EventUp, EventSelect, EventDown, EventRight} {
+		nav.Handle(ev)
+	}
+	if m.Selected() != 0 {
+		t.Errorf("selected=%d", m.Selected())
+	}
+}
@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"image"
+	"pi/dev"
+	"sync"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// Widget is a self drawing element of a Screen.
+type Widget interface {
+	// Bounds returns the panel area owned by the widget.
+	Bounds() image.Rectangle
+	// Dirty reports whether the widget changed since it was last drawn.
+	Dirty() bool
+	// Draw renders the widget inside Bounds and clears the dirty flag.
+	Draw(dst *image1bit.VerticalLSB)
+}
+
+// box holds the bounds and dirty flag shared by the widgets.
+type box struct {
+	mu    sync.Mutex
+	rect  image.Rectangle
+	dirty bool
+}
+
+func newBox(r image.Rectangle) box {
+	return box{rect: r, dirty: true}
+}
+
+// Bounds returns the panel area owned by the widget.
+func (b *box) Bounds() image.Rectangle {
+	return b.rect
+}
+
+// Dirty reports whether the widget changed since it was last drawn.
+func (b *box) Dirty() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dirty
+}
+
+// begin locks the widget and blanks its area for drawing.
+func (b *box) begin(dst *image1bit.VerticalLSB) {
+	b.mu.Lock()
+	fill(dst, b.rect, image1bit.Off)
+}
+
+// end clears the dirty flag and unlocks the widget.
+func (b *box) end() {
+	b.dirty = false
+	b.mu.Unlock()
+}
+
+// Screen composes widgets on a Display.
+type Screen struct {
+	mu      sync.Mutex
+	display dev.Display
+	frame   *image1bit.VerticalLSB
+	widgets []Widget
+	full    bool
+}
+
+// NewScreen creates a new Screen drawing on d.
+func NewScreen(d dev.Display) *Screen {
+	return &Screen{
+		display: d,
+		frame:   image1bit.NewVerticalLSB(d.Bounds()),
+		full:    true,
+	}
+}
+
+// Add appends widgets to the screen, later widgets draw on top.
+func (s *Screen) Add(w ...Widget) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.widgets = append(s.widgets, w...)
+	s.full = true
+}
+
+// Invalidate forces every widget to be redrawn on the next Render.
+func (s *Screen) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.full = true
+}
+
+// Render redraws the widgets whose value changed and sends the frame to the
+// display, it does nothing when no widget is dirty.
+func (s *Screen) Render() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := s.full
+	if s.full {
+		fill(s.frame, s.frame.Rect, image1bit.Off)
+	}
+	for _, w := range s.widgets {
+		if s.full || w.Dirty() {
+			w.Draw(s.frame)
+			changed = true
+		}
+	}
+	s.full = false
+	if !changed {
+		return nil
+	}
+	return s.display.DrawFrame(s.frame)
+}
+
+// Frame returns the frame last rendered, it must not be modified.
+func (s *Screen) Frame() *image1bit.VerticalLSB {
+	return s.frame
+}
+
+// SplitH splits r into columns proportional to weights.
+func SplitH(r image.Rectangle, weights ...int) []image.Rectangle {
+	out := make([]image.Rectangle, 0, len(weights))
+	edges := split(r.Min.X, r.Max.X, weights)
+	for i := range weights {
+		out = append(out, image.Rect(edges[i], r.Min.Y, edges[i+1], r.Max.Y))
+	}
+	return out
+}
+
+// SplitV splits r into rows proportional to weights.
+func SplitV(r image.Rectangle, weights ...int) []image.Rectangle {
+	out := make([]image.Rectangle, 0, len(weights))
+	edges := split(r.Min.Y, r.Max.Y, weights)
+	for i := range weights {
+		out = append(out, image.Rect(r.Min.X, edges[i], r.Max.X, edges[i+1]))
+	}
+	return out
+}
+
+// split returns the len(weights)+1 edges dividing [min, max).
+func split(min, max int, weights []int) []int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	edges := make([]int, len(weights)+1)
+	edges[0] = min
+	acc := 0
+	for i, w := range weights {
+		acc += w
+		if total > 0 {
+			edges[i+1] = min + (max-min)*acc/total
+		} else {
+			edges[i+1] = min
+		}
+	}
+	return edges
+}
@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"periph.io/x/periph/devices/ssd1306/image1bit"
+)
+
+// fakeDisplay counts the frames it receives.
+type fakeDisplay struct {
+	frames int
+	last   *image1bit.VerticalLSB
+}
+
+func (d *fakeDisplay) Bounds() image.Rectangle { return image.Rect(0, 0, 128, 64) }
+func (d *fakeDisplay) DrawFrame(f *image1bit.VerticalLSB) error {
+	d.frames++
+	d.last = f
+	return nil
+}
+func (d *fakeDisplay) SetContrast(byte) error { return nil }
+func (d *fakeDisplay) On() error              { return nil }
+func (d *fakeDisplay) Off() error             { return nil }
+func (d *fakeDisplay) Halt() error            { return nil }
+
+func lit(f *image1bit.VerticalLSB, r image.Rectangle) int {
+	n := 0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if f.BitAt(x, y) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestScreenRedrawsOnlyOnChange(t *testing.T) {
+	d := &fakeDisplay{}
+	s := NewScreen(d)
+	rows := SplitV(d.Bounds(), 1, 1)
+	temp := NewReadout(rows[0], "T", "C", 1)
+	bar := NewBar(rows[1], Horizontal, 0, 100)
+	s.Add(temp, bar)
+
+	if err := s.Render(); err != nil {
+		t.Fatal(err)
+	}
+	if d.frames != 1 {
+		t.Fatalf("first render sent %d frames, want 1", d.frames)
+	}
+	s.Render()
+	if d.frames != 1 {
+		t.Fatalf("idle render sent a frame")
+	}
+	temp.SetValue(23.51)
+	temp.SetValue(23.54) // same text
+	if !temp.Dirty() || bar.Dirty() {
+		t.Fatal("only the readout should be dirty")
+	}
+	s.Render()
+	if d.frames != 2 {
+		t.Fatalf("got %d frames, want 2", d.frames)
+	}
+	temp.SetValue(23.5)
+	if temp.Dirty() {
+		t.Fatal("readout dirty although its text did not change")
+	}
+}
+
+func TestBar(t *testing.T) {
+	d := &fakeDisplay{}
+	s := NewScreen(d)
+	r := image.Rect(0, 0, 102, 10)
+	bar := NewBar(r, Horizontal, 0, 100)
+	s.Add(bar)
+	bar.SetValue(50)
+	s.Render()
+	// Outline is 2*102 + 2*8 pixels, fill is 50 columns of 8.
+	if n := lit(d.last, r); n != 2*102+2*8+50*8 {
+		t.Fatalf("lit %d pixels", n)
+	}
+	bar.SetValue(500)
+	s.Render()
+	if n := lit(d.last, r.Inset(1)); n != 100*8 {
+		t.Fatalf("overflow lit %d interior pixels, want full", n)
+	}
+	v := NewBar(image.Rect(110, 0, 120, 64), Vertical, -10, 10)
+	s.Add(v)
+	v.SetValue(-10)
+	s.Render()
+	if n := lit(d.last, image.Rect(111, 1, 119, 63)); n != 0 {
+		t.Fatalf("empty vertical bar lit %d pixels", n)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	d := &fakeDisplay{}
+	s := NewScreen(d)
+	r := image.Rect(0, 0, 10, 8)
+	sp := NewSparkline(r)
+	s.Add(sp)
+	for i := 0; i < 25; i++ {
+		sp.Push(float64(i))
+	}
+	if got := sp.Samples(); len(got) != 10 || got[0] != 15 {
+		t.Fatalf("samples = %v", got)
+	}
+	s.Render()
+	// A rising line: oldest sample at the bottom left, newest top right.
+	if !d.last.BitAt(0, 7) || !d.last.BitAt(9, 0) {
+		t.Fatal("sparkline is not a rising line")
+	}
+}
+
+func TestProgressAndIcons(t *testing.T) {
+	d := &fakeDisplay{}
+	s := NewScreen(d)
+	p := NewProgressBar(image.Rect(0, 0, 60, 15))
+	icon := NewIcon(image.Pt(70, 0), IconNone)
+	batt := NewBattery(image.Pt(90, 0))
+	wifi := NewWiFi(image.Pt(110, 0))
+	clock := NewClock(image.Rect(0, 16, 48, 64), true)
+	s.Add(p, icon, batt, wifi, clock)
+	s.Render()
+
+	icon.SetIcon(IconWarning)
+	batt.SetLevel(80, true)
+	wifi.SetRSSI(-60)
+	p.SetProgress(0.5)
+	clock.SetTime(time.Date(2020, 6, 1, 10, 10, 30, 0, time.UTC))
+	for _, w := range []Widget{p, icon, batt, wifi, clock} {
+		if !w.Dirty() {
+			t.Errorf("%T not dirty after update", w)
+		}
+	}
+	s.Render()
+	for _, w := range []Widget{icon, batt, wifi, clock} {
+		if lit(d.last, w.Bounds()) == 0 {
+			t.Errorf("%T drew nothing", w)
+		}
+	}
+	clock.SetTime(time.Date(2020, 6, 1, 10, 10, 30, 500, time.UTC))
+	if clock.Dirty() {
+		t.Error("clock dirty within the same second")
+	}
+	batt.SetLevel(81, true)
+	if batt.Dirty() {
+		t.Error("battery dirty for an invisible change")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	cols := SplitH(image.Rect(0, 0, 128, 64), 1, 2, 1)
+	want := []image.Rectangle{
+		image.Rect(0, 0, 32, 64),
+		image.Rect(32, 0, 96, 64),
+		image.Rect(96, 0, 128, 64),
+	}
+	for i := range want {
+		if cols[i] != want[i] {
+			t.Errorf("column %d = %v, want %v", i, cols[i], want[i])
+		}
+	}
+}